@@ -7,7 +7,9 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/JacobD36/appfe_frontpage_api/internal/adapter/cookie"
 	"github.com/JacobD36/appfe_frontpage_api/internal/adapter/messaging"
+	"github.com/JacobD36/appfe_frontpage_api/internal/adapter/middleware"
 	"github.com/JacobD36/appfe_frontpage_api/internal/adapter/router"
 	"github.com/JacobD36/appfe_frontpage_api/internal/adapter/security"
 	"github.com/JacobD36/appfe_frontpage_api/internal/adapter/storage"
@@ -39,25 +41,57 @@ func init() {
 	}
 }
 
-func initKeys() {
+func initKeys() *security.KeyRing {
 	ctx := context.Background()
-	privateKeyPath := os.Getenv("RSA_PRIVATE_KEY_PATH")
-	publicKeyPath := os.Getenv("RSA_PUBLIC_KEY_PATH")
-	if privateKeyPath == "" || publicKeyPath == "" {
+	source := security.KeySource{
+		Dir:         os.Getenv(dto.EnvJWTKeysDir),
+		ActiveKID:   os.Getenv(dto.EnvJWTActiveKID),
+		PrivateFile: os.Getenv("RSA_PRIVATE_KEY_PATH"),
+		PublicFile:  os.Getenv("RSA_PUBLIC_KEY_PATH"),
+	}
+	if source.Dir == "" && (source.PrivateFile == "" || source.PublicFile == "") {
 		logger.Fatal(ctx, dto.ErrRSAKeysNotSet)
 	}
 
 	logger.Info(ctx, dto.MsgLoadingRSAKeys,
-		logger.String("private_key_path", privateKeyPath),
-		logger.String("public_key_path", publicKeyPath),
+		logger.String("keys_dir", source.Dir),
+		logger.String("private_key_path", source.PrivateFile),
+		logger.String("public_key_path", source.PublicFile),
 	)
 
-	err := security.LoadFiles(privateKeyPath, publicKeyPath)
+	keyRing, err := security.NewKeyRing(source)
 	if err != nil {
 		logger.Fatal(ctx, dto.ErrFailedLoadRSAKeys, logger.Error("error", err))
 	}
 
-	logger.Info(ctx, dto.MsgRSAKeysLoadedSuccess)
+	activeKID, _ := keyRing.Signer()
+	logger.Info(ctx, dto.MsgRSAKeysLoadedSuccess, logger.String("active_kid", activeKID))
+
+	return keyRing
+}
+
+// watchKeyReload recarga las claves RSA al recibir SIGHUP, sin reiniciar el servidor
+func watchKeyReload(ctx context.Context, keyRing *security.KeyRing) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				logger.Info(ctx, dto.MsgRSAKeysReloadRequested)
+				if err := keyRing.Reload(); err != nil {
+					logger.LogError(ctx, dto.ErrFailedReloadRSAKeys, logger.Error("error", err))
+					continue
+				}
+				activeKID, _ := keyRing.Signer()
+				logger.Info(ctx, dto.MsgRSAKeysLoadedSuccess, logger.String("active_kid", activeKID))
+			}
+		}
+	}()
 }
 
 func main() {
@@ -76,14 +110,27 @@ func main() {
 	logger.Info(ctx, dto.MsgInitializingDBConnection, logger.String("driver", string(driver)))
 	storage.New(driver)
 
-	initKeys()
+	keyRing := initKeys()
+	watchKeyReload(signalCtx, keyRing)
 
 	uowFactory, err := storage.UoWFactory(driver)
 	if err != nil {
 		logger.Fatal(ctx, dto.ErrUnitOfWorkFactory, logger.Error("error", err))
 	}
 
-	hasher := security.NewBcryptHasher(12)
+	hasher, err := security.NewPasswordHasherFromEnv()
+	if err != nil {
+		logger.Fatal(ctx, dto.ErrPasswordHasherConfig, logger.Error("error", err))
+	}
+	breachChecker, err := security.NewPasswordBreachCheckerFromEnv()
+	if err != nil {
+		logger.Fatal(ctx, dto.ErrFailedLoadBreachFile, logger.Error("error", err))
+	}
+	if breachChecker != nil {
+		logger.Info(ctx, dto.MsgPasswordBreachCheckEnabled, logger.String("file", os.Getenv(dto.EnvPasswordBreachFile)))
+	}
+
+	passwordPolicy := usecase.NewPasswordPolicy(usecase.NewPasswordPolicyConfigFromEnv(), hasher, breachChecker)
 
 	// Inicializar servicio de mensajería
 	var messagingService interfaces.MessagingService
@@ -103,7 +150,16 @@ func main() {
 	templateService := template.NewHTMLTemplateService()
 	logger.Info(ctx, dto.MsgTemplateServiceInitialized)
 
-	userService := usecase.NewUserService(uowFactory, hasher, messagingService, templateService)
+	tokenGenerator := security.NewSecureTokenGenerator()
+	authConfig := usecase.NewAuthConfigFromEnv()
+
+	jwtService, err := security.NewJWTService(keyRing, authConfig.AccessTokenTTL)
+	if err != nil {
+		logger.Fatal(ctx, dto.ErrTokenGenerationFailed, logger.Error("error", err))
+	}
+
+	auditService := usecase.NewAuditService(uowFactory)
+	userService := usecase.NewUserService(uowFactory, hasher, messagingService, templateService, tokenGenerator, jwtService, passwordPolicy, auditService, authConfig)
 
 	logger.Info(ctx, dto.MsgRunningDBMigrations)
 	migrationService := usecase.NewMigrationService(uowFactory, userService)
@@ -112,14 +168,63 @@ func main() {
 	}
 	logger.Info(ctx, dto.MsgDBMigrationsCompleted)
 
-	jwtService, err := security.NewJWTService()
+	revocationService := usecase.NewTokenRevocationService(uowFactory)
+	if err := revocationService.Start(signalCtx, usecase.DefaultTokenRevocationSyncInterval); err != nil {
+		logger.Fatal(ctx, dto.ErrTokenRevocationInitFailed, logger.Error("error", err))
+	}
+
+	loginAttemptRetention := usecase.NewLoginAttemptRetentionService(uowFactory, authConfig)
+	if err := loginAttemptRetention.Start(signalCtx, usecase.DefaultLoginAttemptPruneInterval); err != nil {
+		logger.Warn(ctx, dto.MsgLoginAttemptPruneFailed, logger.Error("error", err))
+	}
+
+	oidcProvider, err := security.NewOIDCProviderFromEnv()
 	if err != nil {
-		logger.Fatal(ctx, dto.ErrTokenGenerationFailed, logger.Error("error", err))
+		logger.Fatal(ctx, dto.ErrOIDCConfigInvalid, logger.Error("error", err))
 	}
+	if oidcProvider != nil {
+		logger.Info(ctx, dto.MsgOIDCEnabled, logger.String("issuer", os.Getenv(dto.EnvOIDCIssuerURL)))
+	}
+
+	twoFactorProvider := security.NewTOTPProvider(dto.TwoFactorIssuer)
+	authService := usecase.NewAuthService(uowFactory, hasher, jwtService, tokenGenerator, messagingService, templateService, revocationService, twoFactorProvider, oidcProvider, passwordPolicy, auditService, authConfig)
+	twoFactorService := usecase.NewTwoFactorService(uowFactory, twoFactorProvider, tokenGenerator, authConfig)
+	profileService := usecase.NewProfileService(uowFactory, hasher, passwordPolicy, auditService)
 
-	authService := usecase.NewAuthService(uowFactory, hasher, jwtService)
+	tokenVersionService := usecase.NewTokenVersionService(uowFactory, usecase.DefaultTokenVersionCacheTTL)
+	permissionService := usecase.NewPermissionService(uowFactory, usecase.DefaultPermissionCacheTTL)
+	sessionService := usecase.NewSessionService(uowFactory, auditService, usecase.DefaultSessionCacheTTL)
+	roleService := usecase.NewRoleService(uowFactory, permissionService, auditService)
+	apiKeyService := usecase.NewAPIKeyService(uowFactory, tokenGenerator, auditService, usecase.DefaultAPIKeyCacheTTL)
 
-	r := router.New(userService, authService, jwtService)
+	sessionCookies := cookie.NewSessionCookieConfigFromEnv(authConfig.AccessTokenTTL, authConfig.RefreshTokenTTL)
+	if sessionCookies.Enabled && len(sessionCookies.CSRFSecret) < cookie.MinCSRFSecretLength {
+		logger.Fatal(ctx, dto.ErrCSRFSecretNotSet)
+	}
+
+	r := router.New(
+		router.Handlers{
+			User:      userService,
+			Auth:      authService,
+			TwoFactor: twoFactorService,
+			Profile:   profileService,
+			Role:      roleService,
+			Audit:     auditService,
+			APIKey:    apiKeyService,
+			Session:   sessionService,
+		},
+		router.Options{
+			Tokens:                jwtService,
+			TokenRevocation:       revocationService,
+			TokenVersions:         tokenVersionService,
+			Permissions:           permissionService,
+			RateLimitStore:        middleware.NewMemoryRateLimitStore(),
+			RateLimits:            middleware.NewRateLimitConfigFromEnv(),
+			RequireAdminTwoFactor: authConfig.RequireAdminTwoFactor,
+			IPExtractor:           middleware.NewIPExtractorFromEnv(),
+			SessionCookies:        sessionCookies,
+		},
+	)
 
 	logger.Info(ctx, dto.MsgServicesInitialized)
 
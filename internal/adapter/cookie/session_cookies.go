@@ -0,0 +1,242 @@
+package cookie
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	AccessTokenCookie  = "appfe_access"
+	RefreshTokenCookie = "appfe_refresh"
+	CSRFTokenCookie    = "appfe_csrf"
+
+	// OIDCStateCookie liga el inicio de sesión OIDC en curso al navegador que lo inició
+	OIDCStateCookie = "appfe_oidc_state"
+
+	// CSRFTokenHeader es el header en el que el cliente repite el valor de la cookie CSRF (double-submit)
+	CSRFTokenHeader = "X-CSRF-Token"
+
+	// SessionModeHeader con el valor SessionModeCookie pide que el login entregue los tokens en cookies
+	SessionModeHeader = "X-Session-Mode"
+	SessionModeCookie = "cookie"
+
+	// La cookie de acceso viaja a toda la API; la de refresh solo a /auth, donde están refresh y logout.
+	// La cookie CSRF usa "/" para que el portal pueda leerla desde cualquier página.
+	accessTokenCookiePath  = "/api/v1"
+	refreshTokenCookiePath = "/api/v1/auth"
+	csrfTokenCookiePath    = "/"
+	oidcStateCookiePath    = "/api/v1/auth/oidc"
+
+	// MinCSRFSecretLength es el largo mínimo de CSRF_SECRET con el modo cookie habilitado
+	MinCSRFSecretLength = 32
+)
+
+// SessionCookieConfig configura el modo de sesión por cookies para el portal web
+type SessionCookieConfig struct {
+	Enabled  bool
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+
+	// AllowedOrigins son los orígenes del portal a los que CORS permite enviar credenciales
+	AllowedOrigins []string
+
+	// CSRFSecret firma los tokens CSRF; debe ser igual en todas las instancias de la API
+	CSRFSecret []byte
+
+	// La vigencia de las cookies sigue a la de los tokens que transportan
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// NewSessionCookieConfigFromEnv lee la configuración de cookies; el modo está deshabilitado por defecto
+func NewSessionCookieConfigFromEnv(accessTokenTTL, refreshTokenTTL time.Duration) SessionCookieConfig {
+	config := SessionCookieConfig{
+		Domain:          os.Getenv(dto.EnvCookieDomain),
+		CSRFSecret:      []byte(os.Getenv(dto.EnvCSRFSecret)),
+		Secure:          true,
+		SameSite:        http.SameSiteStrictMode,
+		AccessTokenTTL:  accessTokenTTL,
+		RefreshTokenTTL: refreshTokenTTL,
+	}
+
+	config.Enabled, _ = strconv.ParseBool(os.Getenv(dto.EnvSessionCookies))
+
+	if secure, err := strconv.ParseBool(os.Getenv(dto.EnvCookieSecure)); err == nil {
+		config.Secure = secure
+	}
+
+	switch strings.ToLower(os.Getenv(dto.EnvCookieSameSite)) {
+	case "lax":
+		config.SameSite = http.SameSiteLaxMode
+	case "none":
+		// Los navegadores descartan las cookies SameSite=None que no son Secure
+		config.SameSite = http.SameSiteNoneMode
+		config.Secure = true
+	}
+
+	for _, origin := range strings.Split(os.Getenv(dto.EnvCORSAllowedOrigins), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			config.AllowedOrigins = append(config.AllowedOrigins, origin)
+		}
+	}
+
+	return config
+}
+
+// SessionCookies escribe y lee las cookies de sesión y valida el token CSRF de las peticiones que las usan
+type SessionCookies struct {
+	config SessionCookieConfig
+}
+
+func NewSessionCookies(config SessionCookieConfig) *SessionCookies {
+	return &SessionCookies{config: config}
+}
+
+func (s *SessionCookies) Enabled() bool {
+	return s.config.Enabled
+}
+
+// Requested indica si el cliente pidió recibir la sesión en cookies y el modo está habilitado
+func (s *SessionCookies) Requested(c echo.Context) bool {
+	return s.config.Enabled && strings.EqualFold(c.Request().Header.Get(SessionModeHeader), SessionModeCookie)
+}
+
+// SetSession guarda los tokens en cookies HttpOnly y emite el token CSRF de la sesión, que además se devuelve
+// en el header X-CSRF-Token. Sin refresh token (sesión restringida) se elimina el anterior.
+func (s *SessionCookies) SetSession(c echo.Context, sessionID, accessToken, refreshToken string) {
+	csrfToken := s.csrfToken(sessionID)
+
+	c.SetCookie(s.cookie(AccessTokenCookie, accessToken, accessTokenCookiePath, s.config.AccessTokenTTL, true))
+
+	if refreshToken != "" {
+		c.SetCookie(s.cookie(RefreshTokenCookie, refreshToken, refreshTokenCookiePath, s.config.RefreshTokenTTL, true))
+	} else {
+		c.SetCookie(s.cookie(RefreshTokenCookie, "", refreshTokenCookiePath, -1, true))
+	}
+
+	c.SetCookie(s.cookie(CSRFTokenCookie, csrfToken, csrfTokenCookiePath, s.config.RefreshTokenTTL, false))
+	c.Response().Header().Set(CSRFTokenHeader, csrfToken)
+}
+
+// Clear elimina las cookies de sesión
+func (s *SessionCookies) Clear(c echo.Context) {
+	c.SetCookie(s.cookie(AccessTokenCookie, "", accessTokenCookiePath, -1, true))
+	c.SetCookie(s.cookie(RefreshTokenCookie, "", refreshTokenCookiePath, -1, true))
+	c.SetCookie(s.cookie(CSRFTokenCookie, "", csrfTokenCookiePath, -1, false))
+}
+
+// SetOIDCState guarda el state del flujo OIDC en una cookie HttpOnly. A diferencia de las cookies de sesión
+// no depende de SESSION_COOKIES: el callback se rechaza si no llega desde el navegador que la tiene.
+func (s *SessionCookies) SetOIDCState(c echo.Context, state string, ttl time.Duration) {
+	c.SetCookie(s.cookie(OIDCStateCookie, state, oidcStateCookiePath, ttl, true))
+}
+
+// OIDCState retorna el state de la cookie del flujo OIDC, o "" si no existe
+func (s *SessionCookies) OIDCState(c echo.Context) string {
+	cookie, err := c.Cookie(OIDCStateCookie)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// ClearOIDCState elimina la cookie del flujo OIDC; cada state solo sirve para un callback
+func (s *SessionCookies) ClearOIDCState(c echo.Context) {
+	c.SetCookie(s.cookie(OIDCStateCookie, "", oidcStateCookiePath, -1, true))
+}
+
+// AccessToken retorna el token de la cookie de acceso, o "" si el modo está deshabilitado o no existe
+func (s *SessionCookies) AccessToken(c echo.Context) string {
+	return s.read(c, AccessTokenCookie)
+}
+
+// RefreshToken retorna el refresh token de su cookie, o "" si el modo está deshabilitado o no existe
+func (s *SessionCookies) RefreshToken(c echo.Context) string {
+	return s.read(c, RefreshTokenCookie)
+}
+
+// ValidCSRF indica si la petición trae el token CSRF de la sesión sessionID. Los métodos seguros no lo necesitan.
+func (s *SessionCookies) ValidCSRF(c echo.Context, sessionID string) bool {
+	switch c.Request().Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	csrfSessionID := s.CSRFSessionID(c)
+	return csrfSessionID != "" && csrfSessionID == sessionID
+}
+
+// CSRFSessionID valida el token CSRF de la petición y retorna la sesión a la que pertenece, o "" si el
+// header X-CSRF-Token falta, no coincide con la cookie o no fue firmado por la API.
+func (s *SessionCookies) CSRFSessionID(c echo.Context) string {
+	cookie := s.read(c, CSRFTokenCookie)
+	header := c.Request().Header.Get(CSRFTokenHeader)
+	if cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) != 1 {
+		return ""
+	}
+
+	sessionID, _, ok := strings.Cut(header, ".")
+	if !ok || sessionID == "" || !hmac.Equal([]byte(header), []byte(s.csrfToken(sessionID))) {
+		return ""
+	}
+
+	return sessionID
+}
+
+// CORSAllowedOrigins son los orígenes con credenciales habilitadas. No depende del modo cookie porque el
+// flujo OIDC también necesita que el portal envíe su cookie de state.
+func (s *SessionCookies) CORSAllowedOrigins() []string {
+	return s.config.AllowedOrigins
+}
+
+func (s *SessionCookies) read(c echo.Context, name string) string {
+	if !s.config.Enabled {
+		return ""
+	}
+
+	cookie, err := c.Cookie(name)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// cookie arma una cookie con los atributos configurados; ttl negativo la elimina
+func (s *SessionCookies) cookie(name, value, path string, ttl time.Duration, httpOnly bool) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   s.config.Domain,
+		Secure:   s.config.Secure,
+		HttpOnly: httpOnly,
+		SameSite: s.config.SameSite,
+	}
+
+	if ttl < 0 {
+		cookie.MaxAge = -1
+	} else {
+		cookie.MaxAge = int(ttl.Seconds())
+	}
+
+	return cookie
+}
+
+// csrfToken retorna "<sessionID>.<HMAC-SHA256 del sessionID>": el token queda ligado a la sesión y solo la
+// API puede emitirlo, de modo que una cookie CSRF inyectada por un subdominio no sirve para otra sesión
+func (s *SessionCookies) csrfToken(sessionID string) string {
+	mac := hmac.New(sha256.New, s.config.CSRFSecret)
+	mac.Write([]byte(sessionID))
+	return sessionID + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
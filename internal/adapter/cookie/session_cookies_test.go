@@ -0,0 +1,134 @@
+package cookie
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func newTestSessionCookies(secret string) *SessionCookies {
+	return NewSessionCookies(SessionCookieConfig{
+		Enabled:         true,
+		Secure:          true,
+		SameSite:        http.SameSiteStrictMode,
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: time.Hour,
+		CSRFSecret:      []byte(secret),
+	})
+}
+
+func TestSessionCookies_SetSession(t *testing.T) {
+	sessions := newTestSessionCookies("test-csrf-secret-with-32-characters")
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil), rec)
+
+	sessions.SetSession(c, "session-1", "access", "refresh")
+
+	cookies := map[string]*http.Cookie{}
+	for _, cookie := range rec.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		path     string
+		maxAge   int
+		httpOnly bool
+	}{
+		{name: AccessTokenCookie, value: "access", path: accessTokenCookiePath, maxAge: 900, httpOnly: true},
+		{name: RefreshTokenCookie, value: "refresh", path: refreshTokenCookiePath, maxAge: 3600, httpOnly: true},
+		{name: CSRFTokenCookie, path: csrfTokenCookiePath, maxAge: 3600},
+	}
+
+	for _, tt := range tests {
+		cookie, ok := cookies[tt.name]
+		if !ok {
+			t.Fatalf("cookie %s not set", tt.name)
+		}
+		if tt.value != "" && cookie.Value != tt.value {
+			t.Errorf("%s value = %q, want %q", tt.name, cookie.Value, tt.value)
+		}
+		if cookie.Path != tt.path || cookie.MaxAge != tt.maxAge || cookie.HttpOnly != tt.httpOnly {
+			t.Errorf("%s = path %q maxAge %d httpOnly %v", tt.name, cookie.Path, cookie.MaxAge, cookie.HttpOnly)
+		}
+		if !cookie.Secure || cookie.SameSite != http.SameSiteStrictMode {
+			t.Errorf("%s must be Secure and SameSite=Strict", tt.name)
+		}
+	}
+
+	csrf := cookies[CSRFTokenCookie].Value
+	if csrf != sessions.csrfToken("session-1") || rec.Header().Get(CSRFTokenHeader) != csrf {
+		t.Errorf("csrf header = %q, cookie = %q, want the token of session-1", rec.Header().Get(CSRFTokenHeader), csrf)
+	}
+}
+
+func TestSessionCookies_CSRFSessionID(t *testing.T) {
+	sessions := newTestSessionCookies("test-csrf-secret-with-32-characters")
+	other := newTestSessionCookies("another-secret-with-32-characters!")
+
+	valid := sessions.csrfToken("session-1")
+
+	tests := []struct {
+		name   string
+		cookie string
+		header string
+		want   string
+	}{
+		{name: "signed token", cookie: valid, header: valid, want: "session-1"},
+		{name: "missing header", cookie: valid},
+		{name: "missing cookie", header: valid},
+		{name: "header differs from cookie", cookie: valid, header: sessions.csrfToken("session-2")},
+		{name: "session swapped", cookie: "session-2" + valid[len("session-1"):], header: "session-2" + valid[len("session-1"):]},
+		{name: "signed with another secret", cookie: other.csrfToken("session-1"), header: other.csrfToken("session-1")},
+		{name: "without session", cookie: "forged", header: "forged"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: CSRFTokenCookie, Value: tt.cookie})
+			}
+			if tt.header != "" {
+				req.Header.Set(CSRFTokenHeader, tt.header)
+			}
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			if got := sessions.CSRFSessionID(c); got != tt.want {
+				t.Errorf("CSRFSessionID() = %q, want %q", got, tt.want)
+			}
+			if got := sessions.ValidCSRF(c, "session-1"); got != (tt.want == "session-1") {
+				t.Errorf("ValidCSRF() = %v, want %v", got, tt.want == "session-1")
+			}
+		})
+	}
+}
+
+func TestNewSessionCookieConfigFromEnv(t *testing.T) {
+	t.Setenv("SESSION_COOKIES", "true")
+	t.Setenv("COOKIE_SECURE", "false")
+	t.Setenv("COOKIE_SAMESITE", "none")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://portal.example.com, https://admin.example.com")
+	t.Setenv("CSRF_SECRET", "test-csrf-secret-with-32-characters")
+
+	config := NewSessionCookieConfigFromEnv(time.Minute, time.Hour)
+
+	if !config.Enabled {
+		t.Error("Enabled = false, want true")
+	}
+	if !config.Secure || config.SameSite != http.SameSiteNoneMode {
+		t.Errorf("SameSite=None must force Secure, got Secure=%v SameSite=%v", config.Secure, config.SameSite)
+	}
+	if len(config.AllowedOrigins) != 2 || config.AllowedOrigins[1] != "https://admin.example.com" {
+		t.Errorf("AllowedOrigins = %v", config.AllowedOrigins)
+	}
+	if string(config.CSRFSecret) != "test-csrf-secret-with-32-characters" {
+		t.Errorf("CSRFSecret = %q", config.CSRFSecret)
+	}
+}
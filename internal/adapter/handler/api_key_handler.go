@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/labstack/echo/v4"
+)
+
+type APIKeyHandler struct {
+	apiKeyService interfaces.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService interfaces.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (h *APIKeyHandler) Create(c echo.Context) error {
+	var input dto.CreateAPIKeyInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	if err := input.Validate(); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	ownerID, _ := c.Get("user_id").(string)
+	if ownerID == "" {
+		return Error(c, http.StatusUnauthorized, dto.ErrTokenMissing)
+	}
+
+	response, err := h.apiKeyService.Create(c.Request().Context(), ownerID, input)
+	if err != nil {
+		return Error(c, apiKeyErrorStatus(err), err.Error())
+	}
+
+	return Success(c, http.StatusCreated, dto.ErrAPIKeyCreatedSuccess, response)
+}
+
+func (h *APIKeyHandler) GetAll(c echo.Context) error {
+	ownerID, _ := c.Get("user_id").(string)
+	if ownerID == "" {
+		return Error(c, http.StatusUnauthorized, dto.ErrTokenMissing)
+	}
+
+	keys, err := h.apiKeyService.List(c.Request().Context(), ownerID)
+	if err != nil {
+		return Error(c, http.StatusInternalServerError, dto.ErrInternalServer)
+	}
+
+	return Success(c, http.StatusOK, dto.ErrAPIKeysRetrievedSuccess, keys)
+}
+
+func (h *APIKeyHandler) Revoke(c echo.Context) error {
+	id := c.Param("id")
+
+	ownerID, _ := c.Get("user_id").(string)
+	if ownerID == "" {
+		return Error(c, http.StatusUnauthorized, dto.ErrTokenMissing)
+	}
+
+	if err := h.apiKeyService.Revoke(c.Request().Context(), ownerID, id); err != nil {
+		return Error(c, apiKeyErrorStatus(err), err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrAPIKeyRevokedSuccess, echo.Map{
+		"id": id,
+	})
+}
+
+func apiKeyErrorStatus(err error) int {
+	switch err.Error() {
+	case dto.ErrAPIKeyNotFound:
+		return http.StatusNotFound
+	case dto.ErrAPIKeyAlreadyRevoked:
+		return http.StatusConflict
+	case dto.ErrAPIKeyScopeNotGranted:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
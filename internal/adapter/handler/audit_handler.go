@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/labstack/echo/v4"
+)
+
+type AuditHandler struct {
+	auditService interfaces.AuditService
+}
+
+func NewAuditHandler(auditService interfaces.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+func (h *AuditHandler) GetAll(c echo.Context) error {
+	pagination, err := domain.ParsePaginationFromQuery(c.QueryParam("page"), c.QueryParam("limit"), "")
+	if err != nil {
+		return Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	filter := domain.AuditFilter{
+		Type:     c.QueryParam("type"),
+		ActorID:  c.QueryParam("actor_id"),
+		TargetID: c.QueryParam("target_id"),
+	}
+
+	if filter.From, err = parseDateQuery(c, "from"); err != nil {
+		return Error(c, http.StatusBadRequest, err.Error())
+	}
+	if filter.To, err = parseDateQuery(c, "to"); err != nil {
+		return Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.auditService.List(c.Request().Context(), filter, pagination)
+	if err != nil {
+		return Error(c, http.StatusInternalServerError, dto.ErrInternalServer)
+	}
+
+	return Success(c, http.StatusOK, dto.ErrAuditEventsRetrievedSuccess, result)
+}
@@ -2,7 +2,9 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/JacobD36/appfe_frontpage_api/internal/adapter/cookie"
 	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
 	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
 	"github.com/JacobD36/appfe_frontpage_api/pkg/validator"
@@ -11,11 +13,13 @@ import (
 
 type AuthHandler struct {
 	authService interfaces.AuthService
+	sessions    *cookie.SessionCookies
 }
 
-func NewAuthHandler(authService interfaces.AuthService) *AuthHandler {
+func NewAuthHandler(authService interfaces.AuthService, sessions *cookie.SessionCookies) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
+		sessions:    sessions,
 	}
 }
 
@@ -24,12 +28,13 @@ func (h *AuthHandler) Login(c echo.Context) error {
 	if err := c.Bind(&input); err != nil {
 		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
 	}
+	input.IP = c.RealIP()
 
 	if err := validator.Validate.Struct(input); err != nil {
 		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
 	}
 
-	response, err := h.authService.Login(input)
+	response, err := h.authService.Login(c.Request().Context(), input)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		switch err.Error() {
@@ -39,14 +44,54 @@ func (h *AuthHandler) Login(c echo.Context) error {
 			statusCode = http.StatusBadRequest
 		case dto.ErrAccountDisabled:
 			statusCode = http.StatusBadRequest
-		case dto.ErrPasswordIncorrect:
+		case dto.ErrAccountLocked:
+			statusCode = http.StatusLocked
+		case dto.ErrTooManyLoginAttempts:
+			statusCode = http.StatusTooManyRequests
+		}
+
+		return Error(c, statusCode, err.Error())
+	}
+
+	if response.TwoFactorRequired {
+		return Success(c, http.StatusOK, dto.ErrTwoFactorChallengeIssued, echo.Map{
+			"two_factor_required": true,
+			"challenge_token":     response.ChallengeToken,
+		})
+	}
+
+	return h.respondLogin(c, loginMessage(response), response, h.sessions.Requested(c))
+}
+
+func (h *AuthHandler) VerifyTwoFactor(c echo.Context) error {
+	var input dto.AuthTwoFactorLoginInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+	input.IP = c.RealIP()
+
+	if err := validator.Validate.Struct(input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	response, err := h.authService.VerifyTwoFactor(c.Request().Context(), input)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case dto.ErrTwoFactorChallengeInvalid:
+			statusCode = http.StatusUnauthorized
+		case dto.ErrTwoFactorCodeInvalid:
+			statusCode = http.StatusUnauthorized
+		case dto.ErrAccountDisabled:
 			statusCode = http.StatusBadRequest
+		case dto.ErrAccountLocked:
+			statusCode = http.StatusLocked
 		}
 
 		return Error(c, statusCode, err.Error())
 	}
 
-	return SuccessLogin(c, http.StatusOK, dto.ErrLoginSuccess, response.User, response.Token)
+	return h.respondLogin(c, loginMessage(response), response, h.sessions.Requested(c))
 }
 
 func (h *AuthHandler) SignInWithToken(c echo.Context) error {
@@ -59,12 +104,16 @@ func (h *AuthHandler) SignInWithToken(c echo.Context) error {
 		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
 	}
 
-	response, err := h.authService.SignInWithToken(input)
+	response, err := h.authService.SignInWithToken(c.Request().Context(), input)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
 		switch err.Error() {
 		case dto.ErrInvalidToken:
 			statusCode = http.StatusUnauthorized
+		case dto.ErrTokenOutdated:
+			statusCode = http.StatusUnauthorized
+		case dto.ErrSessionTerminated:
+			statusCode = http.StatusUnauthorized
 		case dto.ErrUserNotFoundForToken:
 			statusCode = http.StatusBadRequest
 		case dto.ErrEmailNotValidated:
@@ -76,5 +125,402 @@ func (h *AuthHandler) SignInWithToken(c echo.Context) error {
 		return Error(c, statusCode, err.Error())
 	}
 
-	return SuccessLogin(c, http.StatusOK, dto.ErrTokenSignInSuccess, response.User, response.Token)
+	return h.respondLogin(c, dto.ErrTokenSignInSuccess, response, h.sessions.Requested(c))
+}
+
+func (h *AuthHandler) Refresh(c echo.Context) error {
+	var input dto.AuthRefreshInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	// Sin refresh token en el body se usa el de la cookie, y la respuesta vuelve a entregarse en cookies
+	useCookies := h.sessions.Requested(c)
+	if input.RefreshToken == "" {
+		if token := h.sessions.RefreshToken(c); token != "" {
+			// El token CSRF identifica la sesión; el refresh token presentado debe pertenecer a ella
+			sessionID := h.sessions.CSRFSessionID(c)
+			if sessionID == "" {
+				return Error(c, http.StatusForbidden, dto.ErrCSRFTokenInvalid)
+			}
+			input.RefreshToken = token
+			input.SessionID = sessionID
+			useCookies = true
+		}
+	}
+
+	if err := validator.Validate.Struct(input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	response, err := h.authService.Refresh(c.Request().Context(), input)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case dto.ErrRefreshTokenInvalid:
+			statusCode = http.StatusUnauthorized
+		case dto.ErrRefreshTokenReused:
+			statusCode = http.StatusUnauthorized
+		case dto.ErrEmailNotValidated:
+			statusCode = http.StatusBadRequest
+		case dto.ErrAccountDisabled:
+			statusCode = http.StatusBadRequest
+		case dto.ErrPasswordChangeRequired, dto.ErrPasswordExpired:
+			statusCode = http.StatusForbidden
+		}
+
+		return Error(c, statusCode, err.Error())
+	}
+
+	return h.respondLogin(c, dto.ErrTokenRefreshSuccess, response, useCookies)
+}
+
+func (h *AuthHandler) ForgotPassword(c echo.Context) error {
+	var input dto.AuthForgotPasswordInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	if err := validator.Validate.Struct(input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	if err := h.authService.ForgotPassword(c.Request().Context(), input); err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case dto.ErrEmailServiceUnavailable:
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		return Error(c, statusCode, err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrPasswordResetRequested, nil)
+}
+
+func (h *AuthHandler) ResetPassword(c echo.Context) error {
+	var input dto.AuthResetPasswordInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	if err := input.Validate(); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	if err := h.authService.ResetPassword(c.Request().Context(), input); err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case dto.ErrPasswordResetTokenInvalid:
+			statusCode = http.StatusBadRequest
+		case dto.ErrAccountDisabled:
+			statusCode = http.StatusBadRequest
+		case dto.ErrPasswordTooLong:
+			statusCode = http.StatusBadRequest
+		}
+
+		if dto.IsPasswordPolicyError(err) {
+			statusCode = http.StatusBadRequest
+		}
+
+		return Error(c, statusCode, err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrPasswordResetSuccess, nil)
+}
+
+func (h *AuthHandler) AcceptInvitation(c echo.Context) error {
+	var input dto.AuthAcceptInvitationInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	if err := input.Validate(); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	if err := h.authService.AcceptInvitation(c.Request().Context(), input); err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case dto.ErrInvitationTokenInvalid:
+			statusCode = http.StatusBadRequest
+		case dto.ErrAccountDisabled:
+			statusCode = http.StatusBadRequest
+		case dto.ErrPasswordTooLong:
+			statusCode = http.StatusBadRequest
+		}
+
+		if dto.IsPasswordPolicyError(err) {
+			statusCode = http.StatusBadRequest
+		}
+
+		return Error(c, statusCode, err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrInvitationAcceptedSuccess, nil)
+}
+
+func (h *AuthHandler) VerifyEmail(c echo.Context) error {
+	var input dto.AuthVerifyEmailInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	if err := validator.Validate.Struct(input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	if err := h.authService.VerifyEmail(c.Request().Context(), input); err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case dto.ErrEmailVerificationTokenInvalid:
+			statusCode = http.StatusBadRequest
+		}
+
+		return Error(c, statusCode, err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrEmailVerificationSuccess, nil)
+}
+
+func (h *AuthHandler) ResendVerificationEmail(c echo.Context) error {
+	var input dto.AuthResendVerificationInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	if err := validator.Validate.Struct(input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	if err := h.authService.ResendVerificationEmail(c.Request().Context(), input); err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case dto.ErrEmailServiceUnavailable:
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		return Error(c, statusCode, err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrEmailVerificationResent, nil)
+}
+
+func (h *AuthHandler) RequestMagicLink(c echo.Context) error {
+	var input dto.AuthMagicLinkInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	if err := validator.Validate.Struct(input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	if err := h.authService.RequestMagicLink(c.Request().Context(), input); err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case dto.ErrEmailServiceUnavailable:
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		return Error(c, statusCode, err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrMagicLinkRequested, nil)
+}
+
+func (h *AuthHandler) ExchangeMagicLink(c echo.Context) error {
+	var input dto.AuthMagicLinkExchangeInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+	input.IP = c.RealIP()
+
+	if err := validator.Validate.Struct(input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	response, err := h.authService.ExchangeMagicLink(c.Request().Context(), input)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case dto.ErrMagicLinkTokenInvalid:
+			statusCode = http.StatusUnauthorized
+		case dto.ErrEmailNotValidated:
+			statusCode = http.StatusBadRequest
+		case dto.ErrAccountDisabled:
+			statusCode = http.StatusBadRequest
+		case dto.ErrAccountLocked:
+			statusCode = http.StatusLocked
+		}
+
+		return Error(c, statusCode, err.Error())
+	}
+
+	if response.TwoFactorRequired {
+		return Success(c, http.StatusOK, dto.ErrTwoFactorChallengeIssued, echo.Map{
+			"two_factor_required": true,
+			"challenge_token":     response.ChallengeToken,
+		})
+	}
+
+	return h.respondLogin(c, loginMessage(response), response, h.sessions.Requested(c))
+}
+
+func (h *AuthHandler) StartOIDCLogin(c echo.Context) error {
+	response, err := h.authService.StartOIDCLogin(c.Request().Context())
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case dto.ErrOIDCUnavailable:
+			statusCode = http.StatusServiceUnavailable
+		case dto.ErrOIDCLoginFailed:
+			statusCode = http.StatusBadGateway
+		}
+
+		return Error(c, statusCode, err.Error())
+	}
+
+	h.sessions.SetOIDCState(c, response.State, time.Until(response.ExpiresAt))
+
+	return Success(c, http.StatusOK, dto.ErrOIDCAuthorizationCreated, response)
+}
+
+func (h *AuthHandler) CompleteOIDCLogin(c echo.Context) error {
+	var input dto.AuthOIDCCallbackInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+	input.IP = c.RealIP()
+
+	if err := validator.Validate.Struct(input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	input.BrowserState = h.sessions.OIDCState(c)
+	h.sessions.ClearOIDCState(c)
+
+	response, err := h.authService.CompleteOIDCLogin(c.Request().Context(), input)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case dto.ErrOIDCUnavailable:
+			statusCode = http.StatusServiceUnavailable
+		case dto.ErrOIDCStateInvalid:
+			statusCode = http.StatusBadRequest
+		case dto.ErrOIDCLoginFailed:
+			statusCode = http.StatusUnauthorized
+		case dto.ErrOIDCEmailNotVerified, dto.ErrOIDCAccountNotFound, dto.ErrOIDCLinkRequired:
+			statusCode = http.StatusForbidden
+		case dto.ErrOIDCLinkMismatch:
+			statusCode = http.StatusConflict
+		case dto.ErrEmailNotValidated:
+			statusCode = http.StatusBadRequest
+		case dto.ErrAccountDisabled:
+			statusCode = http.StatusBadRequest
+		case dto.ErrAccountLocked:
+			statusCode = http.StatusLocked
+		}
+
+		return Error(c, statusCode, err.Error())
+	}
+
+	if response.TwoFactorRequired {
+		return Success(c, http.StatusOK, dto.ErrTwoFactorChallengeIssued, echo.Map{
+			"two_factor_required": true,
+			"challenge_token":     response.ChallengeToken,
+		})
+	}
+
+	return h.respondLogin(c, loginMessage(response), response, h.sessions.Requested(c))
+}
+
+// LinkOIDCIdentity vincula la identidad del proveedor a la cuenta de la sesión, con el code y state de un
+// flujo iniciado en /auth/oidc/authorize
+func (h *AuthHandler) LinkOIDCIdentity(c echo.Context) error {
+	var input dto.AuthOIDCCallbackInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+	input.IP = c.RealIP()
+
+	if err := validator.Validate.Struct(input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	input.BrowserState = h.sessions.OIDCState(c)
+	h.sessions.ClearOIDCState(c)
+
+	userID, _ := c.Get("user_id").(string)
+
+	if err := h.authService.LinkOIDCIdentity(c.Request().Context(), userID, input); err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.Error() {
+		case dto.ErrOIDCUnavailable:
+			statusCode = http.StatusServiceUnavailable
+		case dto.ErrOIDCStateInvalid:
+			statusCode = http.StatusBadRequest
+		case dto.ErrOIDCLoginFailed:
+			statusCode = http.StatusUnauthorized
+		case dto.ErrOIDCLinkMismatch, dto.ErrOIDCIdentityInUse:
+			statusCode = http.StatusConflict
+		}
+
+		return Error(c, statusCode, err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrOIDCLinkedSuccess, nil)
+}
+
+func (h *AuthHandler) Logout(c echo.Context) error {
+	var input dto.AuthLogoutInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	tokenID, _ := c.Get("token_id").(string)
+	userID, _ := c.Get("user_id").(string)
+	expiresAt, _ := c.Get("token_expires_at").(time.Time)
+	if tokenID == "" || userID == "" {
+		return Error(c, http.StatusUnauthorized, dto.ErrTokenMissing)
+	}
+
+	input.TokenID = tokenID
+	input.UserID = userID
+	input.ExpiresAt = expiresAt
+	input.SessionID, _ = c.Get("session_id").(string)
+
+	if input.RefreshToken == "" {
+		input.RefreshToken = h.sessions.RefreshToken(c)
+	}
+
+	if err := h.authService.Logout(c.Request().Context(), input); err != nil {
+		return Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	if h.sessions.Enabled() {
+		h.sessions.Clear(c)
+	}
+
+	return Success(c, http.StatusOK, dto.ErrLogoutSuccess, nil)
+}
+
+// respondLogin entrega los tokens en el body o, en modo cookies, en cookies HttpOnly junto a un token CSRF
+// nuevo; en ese caso el body solo contiene al usuario
+func (h *AuthHandler) respondLogin(c echo.Context, message string, response *dto.AuthLoginResponse, useCookies bool) error {
+	if !useCookies {
+		return SuccessLogin(c, http.StatusOK, message, response.User, response.Token, response.RefreshToken)
+	}
+
+	h.sessions.SetSession(c, response.SessionID, response.Token, response.RefreshToken)
+
+	return Success(c, http.StatusOK, message, response.User)
+}
+
+// loginMessage avisa al cliente cuando el token emitido está restringido al cambio de contraseña
+func loginMessage(response *dto.AuthLoginResponse) string {
+	if response.PasswordChangeRequired {
+		return dto.ErrPasswordChangeLoginSuccess
+	}
+	return dto.ErrLoginSuccess
 }
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/labstack/echo/v4"
+)
+
+type JWKSHandler struct {
+	provider interfaces.JWKSProvider
+}
+
+func NewJWKSHandler(provider interfaces.JWKSProvider) *JWKSHandler {
+	return &JWKSHandler{
+		provider: provider,
+	}
+}
+
+// GetJWKS responde con el formato estándar de JWKS (RFC 7517) en lugar del envoltorio APIResponse,
+// para que cualquier librería JWT pueda consumirlo directamente
+func (h *JWKSHandler) GetJWKS(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(http.StatusOK, struct {
+		Keys []domain.JSONWebKey `json:"keys"`
+	}{
+		Keys: h.provider.PublicJWKs(),
+	})
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/labstack/echo/v4"
+)
+
+// MeHandler atiende las rutas /me, donde el usuario se obtiene del user_id que deja el JWTMiddleware
+type MeHandler struct {
+	profileService interfaces.ProfileService
+}
+
+func NewMeHandler(profileService interfaces.ProfileService) *MeHandler {
+	return &MeHandler{
+		profileService: profileService,
+	}
+}
+
+func (h *MeHandler) Get(c echo.Context) error {
+	userID, _ := c.Get("user_id").(string)
+	if userID == "" {
+		return Error(c, http.StatusUnauthorized, dto.ErrTokenMissing)
+	}
+
+	user, err := h.profileService.Get(c.Request().Context(), userID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == dto.ErrUserNotFound {
+			statusCode = http.StatusNotFound
+		}
+		return Error(c, statusCode, err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrProfileRetrievedSuccess, user)
+}
+
+func (h *MeHandler) Update(c echo.Context) error {
+	userID, _ := c.Get("user_id").(string)
+	if userID == "" {
+		return Error(c, http.StatusUnauthorized, dto.ErrTokenMissing)
+	}
+
+	var input dto.UpdateProfileInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	if err := input.Validate(); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	user, err := h.profileService.Update(c.Request().Context(), userID, input)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == dto.ErrProfileNameEmpty {
+			statusCode = http.StatusBadRequest
+		}
+		return Error(c, statusCode, err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrProfileUpdatedSuccess, user)
+}
+
+func (h *MeHandler) ChangePassword(c echo.Context) error {
+	userID, _ := c.Get("user_id").(string)
+	if userID == "" {
+		return Error(c, http.StatusUnauthorized, dto.ErrTokenMissing)
+	}
+
+	var input dto.ChangePasswordInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	if err := input.Validate(); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	if err := h.profileService.ChangePassword(c.Request().Context(), userID, input); err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == dto.ErrCurrentPasswordIncorrect || dto.IsPasswordPolicyError(err) {
+			statusCode = http.StatusBadRequest
+		}
+		return Error(c, statusCode, err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrPasswordChangedSuccess, nil)
+}
+
+func (h *MeHandler) ListSessions(c echo.Context) error {
+	userID, _ := c.Get("user_id").(string)
+	if userID == "" {
+		return Error(c, http.StatusUnauthorized, dto.ErrTokenMissing)
+	}
+
+	sessions, err := h.profileService.ListSessions(c.Request().Context(), userID)
+	if err != nil {
+		return Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrSessionsRetrievedSuccess, sessions)
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/labstack/echo/v4"
+)
+
+// parseDateQuery lee un parámetro opcional en formato RFC3339; si no viene devuelve nil
+func parseDateQuery(c echo.Context, param string) (*time.Time, error) {
+	value := c.QueryParam(param)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf(dto.ErrQueryDateInvalid, param)
+	}
+
+	return &t, nil
+}
+
+// parseBoolQuery lee un parámetro booleano opcional; si no viene devuelve nil
+func parseBoolQuery(c echo.Context, param string) (*bool, error) {
+	value := c.QueryParam(param)
+	if value == "" {
+		return nil, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf(dto.ErrQueryBoolInvalid, param)
+	}
+
+	return &b, nil
+}
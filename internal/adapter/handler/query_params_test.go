@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/labstack/echo/v4"
+)
+
+func TestParseDateQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    *time.Time
+		wantErr bool
+	}{
+		{name: "missing", value: ""},
+		{name: "utc", value: "2025-01-31T00:00:00Z", want: timePtr(time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC))},
+		{name: "with offset", value: "2025-01-31T08:30:00-05:00", want: timePtr(time.Date(2025, 1, 31, 13, 30, 0, 0, time.UTC))},
+		{name: "date without time", value: "2025-01-31", wantErr: true},
+		{name: "unix timestamp", value: "1738281600", wantErr: true},
+		{name: "garbage", value: "ayer", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := url.Values{}
+			if tt.value != "" {
+				query.Set("from", tt.value)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/?"+query.Encode(), nil)
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			got, err := parseDateQuery(c, "from")
+			if tt.wantErr {
+				if err == nil || err.Error() != fmt.Sprintf(dto.ErrQueryDateInvalid, "from") {
+					t.Fatalf("parseDateQuery() error = %v, want %q", err, fmt.Sprintf(dto.ErrQueryDateInvalid, "from"))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDateQuery() error = %v", err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("parseDateQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time { return &t }
@@ -16,11 +16,12 @@ type APIResponse struct {
 }
 
 type APILoginResponse struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Status  string `json:"status"`
-	Data    any    `json:"data"`
-	Token   string `json:"token"`
+	Code         int    `json:"code"`
+	Message      string `json:"message"`
+	Status       string `json:"status"`
+	Data         any    `json:"data"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
 }
 
 func Success(c echo.Context, code int, message string, data any) error {
@@ -38,7 +39,7 @@ func Success(c echo.Context, code int, message string, data any) error {
 	})
 }
 
-func SuccessLogin(c echo.Context, code int, message string, data any, token string) error {
+func SuccessLogin(c echo.Context, code int, message string, data any, token, refreshToken string) error {
 	ctx := c.Request().Context()
 	logger.Info(ctx, dto.MsgSuccessfulLoginResponse,
 		logger.Int("status_code", code),
@@ -47,11 +48,12 @@ func SuccessLogin(c echo.Context, code int, message string, data any, token stri
 	)
 
 	return c.JSON(code, APILoginResponse{
-		Code:    code,
-		Message: message,
-		Status:  http.StatusText(code),
-		Data:    data,
-		Token:   token,
+		Code:         code,
+		Message:      message,
+		Status:       http.StatusText(code),
+		Data:         data,
+		Token:        token,
+		RefreshToken: refreshToken,
 	})
 }
 
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/labstack/echo/v4"
+)
+
+type RoleHandler struct {
+	roleService interfaces.RoleService
+}
+
+func NewRoleHandler(roleService interfaces.RoleService) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+	}
+}
+
+func (h *RoleHandler) GetAll(c echo.Context) error {
+	roles, err := h.roleService.GetAll(c.Request().Context())
+	if err != nil {
+		return Error(c, http.StatusInternalServerError, dto.ErrInternalServer)
+	}
+
+	return Success(c, http.StatusOK, dto.ErrRolesRetrievedSuccess, roles)
+}
+
+func (h *RoleHandler) GetPermissions(c echo.Context) error {
+	return Success(c, http.StatusOK, dto.ErrPermissionsRetrievedSuccess, domain.Permissions)
+}
+
+func (h *RoleHandler) GetByName(c echo.Context) error {
+	role, err := h.roleService.GetByName(c.Request().Context(), c.Param("name"))
+	if err != nil {
+		return Error(c, roleErrorStatus(err), err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrRoleRetrievedSuccess, role)
+}
+
+func (h *RoleHandler) Create(c echo.Context) error {
+	var input dto.CreateRoleInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	if err := input.Validate(); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	role, err := h.roleService.Create(c.Request().Context(), input)
+	if err != nil {
+		return Error(c, roleErrorStatus(err), err.Error())
+	}
+
+	return Success(c, http.StatusCreated, dto.ErrRoleCreatedSuccess, role)
+}
+
+func (h *RoleHandler) Update(c echo.Context) error {
+	var input dto.UpdateRoleInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	if err := input.Validate(); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	role, err := h.roleService.Update(c.Request().Context(), c.Param("name"), input)
+	if err != nil {
+		return Error(c, roleErrorStatus(err), err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrRoleUpdatedSuccess, role)
+}
+
+func (h *RoleHandler) Delete(c echo.Context) error {
+	name := c.Param("name")
+
+	if err := h.roleService.Delete(c.Request().Context(), name); err != nil {
+		return Error(c, roleErrorStatus(err), err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrRoleDeletedSuccess, echo.Map{
+		"name": name,
+	})
+}
+
+func roleErrorStatus(err error) int {
+	switch err.Error() {
+	case dto.ErrRoleNotFound:
+		return http.StatusNotFound
+	case dto.ErrRoleAlreadyExists, dto.ErrRoleInUse:
+		return http.StatusConflict
+	case dto.ErrSystemRoleProtected, dto.ErrOwnRoleUpdateForbidden, dto.ErrRolePermissionsForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
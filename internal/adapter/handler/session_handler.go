@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/labstack/echo/v4"
+)
+
+type SessionHandler struct {
+	sessionService interfaces.SessionService
+}
+
+func NewSessionHandler(sessionService interfaces.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+func (h *SessionHandler) GetByUser(c echo.Context) error {
+	sessions, err := h.sessionService.ListByUser(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return Error(c, sessionErrorStatus(err), err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrSessionsRetrievedSuccess, sessions)
+}
+
+func (h *SessionHandler) Terminate(c echo.Context) error {
+	sessionID := c.Param("sid")
+
+	if err := h.sessionService.Terminate(c.Request().Context(), c.Param("id"), sessionID); err != nil {
+		return Error(c, sessionErrorStatus(err), err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrSessionTerminatedSuccess, echo.Map{
+		"id": sessionID,
+	})
+}
+
+func sessionErrorStatus(err error) int {
+	switch err.Error() {
+	case dto.ErrUserNotFound, dto.ErrSessionNotFound:
+		return http.StatusNotFound
+	case dto.ErrSessionAlreadyTerminated:
+		return http.StatusConflict
+	case dto.ErrUserWriteForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/pkg/validator"
+	"github.com/labstack/echo/v4"
+)
+
+type TwoFactorHandler struct {
+	twoFactorService interfaces.TwoFactorService
+}
+
+func NewTwoFactorHandler(twoFactorService interfaces.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+	}
+}
+
+func (h *TwoFactorHandler) Enroll(c echo.Context) error {
+	userID, _ := c.Get("user_id").(string)
+	if userID == "" {
+		return Error(c, http.StatusUnauthorized, dto.ErrTokenMissing)
+	}
+
+	response, err := h.twoFactorService.Enroll(c.Request().Context(), userID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == dto.ErrTwoFactorAlreadyEnabled {
+			statusCode = http.StatusConflict
+		}
+		return Error(c, statusCode, err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrTwoFactorEnrollStarted, response)
+}
+
+func (h *TwoFactorHandler) Confirm(c echo.Context) error {
+	userID, _ := c.Get("user_id").(string)
+	if userID == "" {
+		return Error(c, http.StatusUnauthorized, dto.ErrTokenMissing)
+	}
+
+	var input dto.TwoFactorCodeInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	if err := validator.Validate.Struct(input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	response, err := h.twoFactorService.Confirm(c.Request().Context(), userID, input)
+	if err != nil {
+		return Error(c, twoFactorErrorStatus(err), err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrTwoFactorEnabledSuccess, response)
+}
+
+func (h *TwoFactorHandler) Disable(c echo.Context) error {
+	userID, _ := c.Get("user_id").(string)
+	if userID == "" {
+		return Error(c, http.StatusUnauthorized, dto.ErrTokenMissing)
+	}
+
+	var input dto.TwoFactorCodeInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	if err := validator.Validate.Struct(input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	if err := h.twoFactorService.Disable(c.Request().Context(), userID, input); err != nil {
+		return Error(c, twoFactorErrorStatus(err), err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrTwoFactorDisabledSuccess, nil)
+}
+
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c echo.Context) error {
+	userID, _ := c.Get("user_id").(string)
+	if userID == "" {
+		return Error(c, http.StatusUnauthorized, dto.ErrTokenMissing)
+	}
+
+	var input dto.TwoFactorCodeInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	if err := validator.Validate.Struct(input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	response, err := h.twoFactorService.RegenerateRecoveryCodes(c.Request().Context(), userID, input)
+	if err != nil {
+		return Error(c, twoFactorErrorStatus(err), err.Error())
+	}
+
+	return Success(c, http.StatusOK, dto.ErrRecoveryCodesRegenerated, response)
+}
+
+func twoFactorErrorStatus(err error) int {
+	switch err.Error() {
+	case dto.ErrTwoFactorCodeInvalid:
+		return http.StatusBadRequest
+	case dto.ErrTwoFactorAlreadyEnabled:
+		return http.StatusConflict
+	case dto.ErrTwoFactorNotEnrolled, dto.ErrTwoFactorNotEnabled:
+		return http.StatusBadRequest
+	case dto.ErrTwoFactorRequiredForAdmins:
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
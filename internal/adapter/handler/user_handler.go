@@ -58,18 +58,24 @@ func (h *UserHandler) Create(c echo.Context) error {
 	}
 
 	u := &domain.User{
-		Name:     input.Name,
-		Email:    input.Email,
-		Password: &input.Password,
-		Role:     input.Role,
+		Name:  input.Name,
+		Email: input.Email,
+		Role:  input.Role,
 	}
 
-	if err := h.userService.Create(ctx, u); err != nil {
+	invitation, err := h.userService.Create(ctx, u)
+	if err != nil {
 		logger.LogError(ctx, dto.MsgFailedToCreateUser,
 			logger.String("email", input.Email),
 			logger.String("role", input.Role),
 			logger.Error("error", err),
 		)
+		switch err.Error() {
+		case domain.ErrInvalidRole:
+			return Error(c, http.StatusBadRequest, err.Error())
+		case dto.ErrRoleAssignmentForbidden:
+			return Error(c, http.StatusForbidden, err.Error())
+		}
 		return Error(c, http.StatusInternalServerError, err.Error())
 	}
 
@@ -82,6 +88,7 @@ func (h *UserHandler) Create(c echo.Context) error {
 	return Success(c, http.StatusCreated, dto.ErrUserCreatedSuccess, echo.Map{
 		dto.UserIdLabel:    u.ID,
 		dto.UserEmailLabel: u.Email,
+		"invitation":       invitation,
 	})
 }
 
@@ -89,36 +96,83 @@ func (h *UserHandler) GetAll(c echo.Context) error {
 	pageStr := c.QueryParam("page")
 	limitStr := c.QueryParam("limit")
 	searchStr := c.QueryParam("search")
+	cursorStr := c.QueryParam("cursor")
+	cursorMode := cursorStr != "" || c.QueryParam("pagination") == domain.PaginationModeCursor
 
 	var pagination *domain.Pagination = nil
 	var err error
 
-	if pageStr != "" || limitStr != "" || searchStr != "" {
+	if cursorMode {
+		if pageStr != "" {
+			return Error(c, http.StatusBadRequest, domain.ErrCursorWithPage)
+		}
+		pagination, err = domain.ParseCursorPaginationFromQuery(limitStr, cursorStr, searchStr)
+		if err != nil {
+			return Error(c, http.StatusBadRequest, err.Error())
+		}
+	} else if pageStr != "" || limitStr != "" || searchStr != "" {
 		pagination, err = domain.ParsePaginationFromQuery(pageStr, limitStr, searchStr)
 		if err != nil {
 			return Error(c, http.StatusBadRequest, err.Error())
 		}
 	}
 
+	filter, err := parseUserFilter(c)
+	if err != nil {
+		return Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	if cursorMode && filter.Sort.Field != domain.UserSortCreatedAt {
+		return Error(c, http.StatusBadRequest, domain.ErrCursorSortUnsupported)
+	}
+
 	ctx := c.Request().Context()
 
-	result, err := h.userService.GetAll(ctx, pagination)
+	result, err := h.userService.GetAll(ctx, filter, pagination)
 	if err != nil {
 		return Error(c, http.StatusInternalServerError, dto.ErrInternalServer)
 	}
 
 	message := dto.ErrUserRetrievedSuccess
-	if pagination != nil {
-		if pagination.Search != "" {
-			message = dto.ErrUsersSearchSuccess
-		} else if pagination.Page > 1 || pagination.Limit < 100 {
-			message = dto.ErrUsersRetrievedPaginated
-		}
+	if (pagination != nil && pagination.Search != "") || !filter.IsEmpty() {
+		message = dto.ErrUsersSearchSuccess
+	} else if pagination != nil && (pagination.Page > 1 || pagination.Limit < 100) {
+		message = dto.ErrUsersRetrievedPaginated
 	}
 
 	return Success(c, http.StatusOK, message, result)
 }
 
+// parseUserFilter lee los filtros y el orden del listado de usuarios desde la query
+func parseUserFilter(c echo.Context) (domain.UserFilter, error) {
+	var err error
+	filter := domain.UserFilter{Role: c.QueryParam("role")}
+
+	if filter.Sort, err = domain.ParseUserSort(c.QueryParam("sort")); err != nil {
+		return filter, err
+	}
+	if filter.Status, err = parseBoolQuery(c, "status"); err != nil {
+		return filter, err
+	}
+	if filter.EmailValidated, err = parseBoolQuery(c, "emailValidated"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedFrom, err = parseDateQuery(c, "created_from"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = parseDateQuery(c, "created_to"); err != nil {
+		return filter, err
+	}
+	if filter.UpdatedFrom, err = parseDateQuery(c, "updated_from"); err != nil {
+		return filter, err
+	}
+	if filter.UpdatedTo, err = parseDateQuery(c, "updated_to"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
 func (h *UserHandler) GetByID(c echo.Context) error {
 	id := c.Param("id")
 	if id == "" {
@@ -167,6 +221,13 @@ func (h *UserHandler) UpdateByID(c echo.Context) error {
 	}
 
 	if err := h.userService.UpdateByID(ctx, &input); err != nil {
+		if err.Error() == domain.ErrInvalidRole || dto.IsPasswordPolicyError(err) {
+			return Error(c, http.StatusBadRequest, err.Error())
+		}
+		switch err.Error() {
+		case dto.ErrRoleAssignmentForbidden, dto.ErrOwnRoleChangeForbidden, dto.ErrAPIKeyRoleChange, dto.ErrUserWriteForbidden:
+			return Error(c, http.StatusForbidden, err.Error())
+		}
 		return Error(c, http.StatusInternalServerError, err.Error())
 	}
 
@@ -192,6 +253,9 @@ func (h *UserHandler) Delete(c echo.Context) error {
 	}
 
 	if err := h.userService.Delete(ctx, id); err != nil {
+		if err.Error() == dto.ErrUserWriteForbidden {
+			return Error(c, http.StatusForbidden, err.Error())
+		}
 		return Error(c, http.StatusInternalServerError, err.Error())
 	}
 
@@ -199,3 +263,125 @@ func (h *UserHandler) Delete(c echo.Context) error {
 		dto.UserIdLabel: id,
 	})
 }
+
+func (h *UserHandler) Unlock(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidUserID)
+	}
+
+	ctx := c.Request().Context()
+
+	_, err := h.userService.GetByID(ctx, id)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return Error(c, http.StatusNotFound, dto.ErrUserNotFound)
+		}
+		return Error(c, http.StatusInternalServerError, dto.ErrInternalServer)
+	}
+
+	if err := h.userService.Unlock(ctx, id); err != nil {
+		if err.Error() == dto.ErrUserWriteForbidden {
+			return Error(c, http.StatusForbidden, err.Error())
+		}
+		return Error(c, http.StatusInternalServerError, dto.ErrInternalServer)
+	}
+
+	return Success(c, http.StatusOK, dto.ErrUserUnlockedSuccess, echo.Map{
+		dto.UserIdLabel: id,
+	})
+}
+
+func (h *UserHandler) ResendInvitation(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidUserID)
+	}
+
+	invitation, err := h.userService.ResendInvitation(c.Request().Context(), id)
+	if err != nil {
+		switch err.Error() {
+		case dto.ErrNoRowsFound:
+			return Error(c, http.StatusNotFound, dto.ErrUserNotFound)
+		case dto.ErrInvitationAlreadyAccepted:
+			return Error(c, http.StatusConflict, err.Error())
+		case dto.ErrAccountDisabled:
+			return Error(c, http.StatusBadRequest, err.Error())
+		case dto.ErrUserWriteForbidden:
+			return Error(c, http.StatusForbidden, err.Error())
+		}
+		return Error(c, http.StatusInternalServerError, dto.ErrInternalServer)
+	}
+
+	return Success(c, http.StatusOK, dto.ErrInvitationResentSuccess, echo.Map{
+		dto.UserIdLabel: id,
+		"invitation":    invitation,
+	})
+}
+
+func (h *UserHandler) ForcePasswordReset(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidUserID)
+	}
+
+	var input dto.AdminPasswordResetInput
+	if err := c.Bind(&input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidInput)
+	}
+
+	if err := validator.Validate.Struct(input); err != nil {
+		return Error(c, http.StatusBadRequest, dto.TranslateValidationErrors(err))
+	}
+
+	response, err := h.userService.ForcePasswordReset(c.Request().Context(), id, input)
+	if err != nil {
+		switch err.Error() {
+		case dto.ErrNoRowsFound:
+			return Error(c, http.StatusNotFound, dto.ErrUserNotFound)
+		case dto.ErrAccountDisabled:
+			return Error(c, http.StatusBadRequest, err.Error())
+		case dto.ErrUserWriteForbidden:
+			return Error(c, http.StatusForbidden, err.Error())
+		}
+		return Error(c, http.StatusInternalServerError, dto.ErrInternalServer)
+	}
+
+	message := dto.ErrAdminPasswordResetSuccess
+	if response.Method == dto.AdminPasswordResetLink {
+		message = dto.ErrAdminPasswordResetLinkSent
+	}
+
+	return Success(c, http.StatusOK, message, response)
+}
+
+func (h *UserHandler) Impersonate(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return Error(c, http.StatusBadRequest, dto.ErrInvalidUserID)
+	}
+
+	if actorID, _ := c.Get("actor_id").(string); actorID != "" {
+		return Error(c, http.StatusForbidden, dto.ErrImpersonationNested)
+	}
+
+	actorID, _ := c.Get("user_id").(string)
+	if actorID == "" {
+		return Error(c, http.StatusUnauthorized, dto.ErrTokenMissing)
+	}
+
+	response, err := h.userService.Impersonate(c.Request().Context(), actorID, id)
+	if err != nil {
+		switch err.Error() {
+		case dto.ErrNoRowsFound:
+			return Error(c, http.StatusNotFound, dto.ErrUserNotFound)
+		case dto.ErrImpersonationAdminForbidden, dto.ErrImpersonationRoleForbidden:
+			return Error(c, http.StatusForbidden, err.Error())
+		case dto.ErrAccountDisabled:
+			return Error(c, http.StatusBadRequest, err.Error())
+		}
+		return Error(c, http.StatusInternalServerError, dto.ErrInternalServer)
+	}
+
+	return Success(c, http.StatusOK, dto.ErrImpersonationStartedSuccess, response)
+}
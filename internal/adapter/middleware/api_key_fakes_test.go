@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+)
+
+const testAPIKey = "appfe_valid-api-key"
+
+// fakeAPIKeys acepta solo testAPIKey, con el alcance users:read y un administrador como dueño
+type fakeAPIKeys struct{}
+
+func (fakeAPIKeys) Create(context.Context, string, dto.CreateAPIKeyInput) (*dto.APIKeyCreatedResponse, error) {
+	return nil, errors.New("not implemented")
+}
+func (fakeAPIKeys) List(context.Context, string) ([]*domain.APIKey, error) { return nil, nil }
+func (fakeAPIKeys) Revoke(context.Context, string, string) error           { return nil }
+
+func (fakeAPIKeys) Authenticate(_ context.Context, key string) (*domain.APIKey, *domain.User, error) {
+	if key != testAPIKey {
+		return nil, nil, errors.New(dto.ErrAPIKeyInvalid)
+	}
+	return &domain.APIKey{ID: "key-1", Scopes: []string{domain.PermissionUsersRead}},
+		&domain.User{ID: "admin-1", Role: domain.AdminRole, Status: true}, nil
+}
+
+var _ interfaces.APIKeyService = fakeAPIKeys{}
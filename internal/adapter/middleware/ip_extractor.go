@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// NewIPExtractorFromEnv decide de dónde sale la IP del cliente que usan el rate limit, el límite de login
+// por IP y la auditoría. Sin TRUSTED_PROXIES se usa la IP de la conexión y se ignoran X-Forwarded-For y
+// X-Real-IP, que cualquier cliente puede falsificar. Con proxies configurados (IPs o CIDR separados por
+// comas) se recorre X-Forwarded-For desde la derecha descartando solo esos saltos.
+func NewIPExtractorFromEnv() echo.IPExtractor {
+	return NewIPExtractor(os.Getenv(dto.EnvTrustedProxies))
+}
+
+// NewIPExtractor crea el extractor para la lista de proxies de confianza; las entradas inválidas se ignoran
+func NewIPExtractor(trustedProxies string) echo.IPExtractor {
+	var ranges []echo.TrustOption
+	for _, entry := range strings.Split(trustedProxies, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		ipRange, err := parseTrustedProxy(entry)
+		if err != nil {
+			logger.Warn(context.Background(), dto.MsgInvalidTrustedProxy, logger.String("value", entry))
+			continue
+		}
+		ranges = append(ranges, echo.TrustIPRange(ipRange))
+	}
+
+	if len(ranges) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	// Echo confía por defecto en loopback y redes privadas; solo deben contar los proxies configurados
+	options := append([]echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}, ranges...)
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+// parseTrustedProxy acepta un CIDR o una IP suelta, que se trata como un rango de una sola dirección
+func parseTrustedProxy(value string) (*net.IPNet, error) {
+	if strings.Contains(value, "/") {
+		_, ipRange, err := net.ParseCIDR(value)
+		return ipRange, err
+	}
+
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, &net.ParseError{Type: "IP address", Text: value}
+	}
+
+	bits := 8 * net.IPv6len
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, bits = ip4, 8*net.IPv4len
+	}
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestNewIPExtractor(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies string
+		remoteAddr     string
+		forwardedFor   string
+		want           string
+	}{
+		{name: "no proxies ignores forwarded header", remoteAddr: "203.0.113.7:4000", forwardedFor: "198.51.100.1", want: "203.0.113.7"},
+		{name: "private peer is not trusted by default", remoteAddr: "10.0.0.2:4000", forwardedFor: "198.51.100.1", want: "10.0.0.2"},
+		{name: "trusted proxy cidr", trustedProxies: "10.0.0.0/8", remoteAddr: "10.0.0.2:4000", forwardedFor: "198.51.100.1", want: "198.51.100.1"},
+		{name: "trusted proxy ip", trustedProxies: "10.0.0.2", remoteAddr: "10.0.0.2:4000", forwardedFor: "198.51.100.1", want: "198.51.100.1"},
+		{name: "untrusted peer with proxies configured", trustedProxies: "10.0.0.0/8", remoteAddr: "203.0.113.7:4000", forwardedFor: "198.51.100.1", want: "203.0.113.7"},
+		{name: "spoofed left-most entry is skipped", trustedProxies: "10.0.0.0/8", remoteAddr: "10.0.0.2:4000", forwardedFor: "1.1.1.1, 198.51.100.1", want: "198.51.100.1"},
+		{name: "invalid entries are ignored", trustedProxies: "not-an-ip, 10.0.0.0/99", remoteAddr: "10.0.0.2:4000", forwardedFor: "198.51.100.1", want: "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set(echo.HeaderXForwardedFor, tt.forwardedFor)
+			req.Header.Set(echo.HeaderXRealIP, "192.0.2.99")
+
+			if got := NewIPExtractor(tt.trustedProxies)(req); got != tt.want {
+				t.Errorf("NewIPExtractor(%q) = %q, want %q", tt.trustedProxies, got, tt.want)
+			}
+		})
+	}
+}
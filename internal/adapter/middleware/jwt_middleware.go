@@ -2,29 +2,95 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/JacobD36/appfe_frontpage_api/internal/adapter/cookie"
 	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
 	"github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
 	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	usecaseInterfaces "github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/pkg/logger"
 	"github.com/labstack/echo/v4"
 )
 
 type JWTMiddleware struct {
-	jwtService interfaces.JWTService
+	jwtService    interfaces.JWTService
+	revocation    usecaseInterfaces.TokenRevocationService
+	tokenVersions usecaseInterfaces.TokenVersionService
+	loginSessions usecaseInterfaces.SessionService
+	permissions   usecaseInterfaces.PermissionService
+	sessions      *cookie.SessionCookies
+	apiKeys       usecaseInterfaces.APIKeyService
+
+	// sessionlessCutoff es el arranque del proceso; ver domain.TokenClaims.IsSessionlessSince
+	sessionlessCutoff time.Time
 }
 
-func NewJWTMiddleware(jwtService interfaces.JWTService) *JWTMiddleware {
+func NewJWTMiddleware(
+	jwtService interfaces.JWTService,
+	revocation usecaseInterfaces.TokenRevocationService,
+	tokenVersions usecaseInterfaces.TokenVersionService,
+	loginSessions usecaseInterfaces.SessionService,
+	permissions usecaseInterfaces.PermissionService,
+	sessions *cookie.SessionCookies,
+	apiKeys usecaseInterfaces.APIKeyService,
+) *JWTMiddleware {
 	return &JWTMiddleware{
-		jwtService: jwtService,
+		jwtService:    jwtService,
+		revocation:    revocation,
+		tokenVersions: tokenVersions,
+		loginSessions: loginSessions,
+		permissions:   permissions,
+		sessions:      sessions,
+		apiKeys:       apiKeys,
+
+		sessionlessCutoff: time.Now(),
 	}
 }
 
+// Authenticate valida el token de acceso y rechaza los tokens restringidos al cambio de contraseña obligatorio
 func (m *JWTMiddleware) Authenticate() echo.MiddlewareFunc {
+	return m.authenticate(false, false)
+}
+
+// AuthenticatePasswordChange acepta además los tokens restringidos; solo debe usarse en la ruta de cambio de contraseña
+func (m *JWTMiddleware) AuthenticatePasswordChange() echo.MiddlewareFunc {
+	return m.authenticate(true, false)
+}
+
+// AuthenticateWithAPIKey acepta además "Authorization: ApiKey <clave>". Solo debe usarse en rutas protegidas
+// con RequirePermission, que es donde se aplican los alcances de la clave.
+func (m *JWTMiddleware) AuthenticateWithAPIKey() echo.MiddlewareFunc {
+	return m.authenticate(false, true)
+}
+
+func (m *JWTMiddleware) authenticate(allowPasswordChange, allowAPIKey bool) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			authHeader := c.Request().Header.Get("Authorization")
-			if authHeader == "" {
+			var tokenString string
+			fromCookie := false
+
+			// El header tiene prioridad; la cookie de sesión solo se usa si el cliente no envía Authorization
+			if authHeader := c.Request().Header.Get("Authorization"); authHeader != "" {
+				tokenParts := strings.Split(authHeader, " ")
+				if len(tokenParts) == 2 && tokenParts[0] == "ApiKey" {
+					return m.authenticateAPIKey(c, next, tokenParts[1], allowAPIKey)
+				}
+
+				if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+					return c.JSON(http.StatusUnauthorized, map[string]any{
+						"code":    http.StatusUnauthorized,
+						"message": dto.ErrInvalidTokenFormat,
+						"status":  "Unauthorized",
+					})
+				}
+
+				tokenString = tokenParts[1]
+			} else if tokenString = m.sessions.AccessToken(c); tokenString != "" {
+				fromCookie = true
+			} else {
 				return c.JSON(http.StatusUnauthorized, map[string]any{
 					"code":    http.StatusUnauthorized,
 					"message": dto.ErrTokenMissing,
@@ -32,19 +98,51 @@ func (m *JWTMiddleware) Authenticate() echo.MiddlewareFunc {
 				})
 			}
 
-			tokenParts := strings.Split(authHeader, " ")
-			if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+			claims, err := m.jwtService.ValidateToken(tokenString)
+			if err != nil {
 				return c.JSON(http.StatusUnauthorized, map[string]any{
 					"code":    http.StatusUnauthorized,
-					"message": dto.ErrInvalidTokenFormat,
+					"message": dto.ErrInvalidToken,
 					"status":  "Unauthorized",
 				})
 			}
 
-			tokenString := tokenParts[1]
+			// El navegador adjunta la cookie en cualquier petición al dominio, por eso las que modifican
+			// estado deben demostrar que leyeron la cookie CSRF emitida para la sesión del token
+			if fromCookie && !m.sessions.ValidCSRF(c, claims.SessionID) {
+				return c.JSON(http.StatusForbidden, map[string]any{
+					"code":    http.StatusForbidden,
+					"message": dto.ErrCSRFTokenInvalid,
+					"status":  "Forbidden",
+				})
+			}
 
-			claims, err := m.jwtService.ValidateToken(tokenString)
+			if m.revocation.IsRevoked(claims.TokenID) {
+				return c.JSON(http.StatusUnauthorized, map[string]any{
+					"code":    http.StatusUnauthorized,
+					"message": dto.ErrTokenRevoked,
+					"status":  "Unauthorized",
+				})
+			}
+
+			current, err := m.tokenVersions.IsCurrent(c.Request().Context(), claims.UserID, claims.TokenVersion)
 			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]any{
+					"code":    http.StatusInternalServerError,
+					"message": dto.ErrInternalServer,
+					"status":  "Internal Server Error",
+				})
+			}
+
+			if !current {
+				return c.JSON(http.StatusUnauthorized, map[string]any{
+					"code":    http.StatusUnauthorized,
+					"message": dto.ErrTokenOutdated,
+					"status":  "Unauthorized",
+				})
+			}
+
+			if claims.IsSessionlessSince(m.sessionlessCutoff) {
 				return c.JSON(http.StatusUnauthorized, map[string]any{
 					"code":    http.StatusUnauthorized,
 					"message": dto.ErrInvalidToken,
@@ -52,15 +150,102 @@ func (m *JWTMiddleware) Authenticate() echo.MiddlewareFunc {
 				})
 			}
 
-			c.Set("user_id", claims.ID)
+			if claims.SessionID != "" {
+				active, err := m.loginSessions.IsActive(c.Request().Context(), claims.SessionID)
+				if err != nil {
+					logger.LogError(c.Request().Context(), dto.MsgSessionLookupFailed,
+						logger.String("session_id", claims.SessionID),
+						logger.Error("error", err),
+					)
+					return c.JSON(http.StatusInternalServerError, map[string]any{
+						"code":    http.StatusInternalServerError,
+						"message": dto.ErrInternalServer,
+						"status":  "Internal Server Error",
+					})
+				}
+
+				if !active {
+					return c.JSON(http.StatusUnauthorized, map[string]any{
+						"code":    http.StatusUnauthorized,
+						"message": dto.ErrSessionTerminated,
+						"status":  "Unauthorized",
+					})
+				}
+			}
+
+			if claims.PasswordChangeRequired && !allowPasswordChange {
+				return c.JSON(http.StatusForbidden, map[string]any{
+					"code":    http.StatusForbidden,
+					"message": dto.ErrPasswordChangeRequired,
+					"status":  "Forbidden",
+				})
+			}
+
+			c.Set("user_id", claims.UserID)
 			c.Set("user_email", claims.Email)
 			c.Set("user_role", claims.Role)
+			c.Set("token_id", claims.TokenID)
+			c.Set("token_expires_at", claims.ExpiresAt)
+			c.Set("two_factor", claims.TwoFactor)
+			c.Set("session_id", claims.SessionID)
+
+			// El usuario autenticado queda como actor de los eventos de auditoría de la petición; en una
+			// suplantación el actor es el administrador y user_id sigue siendo el usuario suplantado
+			ctx := domain.WithActor(c.Request().Context(), claims.UserID)
+			if claims.IsImpersonation() {
+				c.Set("actor_id", claims.ActorID)
+				ctx = domain.WithImpersonation(c.Request().Context(), claims.ActorID, claims.UserID)
+			}
+			ctx = logger.WithIdentity(ctx, claims.UserID, claims.ActorID)
+			c.SetRequest(c.Request().WithContext(ctx))
 
 			return next(c)
 		}
 	}
 }
 
+// authenticateAPIKey autentica la petición como el dueño de la clave, con su rol y los alcances de la clave
+func (m *JWTMiddleware) authenticateAPIKey(c echo.Context, next echo.HandlerFunc, plain string, allowed bool) error {
+	if !allowed {
+		return c.JSON(http.StatusForbidden, map[string]any{
+			"code":    http.StatusForbidden,
+			"message": dto.ErrAPIKeyNotAllowed,
+			"status":  "Forbidden",
+		})
+	}
+
+	ctx := c.Request().Context()
+
+	key, owner, err := m.apiKeys.Authenticate(ctx, plain)
+	if err != nil {
+		if err.Error() == dto.ErrAPIKeyInvalid {
+			return c.JSON(http.StatusUnauthorized, map[string]any{
+				"code":    http.StatusUnauthorized,
+				"message": dto.ErrAPIKeyInvalid,
+				"status":  "Unauthorized",
+			})
+		}
+
+		logger.LogError(ctx, dto.MsgAPIKeyLookupFailed, logger.Error("error", err))
+		return c.JSON(http.StatusInternalServerError, map[string]any{
+			"code":    http.StatusInternalServerError,
+			"message": dto.ErrInternalServer,
+			"status":  "Internal Server Error",
+		})
+	}
+
+	c.Set("user_id", owner.ID)
+	c.Set("user_email", owner.Email)
+	c.Set("user_role", owner.Role)
+	c.Set("api_key_id", key.ID)
+	c.Set("api_key_scopes", key.Scopes)
+
+	ctx = domain.WithAPIKey(ctx, owner.ID, key.ID)
+	c.SetRequest(c.Request().WithContext(logger.WithIdentity(ctx, owner.ID, "")))
+
+	return next(c)
+}
+
 func (m *JWTMiddleware) RequiredRole(requiredRole string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -94,6 +279,118 @@ func (m *JWTMiddleware) RequireAdminRole() echo.MiddlewareFunc {
 	return m.RequiredRole(domain.AdminRole)
 }
 
+// RequireAdminTwoFactor rechaza a los administradores cuya sesión no completó la verificación en dos pasos.
+// Es administrador todo rol con alguno de los domain.AdminPermissions, no solo ADMIN_ROLE. Con required en
+// false no aplica ninguna restricción. Las API keys no tienen segundo paso: quedan exentas porque se crean
+// desde una sesión que sí debió completarlo.
+func (m *JWTMiddleware) RequireAdminTwoFactor(required bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if !required {
+			return next
+		}
+
+		return func(c echo.Context) error {
+			userRole, _ := c.Get("user_role").(string)
+			twoFactor, _ := c.Get("two_factor").(bool)
+			apiKeyID, _ := c.Get("api_key_id").(string)
+
+			if twoFactor || apiKeyID != "" {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+
+			isAdmin, err := m.permissions.IsAdmin(ctx, userRole)
+			if err != nil {
+				logger.LogError(ctx, dto.MsgPermissionLookupFailed,
+					logger.String("role", userRole),
+					logger.Error("error", err),
+				)
+				return c.JSON(http.StatusInternalServerError, map[string]any{
+					"code":    http.StatusInternalServerError,
+					"message": dto.ErrInternalServer,
+					"status":  "Internal Server Error",
+				})
+			}
+
+			if isAdmin {
+				return c.JSON(http.StatusForbidden, map[string]any{
+					"code":    http.StatusForbidden,
+					"message": dto.ErrTwoFactorLoginRequired,
+					"status":  "Forbidden",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// ForbidImpersonation rechaza las sesiones de suplantación en acciones que solo puede ejecutar el propio usuario
+func (m *JWTMiddleware) ForbidImpersonation() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if actorID, _ := c.Get("actor_id").(string); actorID != "" {
+				return c.JSON(http.StatusForbidden, map[string]any{
+					"code":    http.StatusForbidden,
+					"message": dto.ErrImpersonationActionForbidden,
+					"status":  "Forbidden",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// RequirePermission permite el acceso solo si el rol del token tiene el permiso indicado.
+// Los permisos se consultan en la base de datos a través de una caché con TTL. Con una API key el
+// permiso debe estar además entre sus alcances, de modo que nunca supera al rol actual de su dueño.
+func (m *JWTMiddleware) RequirePermission(permission string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userRole, ok := c.Get("user_role").(string)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]any{
+					"code":    http.StatusUnauthorized,
+					"message": dto.ErrTokenMissing,
+					"status":  "Unauthorized",
+				})
+			}
+
+			ctx := c.Request().Context()
+
+			allowed, err := m.permissions.HasPermission(ctx, userRole, permission)
+			if err != nil {
+				logger.LogError(ctx, dto.MsgPermissionLookupFailed,
+					logger.String("role", userRole),
+					logger.String("permission", permission),
+					logger.Error("error", err),
+				)
+				return c.JSON(http.StatusInternalServerError, map[string]any{
+					"code":    http.StatusInternalServerError,
+					"message": dto.ErrInternalServer,
+					"status":  "Internal Server Error",
+				})
+			}
+
+			if scopes, ok := c.Get("api_key_scopes").([]string); ok && !slices.Contains(scopes, permission) {
+				allowed = false
+			}
+
+			if !allowed {
+				return c.JSON(http.StatusForbidden, map[string]any{
+					"code":    http.StatusForbidden,
+					"message": dto.ErrInsufficientPermissions,
+					"status":  "Forbidden",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
+
 func (m *JWTMiddleware) RequireAnyRole(allowedRoles ...string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -0,0 +1,347 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/adapter/cookie"
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	testAccessToken       = "valid-access-token"
+	testTerminatedToken   = "terminated-session-token"
+	testTerminatedSession = "terminated-session"
+	testSessionlessToken  = "sessionless-token"
+	testRevokedToken      = "revoked-token"
+)
+
+// testTokenSessions asocia los tokens aceptados por fakeJWTService con su claim "sid"
+var testTokenSessions = map[string]string{
+	testAccessToken:      "session-1",
+	testTerminatedToken:  testTerminatedSession,
+	testSessionlessToken: "",
+	testRevokedToken:     "session-1",
+}
+
+// testTokenIssuedAt es la emisión de todos los tokens de fakeJWTService
+var testTokenIssuedAt = time.Now().Add(-time.Minute)
+
+type fakeJWTService struct{}
+
+func (fakeJWTService) GenerateToken(domain.User, domain.TokenOptions) (string, error) {
+	return testAccessToken, nil
+}
+
+func (fakeJWTService) ValidateToken(token string) (*domain.TokenClaims, error) {
+	sessionID, ok := testTokenSessions[token]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	return &domain.TokenClaims{TokenID: "jti-" + token, UserID: "user-1", Role: domain.UserRole, IssuedAt: testTokenIssuedAt, ExpiresAt: time.Now().Add(time.Minute), SessionID: sessionID}, nil
+}
+
+// fakeRevocation solo tiene en la lista el jti de testRevokedToken
+type fakeRevocation struct{}
+
+func (fakeRevocation) Revoke(context.Context, *domain.TokenClaims) error { return nil }
+func (fakeRevocation) IsRevoked(tokenID string) bool                     { return tokenID == "jti-"+testRevokedToken }
+func (fakeRevocation) Start(context.Context, time.Duration) error        { return nil }
+
+type fakeTokenVersions struct{}
+
+func (fakeTokenVersions) IsCurrent(context.Context, string, int) (bool, error) { return true, nil }
+
+type fakeSessions struct{}
+
+func (fakeSessions) IsActive(_ context.Context, sessionID string) (bool, error) {
+	return sessionID != testTerminatedSession, nil
+}
+func (fakeSessions) ListByUser(context.Context, string) ([]*domain.Session, error) { return nil, nil }
+func (fakeSessions) Terminate(context.Context, string, string) error               { return nil }
+
+type fakePermissions struct{}
+
+func (fakePermissions) HasPermission(context.Context, string, string) (bool, error) { return true, nil }
+func (fakePermissions) IsAdmin(context.Context, string) (bool, error)               { return true, nil }
+func (fakePermissions) Invalidate(string)                                           {}
+
+const testUnavailableRole = "UNAVAILABLE_ROLE"
+
+// rolePermissions simula la tabla de permisos por rol; testUnavailableRole falla como si la base no respondiera
+type rolePermissions map[string][]string
+
+func (r rolePermissions) HasPermission(_ context.Context, role, permission string) (bool, error) {
+	if role == testUnavailableRole {
+		return false, errors.New("database unavailable")
+	}
+	return slices.Contains(r[role], permission), nil
+}
+
+func (r rolePermissions) IsAdmin(_ context.Context, role string) (bool, error) {
+	if role == testUnavailableRole {
+		return false, errors.New("database unavailable")
+	}
+	return domain.HasAdminPermission(r[role]), nil
+}
+
+func (rolePermissions) Invalidate(string) {}
+
+var testRolePermissions = rolePermissions{
+	domain.UserRole:  {},
+	domain.AdminRole: domain.Permissions,
+	"SUPPORT_ROLE":   {domain.PermissionUsersRead, domain.PermissionUsersWrite},
+	"AUDITOR_ROLE":   {domain.PermissionAuditRead},
+}
+
+func newTestJWTMiddleware(sessions *cookie.SessionCookies) *JWTMiddleware {
+	return NewJWTMiddleware(fakeJWTService{}, fakeRevocation{}, fakeTokenVersions{}, fakeSessions{}, fakePermissions{}, sessions, fakeAPIKeys{})
+}
+
+func TestJWTMiddleware_APIKey(t *testing.T) {
+	mw := newTestJWTMiddleware(cookie.NewSessionCookies(cookie.SessionCookieConfig{}))
+
+	tests := []struct {
+		name     string
+		header   string
+		chain    []echo.MiddlewareFunc
+		wantCode int
+	}{
+		{
+			name:     "scope granted",
+			header:   "ApiKey " + testAPIKey,
+			chain:    []echo.MiddlewareFunc{mw.AuthenticateWithAPIKey(), mw.RequirePermission(domain.PermissionUsersRead)},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "scope missing although the owner role has it",
+			header:   "ApiKey " + testAPIKey,
+			chain:    []echo.MiddlewareFunc{mw.AuthenticateWithAPIKey(), mw.RequirePermission(domain.PermissionUsersWrite)},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "admin two factor does not apply to api keys",
+			header:   "ApiKey " + testAPIKey,
+			chain:    []echo.MiddlewareFunc{mw.AuthenticateWithAPIKey(), mw.RequireAdminTwoFactor(true)},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "route without api key support",
+			header:   "ApiKey " + testAPIKey,
+			chain:    []echo.MiddlewareFunc{mw.Authenticate()},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "unknown key",
+			header:   "ApiKey appfe_unknown",
+			chain:    []echo.MiddlewareFunc{mw.AuthenticateWithAPIKey()},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "bearer token still accepted",
+			header:   "Bearer " + testAccessToken,
+			chain:    []echo.MiddlewareFunc{mw.AuthenticateWithAPIKey(), mw.RequirePermission(domain.PermissionUsersWrite)},
+			wantCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+			req.Header.Set("Authorization", tt.header)
+			rec := httptest.NewRecorder()
+
+			handler := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+			for i := len(tt.chain) - 1; i >= 0; i-- {
+				handler = tt.chain[i](handler)
+			}
+
+			if err := handler(e.NewContext(req, rec)); err != nil {
+				t.Fatalf("handler error: %v", err)
+			}
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestJWTMiddleware_APIKeyContext(t *testing.T) {
+	mw := newTestJWTMiddleware(cookie.NewSessionCookies(cookie.SessionCookieConfig{}))
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+	req.Header.Set("Authorization", "ApiKey "+testAPIKey)
+	rec := httptest.NewRecorder()
+
+	handler := mw.AuthenticateWithAPIKey()(func(c echo.Context) error {
+		meta := domain.RequestMetadataFromContext(c.Request().Context())
+		if c.Get("user_id") != "admin-1" || meta.ActorID != "admin-1" || meta.APIKeyID != "key-1" {
+			t.Errorf("user_id = %v, actor = %q, api key = %q", c.Get("user_id"), meta.ActorID, meta.APIKeyID)
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	if err := handler(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+}
+
+func TestJWTMiddleware_EndedTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		cutoff   time.Time
+		wantCode int
+	}{
+		{name: "active session", token: testAccessToken, wantCode: http.StatusOK},
+		{name: "terminated session", token: testTerminatedToken, wantCode: http.StatusUnauthorized},
+		{name: "denylisted jti", token: testRevokedToken, wantCode: http.StatusUnauthorized},
+		{name: "without session issued before the cutoff", token: testSessionlessToken, cutoff: time.Now(), wantCode: http.StatusOK},
+		{name: "without session issued after the cutoff", token: testSessionlessToken, cutoff: testTokenIssuedAt.Add(-time.Second), wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mw := newTestJWTMiddleware(cookie.NewSessionCookies(cookie.SessionCookieConfig{}))
+			if !tt.cutoff.IsZero() {
+				mw.sessionlessCutoff = tt.cutoff
+			}
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+
+			handler := mw.Authenticate()(func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+			if err := handler(e.NewContext(req, rec)); err != nil {
+				t.Fatalf("handler error: %v", err)
+			}
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+// serveWithContext ejecuta middleware con los valores que Authenticate deja en el contexto
+func serveWithContext(t *testing.T, middleware echo.MiddlewareFunc, values map[string]any) int {
+	t.Helper()
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	for key, value := range values {
+		c.Set(key, value)
+	}
+
+	handler := middleware(func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	if err := handler(c); err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	return rec.Code
+}
+
+func TestJWTMiddleware_RequirePermission(t *testing.T) {
+	mw := NewJWTMiddleware(fakeJWTService{}, fakeRevocation{}, fakeTokenVersions{}, fakeSessions{}, testRolePermissions, cookie.NewSessionCookies(cookie.SessionCookieConfig{}), fakeAPIKeys{})
+
+	tests := []struct {
+		name       string
+		permission string
+		values     map[string]any
+		wantCode   int
+	}{
+		{name: "role has permission", permission: domain.PermissionUsersWrite, values: map[string]any{"user_role": "SUPPORT_ROLE"}, wantCode: http.StatusOK},
+		{name: "role lacks permission", permission: domain.PermissionRolesManage, values: map[string]any{"user_role": "SUPPORT_ROLE"}, wantCode: http.StatusForbidden},
+		{name: "role without permissions", permission: domain.PermissionUsersRead, values: map[string]any{"user_role": domain.UserRole}, wantCode: http.StatusForbidden},
+		{name: "unknown role", permission: domain.PermissionUsersRead, values: map[string]any{"user_role": "DELETED_ROLE"}, wantCode: http.StatusForbidden},
+		{name: "not authenticated", permission: domain.PermissionUsersRead, wantCode: http.StatusUnauthorized},
+		{name: "permission lookup fails", permission: domain.PermissionUsersRead, values: map[string]any{"user_role": testUnavailableRole}, wantCode: http.StatusInternalServerError},
+		{
+			name:       "api key scope granted",
+			permission: domain.PermissionUsersRead,
+			values:     map[string]any{"user_role": domain.AdminRole, "api_key_scopes": []string{domain.PermissionUsersRead}},
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "api key scope missing",
+			permission: domain.PermissionUsersWrite,
+			values:     map[string]any{"user_role": domain.AdminRole, "api_key_scopes": []string{domain.PermissionUsersRead}},
+			wantCode:   http.StatusForbidden,
+		},
+		{
+			name:       "api key scope exceeds owner role",
+			permission: domain.PermissionAuditRead,
+			values:     map[string]any{"user_role": "SUPPORT_ROLE", "api_key_scopes": []string{domain.PermissionAuditRead}},
+			wantCode:   http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serveWithContext(t, mw.RequirePermission(tt.permission), tt.values); got != tt.wantCode {
+				t.Errorf("status = %d, want %d", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestJWTMiddleware_RequireAdminTwoFactor(t *testing.T) {
+	mw := NewJWTMiddleware(fakeJWTService{}, fakeRevocation{}, fakeTokenVersions{}, fakeSessions{}, testRolePermissions, cookie.NewSessionCookies(cookie.SessionCookieConfig{}), fakeAPIKeys{})
+
+	tests := []struct {
+		name     string
+		required bool
+		values   map[string]any
+		wantCode int
+	}{
+		{name: "admin role without two factor", required: true, values: map[string]any{"user_role": domain.AdminRole}, wantCode: http.StatusForbidden},
+		{name: "custom role with admin permissions", required: true, values: map[string]any{"user_role": "SUPPORT_ROLE"}, wantCode: http.StatusForbidden},
+		{name: "role without admin permissions", required: true, values: map[string]any{"user_role": "AUDITOR_ROLE"}, wantCode: http.StatusOK},
+		{name: "admin with two factor", required: true, values: map[string]any{"user_role": domain.AdminRole, "two_factor": true}, wantCode: http.StatusOK},
+		{name: "api key", required: true, values: map[string]any{"user_role": domain.AdminRole, "api_key_id": "key-1"}, wantCode: http.StatusOK},
+		{name: "policy disabled", values: map[string]any{"user_role": domain.AdminRole}, wantCode: http.StatusOK},
+		{name: "permission lookup fails", required: true, values: map[string]any{"user_role": testUnavailableRole}, wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serveWithContext(t, mw.RequireAdminTwoFactor(tt.required), tt.values); got != tt.wantCode {
+				t.Errorf("status = %d, want %d", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestJWTMiddleware_ForbidImpersonation(t *testing.T) {
+	mw := newTestJWTMiddleware(cookie.NewSessionCookies(cookie.SessionCookieConfig{}))
+
+	tests := []struct {
+		name     string
+		values   map[string]any
+		wantCode int
+	}{
+		{name: "own session", values: map[string]any{"user_id": "user-1"}, wantCode: http.StatusOK},
+		{name: "impersonation", values: map[string]any{"user_id": "user-1", "actor_id": "admin-1"}, wantCode: http.StatusForbidden},
+		{name: "empty actor", values: map[string]any{"user_id": "user-1", "actor_id": ""}, wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serveWithContext(t, mw.ForbidImpersonation(), tt.values); got != tt.wantCode {
+				t.Errorf("status = %d, want %d", got, tt.wantCode)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"time"
 
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
 	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
 	"github.com/JacobD36/appfe_frontpage_api/pkg/logger"
 	"github.com/labstack/echo/v4"
@@ -60,6 +61,11 @@ func LoggerWithConfig(config LoggerConfig) echo.MiddlewareFunc {
 			// Agregar request ID al contexto para que esté disponible en los logs
 			ctx := c.Request().Context()
 			ctx = context.WithValue(ctx, RequestIDKey, requestID)
+			ctx = domain.WithRequestMetadata(ctx, domain.RequestMetadata{
+				IP:        c.RealIP(),
+				UserAgent: c.Request().UserAgent(),
+				RequestID: requestID,
+			})
 			c.SetRequest(c.Request().WithContext(ctx))
 
 			// Log del inicio de la request
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimit define un token bucket: Requests es la capacidad (ráfaga máxima) y Per el tiempo
+// en que el bucket se rellena por completo. Un límite con Requests en cero queda deshabilitado.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+func (l RateLimit) Enabled() bool {
+	return l.Requests > 0 && l.Per > 0
+}
+
+// ParseRateLimit interpreta el formato "<requests>/<duración>" (ej. "20/1m"); "off" deshabilita el límite
+func ParseRateLimit(value string) (RateLimit, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "off") {
+		return RateLimit{}, nil
+	}
+
+	requests, per, found := strings.Cut(value, "/")
+	if !found {
+		return RateLimit{}, errors.New(dto.ErrInvalidRateLimitFormat)
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil || n < 0 {
+		return RateLimit{}, errors.New(dto.ErrInvalidRateLimitFormat)
+	}
+
+	d, err := time.ParseDuration(per)
+	if err != nil || d <= 0 {
+		return RateLimit{}, errors.New(dto.ErrInvalidRateLimitFormat)
+	}
+
+	return RateLimit{Requests: n, Per: d}, nil
+}
+
+// RateLimitConfig agrupa los límites de cada grupo de rutas
+type RateLimitConfig struct {
+	// Auth aplica a los endpoints públicos de /auth, por IP
+	Auth RateLimit
+
+	// Admin aplica a la administración de usuarios, por usuario autenticado
+	Admin RateLimit
+
+	// Me aplica a las rutas de la cuenta propia, por usuario autenticado
+	Me RateLimit
+
+	// Public queda reservado para el contenido público de la portada
+	Public RateLimit
+}
+
+var DefaultRateLimitConfig = RateLimitConfig{
+	Auth:   RateLimit{Requests: 20, Per: time.Minute},
+	Admin:  RateLimit{Requests: 300, Per: time.Minute},
+	Me:     RateLimit{Requests: 60, Per: time.Minute},
+	Public: RateLimit{Requests: 120, Per: time.Minute},
+}
+
+// NewRateLimitConfigFromEnv lee los límites desde variables de entorno; los valores inválidos usan el valor por defecto
+func NewRateLimitConfigFromEnv() RateLimitConfig {
+	return RateLimitConfig{
+		Auth:   rateLimitFromEnv(dto.EnvRateLimitAuth, DefaultRateLimitConfig.Auth),
+		Admin:  rateLimitFromEnv(dto.EnvRateLimitAdmin, DefaultRateLimitConfig.Admin),
+		Me:     rateLimitFromEnv(dto.EnvRateLimitMe, DefaultRateLimitConfig.Me),
+		Public: rateLimitFromEnv(dto.EnvRateLimitPublic, DefaultRateLimitConfig.Public),
+	}
+}
+
+func rateLimitFromEnv(key string, fallback RateLimit) RateLimit {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	limit, err := ParseRateLimit(value)
+	if err != nil {
+		logger.Warn(context.Background(), dto.MsgInvalidRateLimitConfig,
+			logger.String("key", key),
+			logger.String("value", value),
+		)
+		return fallback
+	}
+
+	return limit
+}
+
+// RateLimitStore guarda el estado de los buckets. La implementación en memoria sirve para una
+// sola instancia; varias réplicas deben compartir un store externo (ej. Redis).
+type RateLimitStore interface {
+	// Take consume un token del bucket key. Si no quedan tokens retorna allowed=false y el tiempo
+	// hasta que haya uno disponible.
+	Take(ctx context.Context, key string, limit RateLimit) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// KeyFunc obtiene la clave que identifica a quien realiza la petición
+type KeyFunc func(c echo.Context) string
+
+// KeyByIP limita por dirección IP del cliente resuelta con el IPExtractor de Echo. Sin extractor configurado
+// usa la IP de la conexión: el fallback de RealIP lee X-Forwarded-For y X-Real-IP, que el cliente puede
+// cambiar en cada petición para estrenar un bucket nuevo.
+func KeyByIP(c echo.Context) string {
+	if c.Echo().IPExtractor == nil {
+		return "ip:" + echo.ExtractIPDirect()(c.Request())
+	}
+	return "ip:" + c.RealIP()
+}
+
+// KeyByUserID limita por el user_id que deja JWTMiddleware.Authenticate; sin usuario recurre a la IP
+func KeyByUserID(c echo.Context) string {
+	if userID, ok := c.Get("user_id").(string); ok && userID != "" {
+		return "user:" + userID
+	}
+	return KeyByIP(c)
+}
+
+type RateLimiter struct {
+	store RateLimitStore
+}
+
+func NewRateLimiter(store RateLimitStore) *RateLimiter {
+	return &RateLimiter{store: store}
+}
+
+// Limit crea un middleware que aplica limit a cada clave dentro del grupo name.
+// Si el store falla la petición se deja pasar para no tumbar la API por el limitador.
+func (r *RateLimiter) Limit(name string, limit RateLimit, keyFunc KeyFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if !limit.Enabled() {
+			return next
+		}
+
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			key := name + ":" + keyFunc(c)
+
+			allowed, retryAfter, err := r.store.Take(ctx, key, limit)
+			if err != nil {
+				logger.Warn(ctx, dto.MsgRateLimitStoreFailed,
+					logger.String("key", key),
+					logger.Error("error", err),
+				)
+				return next(c)
+			}
+
+			if !allowed {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				if seconds < 1 {
+					seconds = 1
+				}
+				c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(seconds))
+
+				return c.JSON(http.StatusTooManyRequests, map[string]any{
+					"code":    http.StatusTooManyRequests,
+					"message": dto.ErrRateLimitExceeded,
+					"status":  "Too Many Requests",
+					"data":    nil,
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryRateLimitSweepInterval define cada cuánto se eliminan los buckets inactivos
+const memoryRateLimitSweepInterval = time.Minute
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	per    time.Duration
+}
+
+// MemoryRateLimitStore implementa RateLimitStore con buckets en memoria del proceso
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, limit RateLimit) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	capacity := float64(limit.Requests)
+	refillPerSecond := capacity / limit.Per.Seconds()
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, last: now}
+		s.buckets[key] = bucket
+	}
+	bucket.per = limit.Per
+
+	elapsed := now.Sub(bucket.last).Seconds()
+	if elapsed > 0 {
+		bucket.tokens = min(capacity, bucket.tokens+elapsed*refillPerSecond)
+		bucket.last = now
+	}
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0, nil
+	}
+
+	missing := 1 - bucket.tokens
+	retryAfter := time.Duration(missing / refillPerSecond * float64(time.Second))
+
+	return false, retryAfter, nil
+}
+
+// sweep descarta los buckets que ya se habrían rellenado por completo; recrearlos es equivalente
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memoryRateLimitSweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, bucket := range s.buckets {
+		if now.Sub(bucket.last) >= bucket.per {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    RateLimit
+		wantErr bool
+	}{
+		{name: "valid", value: "20/1m", want: RateLimit{Requests: 20, Per: time.Minute}},
+		{name: "off", value: "off", want: RateLimit{}},
+		{name: "missing separator", value: "20", wantErr: true},
+		{name: "invalid duration", value: "20/abc", wantErr: true},
+		{name: "negative requests", value: "-1/1m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRateLimit(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRateLimit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseRateLimit() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryRateLimitStore_Take(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	limit := RateLimit{Requests: 2, Per: 10 * time.Second}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if allowed, _, _ := store.Take(ctx, "k", limit); !allowed {
+			t.Fatalf("request %d should be allowed", i+1)
+		}
+	}
+
+	allowed, retryAfter, _ := store.Take(ctx, "k", limit)
+	if allowed {
+		t.Fatal("third request should be rejected")
+	}
+	if retryAfter != 5*time.Second {
+		t.Errorf("retryAfter = %v, want 5s", retryAfter)
+	}
+
+	if allowed, _, _ := store.Take(ctx, "other", limit); !allowed {
+		t.Error("buckets must be independent per key")
+	}
+
+	now = now.Add(5 * time.Second)
+	if allowed, _, _ := store.Take(ctx, "k", limit); !allowed {
+		t.Error("a token should be refilled after 5s")
+	}
+}
+
+func TestRateLimiter_Limit(t *testing.T) {
+	e := echo.New()
+	limiter := NewRateLimiter(NewMemoryRateLimitStore())
+	handler := limiter.Limit("test", RateLimit{Requests: 1, Per: time.Minute}, KeyByIP)(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		rec := httptest.NewRecorder()
+		if err := handler(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("handler returned error: %v", err)
+		}
+		return rec
+	}
+
+	if rec := serve(); rec.Code != http.StatusOK {
+		t.Fatalf("first request status = %d, want 200", rec.Code)
+	}
+
+	rec := serve()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request status = %d, want 429", rec.Code)
+	}
+	if rec.Header().Get(echo.HeaderRetryAfter) != "60" {
+		t.Errorf("Retry-After = %q, want 60", rec.Header().Get(echo.HeaderRetryAfter))
+	}
+}
+
+func TestKeyByIP(t *testing.T) {
+	tests := []struct {
+		name        string
+		ipExtractor echo.IPExtractor
+		want        string
+	}{
+		{name: "no extractor uses connection ip", want: "ip:10.0.0.2"},
+		{name: "direct extractor", ipExtractor: echo.ExtractIPDirect(), want: "ip:10.0.0.2"},
+		{name: "untrusted forwarded header", ipExtractor: NewIPExtractor("192.0.2.0/24"), want: "ip:10.0.0.2"},
+		{name: "trusted proxy", ipExtractor: NewIPExtractor("10.0.0.0/8"), want: "ip:198.51.100.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.IPExtractor = tt.ipExtractor
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.RemoteAddr = "10.0.0.2:1234"
+			req.Header.Set(echo.HeaderXForwardedFor, "198.51.100.1")
+			req.Header.Set(echo.HeaderXRealIP, "198.51.100.2")
+
+			if got := KeyByIP(e.NewContext(req, httptest.NewRecorder())); got != tt.want {
+				t.Errorf("KeyByIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
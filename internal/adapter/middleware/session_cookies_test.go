@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/adapter/cookie"
+	"github.com/labstack/echo/v4"
+)
+
+func newTestSessionCookies() *cookie.SessionCookies {
+	return cookie.NewSessionCookies(cookie.SessionCookieConfig{
+		Enabled:         true,
+		Secure:          true,
+		SameSite:        http.SameSiteStrictMode,
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: time.Hour,
+		CSRFSecret:      []byte("test-csrf-secret-with-32-characters"),
+	})
+}
+
+// testCSRFToken retorna el token CSRF que SetSession emite para la sesión
+func testCSRFToken(sessions *cookie.SessionCookies, sessionID string) string {
+	rec := httptest.NewRecorder()
+	sessions.SetSession(echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec), sessionID, "access", "refresh")
+	return rec.Header().Get(cookie.CSRFTokenHeader)
+}
+
+func TestJWTMiddleware_SessionCookie(t *testing.T) {
+	sessions := newTestSessionCookies()
+	mw := newTestJWTMiddleware(sessions)
+
+	valid := testCSRFToken(sessions, testTokenSessions[testAccessToken])
+	otherSession := testCSRFToken(sessions, "session-2")
+
+	tests := []struct {
+		name       string
+		method     string
+		header     string
+		cookie     string
+		csrfCookie string
+		csrf       string
+		wantCode   int
+	}{
+		{name: "cookie on safe method", method: http.MethodGet, cookie: testAccessToken, wantCode: http.StatusOK},
+		{name: "cookie with csrf token", method: http.MethodPost, cookie: testAccessToken, csrfCookie: valid, csrf: valid, wantCode: http.StatusOK},
+		{name: "cookie without csrf token", method: http.MethodPost, cookie: testAccessToken, csrfCookie: valid, wantCode: http.StatusForbidden},
+		{name: "cookie with wrong csrf token", method: http.MethodDelete, cookie: testAccessToken, csrfCookie: valid, csrf: "other", wantCode: http.StatusForbidden},
+		{name: "csrf token of another session", method: http.MethodPost, cookie: testAccessToken, csrfCookie: otherSession, csrf: otherSession, wantCode: http.StatusForbidden},
+		{name: "unsigned csrf token", method: http.MethodPost, cookie: testAccessToken, csrfCookie: "session-1.forged", csrf: "session-1.forged", wantCode: http.StatusForbidden},
+		{name: "bearer header needs no csrf", method: http.MethodPost, header: "Bearer " + testAccessToken, wantCode: http.StatusOK},
+		{name: "invalid cookie", method: http.MethodGet, cookie: "forged", wantCode: http.StatusUnauthorized},
+		{name: "no credentials", method: http.MethodGet, wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(tt.method, "/api/v1/me", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: cookie.AccessTokenCookie, Value: tt.cookie})
+			}
+			if tt.csrfCookie != "" {
+				req.AddCookie(&http.Cookie{Name: cookie.CSRFTokenCookie, Value: tt.csrfCookie})
+			}
+			if tt.csrf != "" {
+				req.Header.Set(cookie.CSRFTokenHeader, tt.csrf)
+			}
+			rec := httptest.NewRecorder()
+
+			handler := mw.Authenticate()(func(c echo.Context) error {
+				return c.String(http.StatusOK, c.Get("user_id").(string))
+			})
+			if err := handler(e.NewContext(req, rec)); err != nil {
+				t.Fatalf("handler error: %v", err)
+			}
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestJWTMiddleware_SessionCookieDisabled(t *testing.T) {
+	sessions := cookie.NewSessionCookies(cookie.SessionCookieConfig{})
+	mw := newTestJWTMiddleware(sessions)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+	req.AddCookie(&http.Cookie{Name: cookie.AccessTokenCookie, Value: testAccessToken})
+	rec := httptest.NewRecorder()
+
+	handler := mw.Authenticate()(func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	if err := handler(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	pgxAPIKeyTableCreate = `
+	CREATE TABLE IF NOT EXISTS api_keys (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+        name VARCHAR(100) NOT NULL,
+        prefix VARCHAR(32) NOT NULL,
+        key_hash VARCHAR(128) NOT NULL UNIQUE,
+        scopes TEXT[] NOT NULL DEFAULT '{}',
+        created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+        expires_at TIMESTAMPTZ,
+        last_used_at TIMESTAMPTZ,
+        revoked_at TIMESTAMPTZ,
+        created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    );`
+	pgxAPIKeyCreate = `
+	INSERT INTO api_keys (name, prefix, key_hash, scopes, created_by, expires_at, created_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7)
+    RETURNING id;`
+	pgxAPIKeyColumns     = `id, name, prefix, key_hash, scopes, created_by, expires_at, last_used_at, revoked_at, created_at`
+	pgxAPIKeyFindByID    = `SELECT ` + pgxAPIKeyColumns + ` FROM api_keys WHERE id = $1;`
+	pgxAPIKeyFindByHash  = `SELECT ` + pgxAPIKeyColumns + ` FROM api_keys WHERE key_hash = $1;`
+	pgxAPIKeyListByOwner = `SELECT ` + pgxAPIKeyColumns + ` FROM api_keys WHERE created_by = $1 ORDER BY created_at DESC;`
+	pgxAPIKeyRevoke      = `UPDATE api_keys
+		SET revoked_at = $1
+		WHERE id = $2 AND revoked_at IS NULL;`
+	pgxAPIKeyTouchLastUsed = `UPDATE api_keys
+		SET last_used_at = $1
+		WHERE id = $2 AND (last_used_at IS NULL OR last_used_at < $3);`
+)
+
+type pgxAPIKeyRepository struct {
+	db pgx.Tx
+}
+
+func NewPgxAPIKey(db pgx.Tx) ui.APIKeyRepository {
+	return &pgxAPIKeyRepository{db}
+}
+
+func (r *pgxAPIKeyRepository) Migrate(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, pgxAPIKeyTableCreate)
+	return err
+}
+
+func (r *pgxAPIKeyRepository) Create(ctx context.Context, k *domain.APIKey) error {
+	return r.db.QueryRow(ctx, pgxAPIKeyCreate,
+		k.Name,
+		k.Prefix,
+		k.KeyHash,
+		k.Scopes,
+		k.CreatedBy,
+		k.ExpiresAt,
+		k.CreatedAt,
+	).Scan(&k.ID)
+}
+
+func (r *pgxAPIKeyRepository) FindByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	return scanAPIKey(r.db.QueryRow(ctx, pgxAPIKeyFindByID, id))
+}
+
+func (r *pgxAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	return scanAPIKey(r.db.QueryRow(ctx, pgxAPIKeyFindByHash, keyHash))
+}
+
+func (r *pgxAPIKeyRepository) ListByOwner(ctx context.Context, ownerID string) ([]*domain.APIKey, error) {
+	rows, err := r.db.Query(ctx, pgxAPIKeyListByOwner, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]*domain.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *pgxAPIKeyRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	_, err := r.db.Exec(ctx, pgxAPIKeyRevoke, at, id)
+	return err
+}
+
+func (r *pgxAPIKeyRepository) TouchLastUsed(ctx context.Context, id string, now, since time.Time) error {
+	_, err := r.db.Exec(ctx, pgxAPIKeyTouchLastUsed, now, id, since)
+	return err
+}
+
+func scanAPIKey(s interfaces.Scanner) (*domain.APIKey, error) {
+	k := &domain.APIKey{}
+
+	err := s.Scan(
+		&k.ID,
+		&k.Name,
+		&k.Prefix,
+		&k.KeyHash,
+		&k.Scopes,
+		&k.CreatedBy,
+		&k.ExpiresAt,
+		&k.LastUsedAt,
+		&k.RevokedAt,
+		&k.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// actor_id y target_id no tienen clave foránea: el registro debe sobrevivir a los recursos que describe
+	pgxAuditEventTableCreate = `
+	CREATE TABLE IF NOT EXISTS audit_events (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+        event_type VARCHAR(64) NOT NULL,
+        actor_id TEXT,
+        target_type VARCHAR(32),
+        target_id TEXT,
+        ip VARCHAR(64) NOT NULL DEFAULT '',
+        user_agent TEXT NOT NULL DEFAULT '',
+        request_id VARCHAR(64) NOT NULL DEFAULT '',
+        details JSONB,
+        created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    );
+	CREATE INDEX IF NOT EXISTS idx_audit_events_created ON audit_events (created_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events (actor_id, created_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_audit_events_target ON audit_events (target_id, created_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_audit_events_type ON audit_events (event_type, created_at DESC);`
+	pgxAuditEventCreate = `
+	INSERT INTO audit_events (event_type, actor_id, target_type, target_id, ip, user_agent, request_id, details, created_at)
+    VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9)
+    RETURNING id;`
+	pgxAuditEventList = `SELECT id, event_type, actor_id, COALESCE(target_type, ''), target_id, ip, user_agent, request_id, details, created_at
+		FROM audit_events
+		%s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d;`
+	pgxAuditEventCount = `SELECT COUNT(*) FROM audit_events %s;`
+)
+
+type pgxAuditEventRepository struct {
+	db pgx.Tx
+}
+
+func NewPgxAuditEvent(db pgx.Tx) ui.AuditEventRepository {
+	return &pgxAuditEventRepository{db}
+}
+
+func (r *pgxAuditEventRepository) Migrate(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, pgxAuditEventTableCreate)
+	return err
+}
+
+func (r *pgxAuditEventRepository) Create(ctx context.Context, e *domain.AuditEvent) error {
+	return r.db.QueryRow(ctx, pgxAuditEventCreate,
+		e.Type,
+		e.ActorID,
+		e.TargetType,
+		e.TargetID,
+		e.IP,
+		e.UserAgent,
+		e.RequestID,
+		e.Details,
+		e.CreatedAt,
+	).Scan(&e.ID)
+}
+
+func (r *pgxAuditEventRepository) List(ctx context.Context, filter domain.AuditFilter, pagination *domain.Pagination) ([]*domain.AuditEvent, int64, error) {
+	where, args := auditEventWhere(filter)
+
+	var total int64
+	if err := r.db.QueryRow(ctx, fmt.Sprintf(pgxAuditEventCount, where), args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(pgxAuditEventList, where, len(args)+1, len(args)+2)
+	rows, err := r.db.Query(ctx, query, append(args, pagination.Limit, pagination.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var events []*domain.AuditEvent
+	for rows.Next() {
+		e := &domain.AuditEvent{}
+		if err := rows.Scan(
+			&e.ID,
+			&e.Type,
+			&e.ActorID,
+			&e.TargetType,
+			&e.TargetID,
+			&e.IP,
+			&e.UserAgent,
+			&e.RequestID,
+			&e.Details,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+// auditEventWhere arma la cláusula WHERE con un parámetro por cada filtro presente
+func auditEventWhere(filter domain.AuditFilter) (string, []any) {
+	conditions := []string{}
+	args := []any{}
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Type != "" {
+		add("event_type = $%d", filter.Type)
+	}
+	if filter.ActorID != "" {
+		add("actor_id = $%d", filter.ActorID)
+	}
+	if filter.TargetID != "" {
+		add("target_id = $%d", filter.TargetID)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at <= $%d", *filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
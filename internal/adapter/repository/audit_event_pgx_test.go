@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+func TestAuditEventWhere(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filter    domain.AuditFilter
+		wantWhere string
+		wantArgs  []any
+	}{
+		{name: "empty filter", filter: domain.AuditFilter{}, wantWhere: "", wantArgs: []any{}},
+		{name: "type only", filter: domain.AuditFilter{Type: "auth.login.failed"}, wantWhere: "WHERE event_type = $1", wantArgs: []any{"auth.login.failed"}},
+		{name: "date range", filter: domain.AuditFilter{From: &from, To: &to}, wantWhere: "WHERE created_at >= $1 AND created_at <= $2", wantArgs: []any{from, to}},
+		{
+			name:      "all filters keep placeholder order",
+			filter:    domain.AuditFilter{Type: "user.deleted", ActorID: "admin-1", TargetID: "user-1", From: &from, To: &to},
+			wantWhere: "WHERE event_type = $1 AND actor_id = $2 AND target_id = $3 AND created_at >= $4 AND created_at <= $5",
+			wantArgs:  []any{"user.deleted", "admin-1", "user-1", from, to},
+		},
+		{name: "actor and target without type", filter: domain.AuditFilter{ActorID: "admin-1", TargetID: "ADMIN_ROLE"}, wantWhere: "WHERE actor_id = $1 AND target_id = $2", wantArgs: []any{"admin-1", "ADMIN_ROLE"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := auditEventWhere(tt.filter)
+			if where != tt.wantWhere {
+				t.Errorf("auditEventWhere() where = %q, want %q", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("auditEventWhere() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
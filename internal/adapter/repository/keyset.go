@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+// keysetPage agrega a where la condición del cursor sobre (column, id) y devuelve el ORDER BY de la página.
+// Al retroceder el recorrido va en sentido inverso a desc; domain.NewCursorPaginatedResult restablece el orden.
+func keysetPage(where string, args []any, column string, desc bool, cursor *domain.Cursor) (string, []any, string) {
+	backward := cursor != nil && cursor.Backward
+
+	operator, direction := ">", "ASC"
+	if desc != backward {
+		operator, direction = "<", "DESC"
+	}
+
+	orderBy := fmt.Sprintf("%[1]s %[2]s, id %[2]s", column, direction)
+
+	if cursor == nil {
+		return where, args, orderBy
+	}
+
+	args = append(args, cursor.CreatedAt, cursor.ID)
+	condition := fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, operator, len(args)-1, len(args))
+
+	if where == "" {
+		return "WHERE " + condition, args, orderBy
+	}
+
+	return where + " AND " + condition, args, orderBy
+}
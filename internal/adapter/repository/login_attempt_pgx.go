@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	pgxLoginAttemptTableCreate = `
+	CREATE TABLE IF NOT EXISTS login_attempts (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+        user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+        email VARCHAR(255) NOT NULL,
+        ip VARCHAR(64) NOT NULL,
+        success BOOLEAN NOT NULL,
+        created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    );
+	CREATE INDEX IF NOT EXISTS idx_login_attempts_ip_created ON login_attempts (ip, created_at);
+	CREATE INDEX IF NOT EXISTS idx_login_attempts_created ON login_attempts (created_at);`
+	pgxLoginAttemptCreate = `
+	INSERT INTO login_attempts (user_id, email, ip, success, created_at)
+    VALUES ($1, $2, $3, $4, $5)
+    RETURNING id;`
+	pgxLoginAttemptCountFailedByIP = `SELECT COUNT(*) FROM login_attempts
+		WHERE ip = $1 AND success = false AND created_at >= $2;`
+	pgxLoginAttemptDeleteBefore = `DELETE FROM login_attempts WHERE created_at < $1;`
+
+	pgxAccountLockoutTableCreate = `
+	CREATE TABLE IF NOT EXISTS account_lockouts (
+        user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+        failed_count INTEGER NOT NULL DEFAULT 0,
+        lockout_count INTEGER NOT NULL DEFAULT 0,
+        locked_until TIMESTAMPTZ,
+        updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    );`
+	pgxAccountLockoutGet = `SELECT user_id, failed_count, lockout_count, locked_until, updated_at
+    FROM account_lockouts
+    WHERE user_id = $1
+    FOR UPDATE;`
+	pgxAccountLockoutSave = `
+	INSERT INTO account_lockouts (user_id, failed_count, lockout_count, locked_until, updated_at)
+    VALUES ($1, $2, $3, $4, $5)
+    ON CONFLICT (user_id) DO UPDATE
+    SET failed_count = EXCLUDED.failed_count,
+        lockout_count = EXCLUDED.lockout_count,
+        locked_until = EXCLUDED.locked_until,
+        updated_at = EXCLUDED.updated_at;`
+	pgxAccountLockoutReset = `DELETE FROM account_lockouts WHERE user_id = $1;`
+)
+
+type pgxLoginAttemptRepository struct {
+	db pgx.Tx
+}
+
+func NewPgxLoginAttempt(db pgx.Tx) ui.LoginAttemptRepository {
+	return &pgxLoginAttemptRepository{db}
+}
+
+func (r *pgxLoginAttemptRepository) Migrate(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, pgxLoginAttemptTableCreate)
+	return err
+}
+
+func (r *pgxLoginAttemptRepository) Create(ctx context.Context, a *domain.LoginAttempt) error {
+	return r.db.QueryRow(ctx, pgxLoginAttemptCreate,
+		a.UserID,
+		a.Email,
+		a.IP,
+		a.Success,
+		a.CreatedAt,
+	).Scan(&a.ID)
+}
+
+func (r *pgxLoginAttemptRepository) CountFailedByIP(ctx context.Context, ip string, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, pgxLoginAttemptCountFailedByIP, ip, since).Scan(&count)
+	return count, err
+}
+
+func (r *pgxLoginAttemptRepository) DeleteBefore(ctx context.Context, before time.Time) error {
+	_, err := r.db.Exec(ctx, pgxLoginAttemptDeleteBefore, before)
+	return err
+}
+
+type pgxAccountLockoutRepository struct {
+	db pgx.Tx
+}
+
+func NewPgxAccountLockout(db pgx.Tx) ui.AccountLockoutRepository {
+	return &pgxAccountLockoutRepository{db}
+}
+
+func (r *pgxAccountLockoutRepository) Migrate(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, pgxAccountLockoutTableCreate)
+	return err
+}
+
+func (r *pgxAccountLockoutRepository) Get(ctx context.Context, userID string) (*domain.AccountLockout, error) {
+	l := &domain.AccountLockout{}
+
+	err := r.db.QueryRow(ctx, pgxAccountLockoutGet, userID).Scan(
+		&l.UserID,
+		&l.FailedCount,
+		&l.LockoutCount,
+		&l.LockedUntil,
+		&l.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &domain.AccountLockout{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func (r *pgxAccountLockoutRepository) Save(ctx context.Context, l *domain.AccountLockout) error {
+	l.UpdatedAt = time.Now()
+	_, err := r.db.Exec(ctx, pgxAccountLockoutSave,
+		l.UserID,
+		l.FailedCount,
+		l.LockoutCount,
+		l.LockedUntil,
+		l.UpdatedAt,
+	)
+	return err
+}
+
+func (r *pgxAccountLockoutRepository) Reset(ctx context.Context, userID string) error {
+	_, err := r.db.Exec(ctx, pgxAccountLockoutReset, userID)
+	return err
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	pgxOIDCLinkTableCreate = `
+	CREATE TABLE IF NOT EXISTS oidc_links (
+        issuer TEXT NOT NULL,
+        subject TEXT NOT NULL,
+        user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+        method VARCHAR(32) NOT NULL,
+        created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (issuer, subject),
+        UNIQUE (user_id, issuer)
+    );`
+	pgxOIDCLinkCreate = `
+	INSERT INTO oidc_links (issuer, subject, user_id, method, created_at)
+    VALUES ($1, $2, $3, $4, $5);`
+	pgxOIDCLinkColumns       = `user_id, issuer, subject, method, created_at`
+	pgxOIDCLinkFindBySubject = `SELECT ` + pgxOIDCLinkColumns + ` FROM oidc_links WHERE issuer = $1 AND subject = $2;`
+	pgxOIDCLinkFindByUser    = `SELECT ` + pgxOIDCLinkColumns + ` FROM oidc_links WHERE user_id = $1 AND issuer = $2;`
+)
+
+type pgxOIDCLinkRepository struct {
+	db pgx.Tx
+}
+
+func NewPgxOIDCLink(db pgx.Tx) ui.OIDCLinkRepository {
+	return &pgxOIDCLinkRepository{db}
+}
+
+func (r *pgxOIDCLinkRepository) Migrate(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, pgxOIDCLinkTableCreate)
+	return err
+}
+
+func (r *pgxOIDCLinkRepository) Create(ctx context.Context, l *domain.OIDCLink) error {
+	_, err := r.db.Exec(ctx, pgxOIDCLinkCreate,
+		l.Issuer,
+		l.Subject,
+		l.UserID,
+		l.Method,
+		l.CreatedAt,
+	)
+	return err
+}
+
+func (r *pgxOIDCLinkRepository) FindBySubject(ctx context.Context, issuer, subject string) (*domain.OIDCLink, error) {
+	return scanOIDCLink(r.db.QueryRow(ctx, pgxOIDCLinkFindBySubject, issuer, subject))
+}
+
+func (r *pgxOIDCLinkRepository) FindByUser(ctx context.Context, userID, issuer string) (*domain.OIDCLink, error) {
+	return scanOIDCLink(r.db.QueryRow(ctx, pgxOIDCLinkFindByUser, userID, issuer))
+}
+
+func scanOIDCLink(s interfaces.Scanner) (*domain.OIDCLink, error) {
+	l := &domain.OIDCLink{}
+
+	if err := s.Scan(&l.UserID, &l.Issuer, &l.Subject, &l.Method, &l.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
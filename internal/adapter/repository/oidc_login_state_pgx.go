@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	pgxOIDCLoginStateTableCreate = `
+	CREATE TABLE IF NOT EXISTS oidc_login_states (
+        state_hash VARCHAR(128) PRIMARY KEY,
+        nonce TEXT NOT NULL,
+        code_verifier TEXT NOT NULL,
+        expires_at TIMESTAMPTZ NOT NULL,
+        created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    );
+	CREATE INDEX IF NOT EXISTS idx_oidc_login_states_expires ON oidc_login_states (expires_at);`
+	pgxOIDCLoginStateCreate = `
+	INSERT INTO oidc_login_states (state_hash, nonce, code_verifier, expires_at, created_at)
+    VALUES ($1, $2, $3, $4, $5);`
+	pgxOIDCLoginStateConsume = `DELETE FROM oidc_login_states
+		WHERE state_hash = $1
+		RETURNING state_hash, nonce, code_verifier, expires_at, created_at;`
+	pgxOIDCLoginStateDeleteExpired = `DELETE FROM oidc_login_states WHERE expires_at < $1;`
+)
+
+type pgxOIDCLoginStateRepository struct {
+	db pgx.Tx
+}
+
+func NewPgxOIDCLoginState(db pgx.Tx) ui.OIDCLoginStateRepository {
+	return &pgxOIDCLoginStateRepository{db}
+}
+
+func (r *pgxOIDCLoginStateRepository) Migrate(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, pgxOIDCLoginStateTableCreate)
+	return err
+}
+
+func (r *pgxOIDCLoginStateRepository) Create(ctx context.Context, s *domain.OIDCLoginState) error {
+	_, err := r.db.Exec(ctx, pgxOIDCLoginStateCreate,
+		s.StateHash,
+		s.Nonce,
+		s.CodeVerifier,
+		s.ExpiresAt,
+		s.CreatedAt,
+	)
+	return err
+}
+
+func (r *pgxOIDCLoginStateRepository) Consume(ctx context.Context, stateHash string) (*domain.OIDCLoginState, error) {
+	s := &domain.OIDCLoginState{}
+
+	err := r.db.QueryRow(ctx, pgxOIDCLoginStateConsume, stateHash).Scan(
+		&s.StateHash,
+		&s.Nonce,
+		&s.CodeVerifier,
+		&s.ExpiresAt,
+		&s.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (r *pgxOIDCLoginStateRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	_, err := r.db.Exec(ctx, pgxOIDCLoginStateDeleteExpired, before)
+	return err
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	pgxPasswordHistoryTableCreate = `
+	CREATE TABLE IF NOT EXISTS password_history (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+        user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+        password_hash TEXT NOT NULL,
+        created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    );
+	CREATE INDEX IF NOT EXISTS idx_password_history_user_created ON password_history (user_id, created_at DESC);`
+	pgxPasswordHistoryAdd = `
+	INSERT INTO password_history (user_id, password_hash, created_at)
+    VALUES ($1, $2, $3);`
+	pgxPasswordHistoryListRecent = `SELECT password_hash FROM password_history
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2;`
+	pgxPasswordHistoryPrune = `DELETE FROM password_history
+		WHERE user_id = $1 AND id NOT IN (
+			SELECT id FROM password_history
+			WHERE user_id = $1
+			ORDER BY created_at DESC
+			LIMIT $2
+		);`
+)
+
+type pgxPasswordHistoryRepository struct {
+	db pgx.Tx
+}
+
+func NewPgxPasswordHistory(db pgx.Tx) ui.PasswordHistoryRepository {
+	return &pgxPasswordHistoryRepository{db}
+}
+
+func (r *pgxPasswordHistoryRepository) Migrate(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, pgxPasswordHistoryTableCreate)
+	return err
+}
+
+func (r *pgxPasswordHistoryRepository) Add(ctx context.Context, userID, passwordHash string) error {
+	_, err := r.db.Exec(ctx, pgxPasswordHistoryAdd, userID, passwordHash, time.Now())
+	return err
+}
+
+func (r *pgxPasswordHistoryRepository) ListRecent(ctx context.Context, userID string, limit int) ([]string, error) {
+	rows, err := r.db.Query(ctx, pgxPasswordHistoryListRecent, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hashes := make([]string, 0, limit)
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, rows.Err()
+}
+
+func (r *pgxPasswordHistoryRepository) Prune(ctx context.Context, userID string, keep int) error {
+	_, err := r.db.Exec(ctx, pgxPasswordHistoryPrune, userID, keep)
+	return err
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	pgxRefreshTokenTableCreate = `
+	CREATE TABLE IF NOT EXISTS refresh_tokens (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+        user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+        family_id UUID NOT NULL,
+        token_hash VARCHAR(128) NOT NULL UNIQUE,
+        expires_at TIMESTAMPTZ NOT NULL,
+        used_at TIMESTAMPTZ,
+        revoked_at TIMESTAMPTZ,
+        created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    );
+	CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens (family_id);
+	CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens (user_id);`
+	pgxRefreshTokenTableAlterTwoFactor = `ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS two_factor BOOLEAN NOT NULL DEFAULT false;`
+
+	pgxRefreshTokenCreate = `
+	INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at, created_at, two_factor)
+    VALUES ($1, $2, $3, $4, $5, $6)
+    RETURNING id;`
+	pgxRefreshTokenFindByHash = `SELECT id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at, two_factor
+    FROM refresh_tokens
+    WHERE token_hash = $1
+    FOR UPDATE;`
+	pgxRefreshTokenMarkUsed = `UPDATE refresh_tokens
+		SET used_at = $1
+		WHERE id = $2;`
+	pgxRefreshTokenRevokeFamily = `UPDATE refresh_tokens
+		SET revoked_at = $1
+		WHERE family_id = $2 AND revoked_at IS NULL;`
+	pgxRefreshTokenRevokeByUser = `UPDATE refresh_tokens
+		SET revoked_at = $1
+		WHERE user_id = $2 AND revoked_at IS NULL;`
+)
+
+type pgxRefreshTokenRepository struct {
+	db pgx.Tx
+}
+
+func NewPgxRefreshToken(db pgx.Tx) ui.RefreshTokenRepository {
+	return &pgxRefreshTokenRepository{db}
+}
+
+func (r *pgxRefreshTokenRepository) Migrate(ctx context.Context) error {
+	if _, err := r.db.Exec(ctx, pgxRefreshTokenTableCreate); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec(ctx, pgxRefreshTokenTableAlterTwoFactor)
+	return err
+}
+
+func (r *pgxRefreshTokenRepository) Create(ctx context.Context, t *domain.RefreshToken) error {
+	return r.db.QueryRow(ctx, pgxRefreshTokenCreate,
+		t.UserID,
+		t.FamilyID,
+		t.TokenHash,
+		t.ExpiresAt,
+		t.CreatedAt,
+		t.TwoFactor,
+	).Scan(&t.ID)
+}
+
+func (r *pgxRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	row := r.db.QueryRow(ctx, pgxRefreshTokenFindByHash, tokenHash)
+	return scanRefreshToken(row)
+}
+
+func (r *pgxRefreshTokenRepository) MarkUsed(ctx context.Context, id string) error {
+	_, err := r.db.Exec(ctx, pgxRefreshTokenMarkUsed, time.Now(), id)
+	return err
+}
+
+func (r *pgxRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := r.db.Exec(ctx, pgxRefreshTokenRevokeFamily, time.Now(), familyID)
+	return err
+}
+
+func (r *pgxRefreshTokenRepository) RevokeByUser(ctx context.Context, userID string) error {
+	_, err := r.db.Exec(ctx, pgxRefreshTokenRevokeByUser, time.Now(), userID)
+	return err
+}
+
+func scanRefreshToken(s interfaces.Scanner) (*domain.RefreshToken, error) {
+	t := &domain.RefreshToken{}
+
+	err := s.Scan(
+		&t.ID,
+		&t.UserID,
+		&t.FamilyID,
+		&t.TokenHash,
+		&t.ExpiresAt,
+		&t.UsedAt,
+		&t.RevokedAt,
+		&t.CreatedAt,
+		&t.TwoFactor,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	pgxRevokedTokenTableCreate = `
+	CREATE TABLE IF NOT EXISTS revoked_tokens (
+        token_id VARCHAR(64) PRIMARY KEY,
+        user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+        expires_at TIMESTAMPTZ NOT NULL,
+        revoked_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    );
+	CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires ON revoked_tokens (expires_at);`
+	pgxRevokedTokenCreate = `
+	INSERT INTO revoked_tokens (token_id, user_id, expires_at, revoked_at)
+    VALUES ($1, $2, $3, $4)
+    ON CONFLICT (token_id) DO NOTHING;`
+	pgxRevokedTokenListActive = `SELECT token_id, user_id, expires_at, revoked_at
+    FROM revoked_tokens
+    WHERE expires_at > $1;`
+	pgxRevokedTokenDeleteExpired = `DELETE FROM revoked_tokens WHERE expires_at <= $1;`
+)
+
+type pgxRevokedTokenRepository struct {
+	db pgx.Tx
+}
+
+func NewPgxRevokedToken(db pgx.Tx) ui.RevokedTokenRepository {
+	return &pgxRevokedTokenRepository{db}
+}
+
+func (r *pgxRevokedTokenRepository) Migrate(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, pgxRevokedTokenTableCreate)
+	return err
+}
+
+func (r *pgxRevokedTokenRepository) Create(ctx context.Context, t *domain.RevokedToken) error {
+	_, err := r.db.Exec(ctx, pgxRevokedTokenCreate,
+		t.TokenID,
+		t.UserID,
+		t.ExpiresAt,
+		t.RevokedAt,
+	)
+	return err
+}
+
+func (r *pgxRevokedTokenRepository) ListActive(ctx context.Context, now time.Time) ([]*domain.RevokedToken, error) {
+	rows, err := r.db.Query(ctx, pgxRevokedTokenListActive, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*domain.RevokedToken
+	for rows.Next() {
+		t, err := scanRevokedToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (r *pgxRevokedTokenRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	_, err := r.db.Exec(ctx, pgxRevokedTokenDeleteExpired, now)
+	return err
+}
+
+func scanRevokedToken(s interfaces.Scanner) (*domain.RevokedToken, error) {
+	t := &domain.RevokedToken{}
+
+	err := s.Scan(
+		&t.TokenID,
+		&t.UserID,
+		&t.ExpiresAt,
+		&t.RevokedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
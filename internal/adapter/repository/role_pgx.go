@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	pgxRoleTableCreate = `
+	CREATE TABLE IF NOT EXISTS roles (
+        name VARCHAR(50) PRIMARY KEY,
+        description VARCHAR(255) NOT NULL DEFAULT '',
+        is_system BOOLEAN NOT NULL DEFAULT false,
+        created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMPTZ
+    );
+	CREATE TABLE IF NOT EXISTS role_permissions (
+        role_name VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+        permission VARCHAR(100) NOT NULL,
+        PRIMARY KEY (role_name, permission)
+    );`
+	pgxRoleSeed = `
+	INSERT INTO roles (name, description, is_system, created_at)
+    VALUES ($1, $2, true, $3)
+    ON CONFLICT (name) DO NOTHING;`
+	pgxRolePermissionAdd = `
+	INSERT INTO role_permissions (role_name, permission)
+    VALUES ($1, $2)
+    ON CONFLICT DO NOTHING;`
+	pgxRoleCreate = `
+	INSERT INTO roles (name, description, is_system, created_at)
+    VALUES ($1, $2, false, $3);`
+	pgxRoleUpdate = `UPDATE roles
+		SET description = $1, updated_at = $2
+		WHERE name = $3;`
+	pgxRoleDelete               = `DELETE FROM roles WHERE name = $1 AND is_system = false;`
+	pgxRolePermissionsDeleteAll = `DELETE FROM role_permissions WHERE role_name = $1;`
+	pgxRoleGetPermissions       = `SELECT permission FROM role_permissions WHERE role_name = $1 ORDER BY permission;`
+	pgxRoleCountUsers           = `SELECT COUNT(*) FROM users WHERE role = $1;`
+	pgxRoleGetByName            = `SELECT name, description, is_system, created_at, updated_at FROM roles WHERE name = $1;`
+	pgxRoleGetAll               = `SELECT name, description, is_system, created_at, updated_at FROM roles ORDER BY is_system DESC, name;`
+	pgxRolePermissionsGetAll    = `SELECT role_name, permission FROM role_permissions ORDER BY permission;`
+)
+
+type pgxRoleRepository struct {
+	db pgx.Tx
+}
+
+func NewPgxRole(db pgx.Tx) ui.RoleRepository {
+	return &pgxRoleRepository{db}
+}
+
+func (r *pgxRoleRepository) Migrate(ctx context.Context) error {
+	if _, err := r.db.Exec(ctx, pgxRoleTableCreate); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, role := range domain.SystemRoles {
+		if _, err := r.db.Exec(ctx, pgxRoleSeed, role.Name, role.Description, now); err != nil {
+			return err
+		}
+	}
+
+	// Los permisos nuevos se agregan a ADMIN_ROLE en cada arranque para que nunca pierda acceso
+	for _, permission := range domain.Permissions {
+		if _, err := r.db.Exec(ctx, pgxRolePermissionAdd, domain.AdminRole, permission); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *pgxRoleRepository) GetAll(ctx context.Context) ([]*domain.Role, error) {
+	rows, err := r.db.Query(ctx, pgxRoleGetAll)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := make([]*domain.Role, 0)
+	byName := make(map[string]*domain.Role)
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+		byName[role.Name] = role
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	permRows, err := r.db.Query(ctx, pgxRolePermissionsGetAll)
+	if err != nil {
+		return nil, err
+	}
+	defer permRows.Close()
+
+	for permRows.Next() {
+		var roleName, permission string
+		if err := permRows.Scan(&roleName, &permission); err != nil {
+			return nil, err
+		}
+		if role, ok := byName[roleName]; ok {
+			role.Permissions = append(role.Permissions, permission)
+		}
+	}
+
+	return roles, permRows.Err()
+}
+
+func (r *pgxRoleRepository) GetByName(ctx context.Context, name string) (*domain.Role, error) {
+	role, err := scanRole(r.db.QueryRow(ctx, pgxRoleGetByName, name))
+	if err != nil {
+		return nil, err
+	}
+
+	role.Permissions, err = r.GetPermissions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+func (r *pgxRoleRepository) Create(ctx context.Context, role *domain.Role) error {
+	role.CreatedAt = time.Now()
+	if _, err := r.db.Exec(ctx, pgxRoleCreate, role.Name, role.Description, role.CreatedAt); err != nil {
+		return err
+	}
+
+	return r.addPermissions(ctx, role.Name, role.Permissions)
+}
+
+func (r *pgxRoleRepository) Update(ctx context.Context, role *domain.Role) error {
+	now := time.Now()
+	role.UpdatedAt = &now
+
+	if _, err := r.db.Exec(ctx, pgxRoleUpdate, role.Description, now, role.Name); err != nil {
+		return err
+	}
+
+	if _, err := r.db.Exec(ctx, pgxRolePermissionsDeleteAll, role.Name); err != nil {
+		return err
+	}
+
+	return r.addPermissions(ctx, role.Name, role.Permissions)
+}
+
+func (r *pgxRoleRepository) Delete(ctx context.Context, name string) error {
+	_, err := r.db.Exec(ctx, pgxRoleDelete, name)
+	return err
+}
+
+func (r *pgxRoleRepository) GetPermissions(ctx context.Context, name string) ([]string, error) {
+	rows, err := r.db.Query(ctx, pgxRoleGetPermissions, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := make([]string, 0)
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, rows.Err()
+}
+
+func (r *pgxRoleRepository) CountUsers(ctx context.Context, name string) (int64, error) {
+	var count int64
+	err := r.db.QueryRow(ctx, pgxRoleCountUsers, name).Scan(&count)
+	return count, err
+}
+
+func (r *pgxRoleRepository) addPermissions(ctx context.Context, name string, permissions []string) error {
+	for _, permission := range permissions {
+		if _, err := r.db.Exec(ctx, pgxRolePermissionAdd, name, permission); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanRole(s interfaces.Scanner) (*domain.Role, error) {
+	role := &domain.Role{Permissions: []string{}}
+
+	err := s.Scan(
+		&role.Name,
+		&role.Description,
+		&role.IsSystem,
+		&role.CreatedAt,
+		&role.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	pgxSessionTableCreate = `
+	CREATE TABLE IF NOT EXISTS user_sessions (
+        id UUID PRIMARY KEY,
+        user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+        ip VARCHAR(64) NOT NULL DEFAULT '',
+        user_agent TEXT NOT NULL DEFAULT '',
+        two_factor BOOLEAN NOT NULL DEFAULT false,
+        created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+        last_seen_at TIMESTAMPTZ NOT NULL,
+        expires_at TIMESTAMPTZ NOT NULL,
+        revoked_at TIMESTAMPTZ
+    );
+	CREATE INDEX IF NOT EXISTS idx_user_sessions_user ON user_sessions (user_id, last_seen_at DESC);`
+	pgxSessionCreate = `
+	INSERT INTO user_sessions (id, user_id, ip, user_agent, two_factor, created_at, last_seen_at, expires_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`
+	pgxSessionColumns          = `id, user_id, ip, user_agent, two_factor, created_at, last_seen_at, expires_at, revoked_at`
+	pgxSessionFindByID         = `SELECT ` + pgxSessionColumns + ` FROM user_sessions WHERE id = $1;`
+	pgxSessionListActiveByUser = `SELECT ` + pgxSessionColumns + `
+		FROM user_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_seen_at DESC;`
+	pgxSessionTouch = `UPDATE user_sessions
+		SET last_seen_at = $1
+		WHERE id = $2 AND revoked_at IS NULL;`
+	pgxSessionExtend = `UPDATE user_sessions
+		SET expires_at = $1, last_seen_at = $2
+		WHERE id = $3 AND revoked_at IS NULL;`
+	pgxSessionRevoke = `UPDATE user_sessions
+		SET revoked_at = $1
+		WHERE id = $2 AND revoked_at IS NULL;`
+	pgxSessionRevokeByUser = `UPDATE user_sessions
+		SET revoked_at = $1
+		WHERE user_id = $2 AND revoked_at IS NULL;`
+)
+
+type pgxSessionRepository struct {
+	db pgx.Tx
+}
+
+func NewPgxSession(db pgx.Tx) ui.SessionRepository {
+	return &pgxSessionRepository{db}
+}
+
+func (r *pgxSessionRepository) Migrate(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, pgxSessionTableCreate)
+	return err
+}
+
+func (r *pgxSessionRepository) Create(ctx context.Context, s *domain.Session) error {
+	_, err := r.db.Exec(ctx, pgxSessionCreate,
+		s.ID,
+		s.UserID,
+		s.IP,
+		s.UserAgent,
+		s.TwoFactor,
+		s.CreatedAt,
+		s.LastSeenAt,
+		s.ExpiresAt,
+	)
+	return err
+}
+
+func (r *pgxSessionRepository) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	return scanSession(r.db.QueryRow(ctx, pgxSessionFindByID, id))
+}
+
+func (r *pgxSessionRepository) ListActiveByUser(ctx context.Context, userID string, now time.Time) ([]*domain.Session, error) {
+	rows, err := r.db.Query(ctx, pgxSessionListActiveByUser, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]*domain.Session, 0)
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (r *pgxSessionRepository) Touch(ctx context.Context, id string, now time.Time) (bool, error) {
+	tag, err := r.db.Exec(ctx, pgxSessionTouch, now, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *pgxSessionRepository) Extend(ctx context.Context, id string, expiresAt, now time.Time) (bool, error) {
+	tag, err := r.db.Exec(ctx, pgxSessionExtend, expiresAt, now, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *pgxSessionRepository) Revoke(ctx context.Context, id string, now time.Time) error {
+	_, err := r.db.Exec(ctx, pgxSessionRevoke, now, id)
+	return err
+}
+
+func (r *pgxSessionRepository) RevokeByUser(ctx context.Context, userID string, now time.Time) error {
+	_, err := r.db.Exec(ctx, pgxSessionRevokeByUser, now, userID)
+	return err
+}
+
+func scanSession(s interfaces.Scanner) (*domain.Session, error) {
+	session := &domain.Session{}
+
+	err := s.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.IP,
+		&session.UserAgent,
+		&session.TwoFactor,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
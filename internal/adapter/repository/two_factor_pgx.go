@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	pgxTwoFactorTableCreate = `
+	CREATE TABLE IF NOT EXISTS user_two_factor (
+        user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+        secret VARCHAR(64) NOT NULL,
+        enabled BOOLEAN NOT NULL DEFAULT false,
+        last_used_step BIGINT NOT NULL DEFAULT 0,
+        confirmed_at TIMESTAMPTZ,
+        created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+        updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    );
+	CREATE TABLE IF NOT EXISTS user_recovery_codes (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+        user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+        code_hash VARCHAR(128) NOT NULL,
+        used_at TIMESTAMPTZ,
+        created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    );
+	CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user ON user_recovery_codes (user_id);`
+	pgxTwoFactorGet = `SELECT user_id, secret, enabled, last_used_step, confirmed_at, created_at, updated_at
+    FROM user_two_factor
+    WHERE user_id = $1
+    FOR UPDATE;`
+	pgxTwoFactorSave = `
+	INSERT INTO user_two_factor (user_id, secret, enabled, last_used_step, confirmed_at, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7)
+    ON CONFLICT (user_id) DO UPDATE
+    SET secret = EXCLUDED.secret,
+        enabled = EXCLUDED.enabled,
+        last_used_step = EXCLUDED.last_used_step,
+        confirmed_at = EXCLUDED.confirmed_at,
+        updated_at = EXCLUDED.updated_at;`
+	pgxTwoFactorDelete           = `DELETE FROM user_two_factor WHERE user_id = $1;`
+	pgxRecoveryCodesDeleteByUser = `DELETE FROM user_recovery_codes WHERE user_id = $1;`
+	pgxRecoveryCodeCreate        = `INSERT INTO user_recovery_codes (user_id, code_hash, created_at) VALUES ($1, $2, $3);`
+	pgxRecoveryCodeUse           = `UPDATE user_recovery_codes
+		SET used_at = $1
+		WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL;`
+)
+
+type pgxTwoFactorRepository struct {
+	db pgx.Tx
+}
+
+func NewPgxTwoFactor(db pgx.Tx) ui.TwoFactorRepository {
+	return &pgxTwoFactorRepository{db}
+}
+
+func (r *pgxTwoFactorRepository) Migrate(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, pgxTwoFactorTableCreate)
+	return err
+}
+
+func (r *pgxTwoFactorRepository) Get(ctx context.Context, userID string) (*domain.TwoFactor, error) {
+	t := &domain.TwoFactor{}
+
+	err := r.db.QueryRow(ctx, pgxTwoFactorGet, userID).Scan(
+		&t.UserID,
+		&t.Secret,
+		&t.Enabled,
+		&t.LastUsedStep,
+		&t.ConfirmedAt,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r *pgxTwoFactorRepository) Save(ctx context.Context, t *domain.TwoFactor) error {
+	now := time.Now()
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = now
+	}
+	t.UpdatedAt = now
+
+	_, err := r.db.Exec(ctx, pgxTwoFactorSave,
+		t.UserID,
+		t.Secret,
+		t.Enabled,
+		t.LastUsedStep,
+		t.ConfirmedAt,
+		t.CreatedAt,
+		t.UpdatedAt,
+	)
+	return err
+}
+
+func (r *pgxTwoFactorRepository) Delete(ctx context.Context, userID string) error {
+	if _, err := r.db.Exec(ctx, pgxRecoveryCodesDeleteByUser, userID); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec(ctx, pgxTwoFactorDelete, userID)
+	return err
+}
+
+func (r *pgxTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	if _, err := r.db.Exec(ctx, pgxRecoveryCodesDeleteByUser, userID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, hash := range codeHashes {
+		if _, err := r.db.Exec(ctx, pgxRecoveryCodeCreate, userID, hash, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *pgxTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	tag, err := r.db.Exec(ctx, pgxRecoveryCodeUse, time.Now(), userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
@@ -10,18 +10,44 @@ import (
 )
 
 type PgUnitOfWork struct {
-	tx         pgx.Tx
-	userRepo   interfaces.UserRepository
-	committed  bool
-	rolledBack bool
-	ctx        context.Context
+	tx          pgx.Tx
+	userRepo    interfaces.UserRepository
+	tokenRepo   interfaces.UserTokenRepository
+	refreshRepo interfaces.RefreshTokenRepository
+	revokedRepo interfaces.RevokedTokenRepository
+	attemptRepo interfaces.LoginAttemptRepository
+	lockoutRepo interfaces.AccountLockoutRepository
+	twoFARepo   interfaces.TwoFactorRepository
+	roleRepo    interfaces.RoleRepository
+	historyRepo interfaces.PasswordHistoryRepository
+	auditRepo   interfaces.AuditEventRepository
+	oidcRepo    interfaces.OIDCLoginStateRepository
+	linkRepo    interfaces.OIDCLinkRepository
+	apiKeyRepo  interfaces.APIKeyRepository
+	sessionRepo interfaces.SessionRepository
+	committed   bool
+	rolledBack  bool
+	ctx         context.Context
 }
 
 func NewPgUnitOfWork(tx pgx.Tx, ctx context.Context) *PgUnitOfWork {
 	return &PgUnitOfWork{
-		tx:       tx,
-		userRepo: NewPgxUser(tx),
-		ctx:      ctx,
+		tx:          tx,
+		userRepo:    NewPgxUser(tx),
+		tokenRepo:   NewPgxUserToken(tx),
+		refreshRepo: NewPgxRefreshToken(tx),
+		revokedRepo: NewPgxRevokedToken(tx),
+		attemptRepo: NewPgxLoginAttempt(tx),
+		lockoutRepo: NewPgxAccountLockout(tx),
+		twoFARepo:   NewPgxTwoFactor(tx),
+		roleRepo:    NewPgxRole(tx),
+		historyRepo: NewPgxPasswordHistory(tx),
+		auditRepo:   NewPgxAuditEvent(tx),
+		oidcRepo:    NewPgxOIDCLoginState(tx),
+		linkRepo:    NewPgxOIDCLink(tx),
+		apiKeyRepo:  NewPgxAPIKey(tx),
+		sessionRepo: NewPgxSession(tx),
+		ctx:         ctx,
 	}
 }
 
@@ -60,3 +86,55 @@ func (uow *PgUnitOfWork) Rollback() error {
 func (uow *PgUnitOfWork) UserRepository() interfaces.UserRepository {
 	return uow.userRepo
 }
+
+func (uow *PgUnitOfWork) UserTokenRepository() interfaces.UserTokenRepository {
+	return uow.tokenRepo
+}
+
+func (uow *PgUnitOfWork) RefreshTokenRepository() interfaces.RefreshTokenRepository {
+	return uow.refreshRepo
+}
+
+func (uow *PgUnitOfWork) RevokedTokenRepository() interfaces.RevokedTokenRepository {
+	return uow.revokedRepo
+}
+
+func (uow *PgUnitOfWork) LoginAttemptRepository() interfaces.LoginAttemptRepository {
+	return uow.attemptRepo
+}
+
+func (uow *PgUnitOfWork) AccountLockoutRepository() interfaces.AccountLockoutRepository {
+	return uow.lockoutRepo
+}
+
+func (uow *PgUnitOfWork) TwoFactorRepository() interfaces.TwoFactorRepository {
+	return uow.twoFARepo
+}
+
+func (uow *PgUnitOfWork) RoleRepository() interfaces.RoleRepository {
+	return uow.roleRepo
+}
+
+func (uow *PgUnitOfWork) PasswordHistoryRepository() interfaces.PasswordHistoryRepository {
+	return uow.historyRepo
+}
+
+func (uow *PgUnitOfWork) AuditEventRepository() interfaces.AuditEventRepository {
+	return uow.auditRepo
+}
+
+func (uow *PgUnitOfWork) OIDCLoginStateRepository() interfaces.OIDCLoginStateRepository {
+	return uow.oidcRepo
+}
+
+func (uow *PgUnitOfWork) OIDCLinkRepository() interfaces.OIDCLinkRepository {
+	return uow.linkRepo
+}
+
+func (uow *PgUnitOfWork) APIKeyRepository() interfaces.APIKeyRepository {
+	return uow.apiKeyRepo
+}
+
+func (uow *PgUnitOfWork) SessionRepository() interfaces.SessionRepository {
+	return uow.sessionRepo
+}
@@ -26,37 +26,44 @@ const (
         created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
         updated_at TIMESTAMPTZ
     );`
+	pgxUserTableAlterTokenVersion       = `ALTER TABLE users ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;`
+	pgxUserTableAlterMustChangePassword = `ALTER TABLE users ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT false;`
+	pgxUserTableAlterPasswordChangedAt  = `ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMPTZ;`
+	pgxUserTableIndexCreatedAt          = `CREATE INDEX IF NOT EXISTS idx_users_created_id ON users (created_at, id);`
+	pgxUserBackfillPasswordChangedAt    = `UPDATE users SET password_changed_at = CURRENT_TIMESTAMP
+		WHERE password IS NOT NULL AND password_changed_at IS NULL;`
+
 	pgxUserCreate = `
-	INSERT INTO users (name, email, password, img, role, status, email_validated, created_at)
-    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	INSERT INTO users (name, email, password, img, role, status, email_validated, created_at, password_changed_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
     RETURNING id;
 	`
-	pgxUserGetAll          = `SELECT id, name, email, password, img, role, status, email_validated, created_at, updated_at FROM users ORDER BY created_at DESC;`
-	pgxUserGetAllPaginated = `SELECT id, name, email, password, img, role, status, email_validated, created_at, updated_at 
-		FROM users 
-		ORDER BY created_at DESC 
-		LIMIT $1 OFFSET $2;`
-	pgxUserGetAllWithSearch = `SELECT id, name, email, password, img, role, status, email_validated, created_at, updated_at 
-		FROM users 
-		WHERE (LOWER(name) LIKE LOWER($1) OR LOWER(email) LIKE LOWER($1) OR LOWER(CONCAT(name, ' ', email)) LIKE LOWER($1))
-		ORDER BY created_at DESC 
-		LIMIT $2 OFFSET $3;`
-	pgxUserCount           = `SELECT COUNT(*) FROM users;`
-	pgxUserCountWithSearch = `SELECT COUNT(*) FROM users 
-		WHERE (LOWER(name) LIKE LOWER($1) OR LOWER(email) LIKE LOWER($1) OR LOWER(CONCAT(name, ' ', email)) LIKE LOWER($1));`
-	pgxUserGetByID = `SELECT id, name, email, password, img, role, status, email_validated, created_at, updated_at
+	pgxUserList = `SELECT id, name, email, password, img, role, status, email_validated, created_at, updated_at, token_version, must_change_password, password_changed_at
+		FROM users
+		%s
+		ORDER BY %s`
+	pgxUserListPage   = ` LIMIT $%d OFFSET $%d`
+	pgxUserListCursor = ` LIMIT $%d`
+	pgxUserCount      = `SELECT COUNT(*) FROM users %s;`
+	pgxUserGetByID    = `SELECT id, name, email, password, img, role, status, email_validated, created_at, updated_at, token_version, must_change_password, password_changed_at
     FROM users
     WHERE id = $1;`
-	pgxUserFindByEmail = `SELECT id, name, email, password, img, role, status, email_validated, created_at, updated_at
+	pgxUserFindByEmail = `SELECT id, name, email, password, img, role, status, email_validated, created_at, updated_at, token_version, must_change_password, password_changed_at
     FROM users
     WHERE email = $1;`
 	pgxUserUpdate = `UPDATE users SET %s WHERE id = $%d;`
 	pgxUserDetele = `UPDATE users
 		SET status = false,
+		    token_version = token_version + 1,
 		    updated_at = $1
 		WHERE id = $2;`
+	pgxUserGetTokenVersion = `SELECT token_version FROM users WHERE id = $1;`
+	pgxUserUpdatePassword  = `UPDATE users SET password = $1 WHERE id = $2;`
 )
 
+// tokenVersionFields son las columnas cuyo cambio invalida los JWT ya emitidos para el usuario
+var tokenVersionFields = []string{"password", "role", "status", "must_change_password"}
+
 type pgxUserRepository struct {
 	db pgx.Tx
 }
@@ -66,7 +73,30 @@ func NewPgxUser(db pgx.Tx) ui.UserRepository {
 }
 
 func (r *pgxUserRepository) Migrate(ctx context.Context) error {
-	_, err := r.db.Exec(context.Background(), pgxUserTableCreate)
+	if _, err := r.db.Exec(context.Background(), pgxUserTableCreate); err != nil {
+		return err
+	}
+
+	if _, err := r.db.Exec(ctx, pgxUserTableAlterTokenVersion); err != nil {
+		return err
+	}
+
+	if _, err := r.db.Exec(ctx, pgxUserTableAlterMustChangePassword); err != nil {
+		return err
+	}
+
+	if _, err := r.db.Exec(ctx, pgxUserTableAlterPasswordChangedAt); err != nil {
+		return err
+	}
+
+	// Las contraseñas anteriores a la columna empiezan a vencer desde la migración y no desde la creación
+	// de la cuenta, que obligaría a todos los usuarios existentes a cambiarla a la vez
+	if _, err := r.db.Exec(ctx, pgxUserBackfillPasswordChangedAt); err != nil {
+		return err
+	}
+
+	// Soporta la paginación por cursor sobre (created_at, id) en ambas direcciones
+	_, err := r.db.Exec(ctx, pgxUserTableIndexCreatedAt)
 	return err
 }
 
@@ -80,38 +110,43 @@ func (r *pgxUserRepository) Create(ctx context.Context, u *domain.User) error {
 		u.Status,
 		u.EmailValidated,
 		u.CreatedAt,
+		u.PasswordChangedAt,
 	).Scan(&u.ID)
 
 	return err
 }
 
-func (r *pgxUserRepository) GetAll(ctx context.Context, pagination *domain.Pagination) ([]*domain.User, int64, error) {
-	var total int64
-	var rows pgx.Rows
-	var err error
-
-	hasSearch := pagination != nil && pagination.Search != ""
-
-	if hasSearch {
-		searchTerm := "%" + pagination.Search + "%"
-		err = r.db.QueryRow(ctx, pgxUserCountWithSearch, searchTerm).Scan(&total)
-	} else {
-		err = r.db.QueryRow(ctx, pgxUserCount).Scan(&total)
+func (r *pgxUserRepository) GetAll(ctx context.Context, filter domain.UserFilter, pagination *domain.Pagination) ([]*domain.User, int64, error) {
+	search := ""
+	if pagination != nil {
+		search = pagination.Search
 	}
+	where, args := userWhere(filter, search)
 
-	if err != nil {
-		return nil, 0, err
+	// Las páginas de modo cursor posteriores a la primera no informan el total, así que no se cuenta
+	var total int64
+	if pagination == nil || pagination.Cursor == nil {
+		if err := r.db.QueryRow(ctx, fmt.Sprintf(pgxUserCount, where), args...).Scan(&total); err != nil {
+			return nil, 0, err
+		}
 	}
 
-	if pagination == nil {
-		rows, err = r.db.Query(ctx, pgxUserGetAll)
-	} else if hasSearch {
-		searchTerm := "%" + pagination.Search + "%"
-		rows, err = r.db.Query(ctx, pgxUserGetAllWithSearch, searchTerm, pagination.Limit, pagination.Offset)
-	} else {
-		rows, err = r.db.Query(ctx, pgxUserGetAllPaginated, pagination.Limit, pagination.Offset)
+	var query string
+	switch {
+	case pagination == nil:
+		query = fmt.Sprintf(pgxUserList, where, userOrderBy(filter.Sort))
+	case pagination.CursorMode:
+		// El cursor es la posición (created_at, id), por lo que este modo solo ordena por created_at
+		var orderBy string
+		where, args, orderBy = keysetPage(where, args, "created_at", filter.Sort.Desc, pagination.Cursor)
+		query = fmt.Sprintf(pgxUserList, where, orderBy) + fmt.Sprintf(pgxUserListCursor, len(args)+1)
+		args = append(args, pagination.FetchLimit())
+	default:
+		query = fmt.Sprintf(pgxUserList, where, userOrderBy(filter.Sort)) + fmt.Sprintf(pgxUserListPage, len(args)+1, len(args)+2)
+		args = append(args, pagination.Limit, pagination.Offset)
 	}
 
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -133,6 +168,73 @@ func (r *pgxUserRepository) GetAll(ctx context.Context, pagination *domain.Pagin
 	return users, total, nil
 }
 
+// userSortColumns es la lista blanca de columnas del ORDER BY; nunca se interpola texto del cliente
+var userSortColumns = map[string]string{
+	domain.UserSortName:      "name",
+	domain.UserSortEmail:     "email",
+	domain.UserSortRole:      "role",
+	domain.UserSortStatus:    "status",
+	domain.UserSortCreatedAt: "created_at",
+	domain.UserSortUpdatedAt: "updated_at",
+}
+
+// userWhere arma la cláusula WHERE con un parámetro por cada filtro presente
+func userWhere(filter domain.UserFilter, search string) (string, []any) {
+	conditions := []string{}
+	args := []any{}
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if search != "" {
+		add("(LOWER(name) LIKE LOWER($%[1]d) OR LOWER(email) LIKE LOWER($%[1]d) OR LOWER(CONCAT(name, ' ', email)) LIKE LOWER($%[1]d))", "%"+search+"%")
+	}
+	if filter.Role != "" {
+		add("role = $%d", filter.Role)
+	}
+	if filter.Status != nil {
+		add("status = $%d", *filter.Status)
+	}
+	if filter.EmailValidated != nil {
+		add("email_validated = $%d", *filter.EmailValidated)
+	}
+	if filter.CreatedFrom != nil {
+		add("created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		add("created_at <= $%d", *filter.CreatedTo)
+	}
+	if filter.UpdatedFrom != nil {
+		add("updated_at >= $%d", *filter.UpdatedFrom)
+	}
+	if filter.UpdatedTo != nil {
+		add("updated_at <= $%d", *filter.UpdatedTo)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// userOrderBy traduce el orden a SQL; el id desempata para que la paginación sea estable
+func userOrderBy(sort domain.UserSort) string {
+	column, ok := userSortColumns[sort.Field]
+	if !ok {
+		sort = domain.DefaultUserSort
+		column = userSortColumns[sort.Field]
+	}
+
+	// Los usuarios nunca actualizados (updated_at NULL) quedan al final en ambas direcciones
+	if sort.Desc {
+		return column + " DESC NULLS LAST, id DESC"
+	}
+	return column + " ASC NULLS LAST, id ASC"
+}
+
 func (r *pgxUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
 	row := r.db.QueryRow(ctx, pgxUserGetByID, id)
 	return scanUser(row)
@@ -160,8 +262,24 @@ func (r *pgxUserRepository) UpdateByID(ctx context.Context, input ui.UpdateUserI
 		i++
 	}
 
+	for _, col := range tokenVersionFields {
+		if _, ok := fields[col]; ok {
+			set = append(set, "token_version = token_version + 1")
+			break
+		}
+	}
+
+	now := time.Now()
+
+	// El vencimiento de la contraseña se cuenta desde su último cambio
+	if _, ok := fields["password"]; ok {
+		set = append(set, fmt.Sprintf("password_changed_at = $%d", i))
+		args = append(args, now)
+		i++
+	}
+
 	set = append(set, fmt.Sprintf("updated_at = $%d", i))
-	args = append(args, time.Now())
+	args = append(args, now)
 	i++
 
 	args = append(args, input.GetID())
@@ -176,6 +294,12 @@ func (r *pgxUserRepository) Delete(ctx context.Context, id string) error {
 	return err
 }
 
+func (r *pgxUserRepository) GetTokenVersion(ctx context.Context, id string) (int, error) {
+	var version int
+	err := r.db.QueryRow(ctx, pgxUserGetTokenVersion, id).Scan(&version)
+	return version, err
+}
+
 func scanUser(s interfaces.Scanner) (*domain.User, error) {
 	var (
 		password  *string
@@ -196,6 +320,9 @@ func scanUser(s interfaces.Scanner) (*domain.User, error) {
 		&u.EmailValidated,
 		&u.CreatedAt,
 		&updatedAt,
+		&u.TokenVersion,
+		&u.MustChangePassword,
+		&u.PasswordChangedAt,
 	)
 
 	if err != nil {
@@ -208,3 +335,10 @@ func scanUser(s interfaces.Scanner) (*domain.User, error) {
 
 	return u, nil
 }
+
+// UpdatePasswordHash reemplaza el hash sin incrementar token_version ni updated_at; se usa al migrar
+// el hash a un algoritmo nuevo, donde la contraseña en sí no cambia y las sesiones siguen siendo válidas.
+func (r *pgxUserRepository) UpdatePasswordHash(ctx context.Context, id, hash string) error {
+	_, err := r.db.Exec(ctx, pgxUserUpdatePassword, hash, id)
+	return err
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+func TestUserWhere(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC)
+	active := true
+	unvalidated := false
+
+	searchCondition := "(LOWER(name) LIKE LOWER($1) OR LOWER(email) LIKE LOWER($1) OR LOWER(CONCAT(name, ' ', email)) LIKE LOWER($1))"
+
+	tests := []struct {
+		name      string
+		filter    domain.UserFilter
+		search    string
+		wantWhere string
+		wantArgs  []any
+	}{
+		{name: "empty filter", wantWhere: "", wantArgs: []any{}},
+		{name: "sort alone does not filter", filter: domain.UserFilter{Sort: domain.UserSort{Field: domain.UserSortEmail}}, wantWhere: "", wantArgs: []any{}},
+		{name: "search reuses one placeholder", search: "ana", wantWhere: "WHERE " + searchCondition, wantArgs: []any{"%ana%"}},
+		{name: "role only", filter: domain.UserFilter{Role: "ADMIN_ROLE"}, wantWhere: "WHERE role = $1", wantArgs: []any{"ADMIN_ROLE"}},
+		{name: "false booleans still filter", filter: domain.UserFilter{Status: &unvalidated, EmailValidated: &unvalidated}, wantWhere: "WHERE status = $1 AND email_validated = $2", wantArgs: []any{false, false}},
+		{name: "updated range", filter: domain.UserFilter{UpdatedFrom: &from, UpdatedTo: &to}, wantWhere: "WHERE updated_at >= $1 AND updated_at <= $2", wantArgs: []any{from, to}},
+		{
+			name:      "all filters keep placeholder order",
+			filter:    domain.UserFilter{Role: "USER_ROLE", Status: &active, EmailValidated: &unvalidated, CreatedFrom: &from, CreatedTo: &to, UpdatedFrom: &from, UpdatedTo: &to},
+			search:    "torres",
+			wantWhere: "WHERE " + searchCondition + " AND role = $2 AND status = $3 AND email_validated = $4 AND created_at >= $5 AND created_at <= $6 AND updated_at >= $7 AND updated_at <= $8",
+			wantArgs:  []any{"%torres%", "USER_ROLE", true, false, from, to, from, to},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := userWhere(tt.filter, tt.search)
+			if where != tt.wantWhere {
+				t.Errorf("userWhere() where = %q, want %q", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("userWhere() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestUserOrderBy(t *testing.T) {
+	tests := []struct {
+		name string
+		sort domain.UserSort
+		want string
+	}{
+		{name: "default sort", sort: domain.DefaultUserSort, want: "created_at DESC NULLS LAST, id DESC"},
+		{name: "ascending", sort: domain.UserSort{Field: domain.UserSortName}, want: "name ASC NULLS LAST, id ASC"},
+		{name: "descending", sort: domain.UserSort{Field: domain.UserSortUpdatedAt, Desc: true}, want: "updated_at DESC NULLS LAST, id DESC"},
+		{name: "zero value falls back to default", sort: domain.UserSort{}, want: "created_at DESC NULLS LAST, id DESC"},
+		{name: "column outside the whitelist falls back to default", sort: domain.UserSort{Field: "password"}, want: "created_at DESC NULLS LAST, id DESC"},
+		{name: "injected SQL falls back to default", sort: domain.UserSort{Field: "name; DROP TABLE users"}, want: "created_at DESC NULLS LAST, id DESC"},
+		{name: "fallback keeps the default direction", sort: domain.UserSort{Field: "id"}, want: "created_at DESC NULLS LAST, id DESC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := userOrderBy(tt.sort); got != tt.want {
+				t.Errorf("userOrderBy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
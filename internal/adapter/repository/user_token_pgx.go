@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	pgxUserTokenTableCreate = `
+	CREATE TABLE IF NOT EXISTS user_tokens (
+        id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+        user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+        purpose VARCHAR(50) NOT NULL,
+        token_hash VARCHAR(128) NOT NULL UNIQUE,
+        expires_at TIMESTAMPTZ NOT NULL,
+        used_at TIMESTAMPTZ,
+        created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+    );
+	CREATE INDEX IF NOT EXISTS idx_user_tokens_user_purpose ON user_tokens (user_id, purpose);`
+	pgxUserTokenCreate = `
+	INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at, created_at)
+    VALUES ($1, $2, $3, $4, $5)
+    RETURNING id;`
+	pgxUserTokenFindByHash = `SELECT id, user_id, purpose, token_hash, expires_at, used_at, created_at
+    FROM user_tokens
+    WHERE purpose = $1 AND token_hash = $2
+    FOR UPDATE;`
+	pgxUserTokenMarkUsed = `UPDATE user_tokens
+		SET used_at = $1
+		WHERE id = $2;`
+	pgxUserTokenInvalidateByUser = `UPDATE user_tokens
+		SET used_at = $1
+		WHERE user_id = $2 AND purpose = $3 AND used_at IS NULL;`
+	pgxUserTokenCountCreatedSince = `SELECT COUNT(*) FROM user_tokens
+		WHERE user_id = $1 AND purpose = $2 AND created_at >= $3;`
+)
+
+type pgxUserTokenRepository struct {
+	db pgx.Tx
+}
+
+func NewPgxUserToken(db pgx.Tx) ui.UserTokenRepository {
+	return &pgxUserTokenRepository{db}
+}
+
+func (r *pgxUserTokenRepository) Migrate(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, pgxUserTokenTableCreate)
+	return err
+}
+
+func (r *pgxUserTokenRepository) Create(ctx context.Context, t *domain.UserToken) error {
+	return r.db.QueryRow(ctx, pgxUserTokenCreate,
+		t.UserID,
+		t.Purpose,
+		t.TokenHash,
+		t.ExpiresAt,
+		t.CreatedAt,
+	).Scan(&t.ID)
+}
+
+func (r *pgxUserTokenRepository) FindByHash(ctx context.Context, purpose, tokenHash string) (*domain.UserToken, error) {
+	row := r.db.QueryRow(ctx, pgxUserTokenFindByHash, purpose, tokenHash)
+	return scanUserToken(row)
+}
+
+func (r *pgxUserTokenRepository) MarkUsed(ctx context.Context, id string) error {
+	_, err := r.db.Exec(ctx, pgxUserTokenMarkUsed, time.Now(), id)
+	return err
+}
+
+func (r *pgxUserTokenRepository) InvalidateByUser(ctx context.Context, userID, purpose string) error {
+	_, err := r.db.Exec(ctx, pgxUserTokenInvalidateByUser, time.Now(), userID, purpose)
+	return err
+}
+
+func (r *pgxUserTokenRepository) CountCreatedSince(ctx context.Context, userID, purpose string, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, pgxUserTokenCountCreatedSince, userID, purpose, since).Scan(&count)
+	return count, err
+}
+
+func scanUserToken(s interfaces.Scanner) (*domain.UserToken, error) {
+	t := &domain.UserToken{}
+
+	err := s.Scan(
+		&t.ID,
+		&t.UserID,
+		&t.Purpose,
+		&t.TokenHash,
+		&t.ExpiresAt,
+		&t.UsedAt,
+		&t.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
@@ -1,8 +1,10 @@
 package router
 
 import (
+	"github.com/JacobD36/appfe_frontpage_api/internal/adapter/cookie"
 	"github.com/JacobD36/appfe_frontpage_api/internal/adapter/handler"
 	"github.com/JacobD36/appfe_frontpage_api/internal/adapter/middleware"
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
 	domainInterfaces "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
 	usecaseInterfaces "github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
 	v "github.com/JacobD36/appfe_frontpage_api/pkg/validator"
@@ -15,11 +17,46 @@ type Router struct {
 	e        *echo.Echo
 	handlers *Handlers
 	jwtMw    *middleware.JWTMiddleware
+	limiter  *middleware.RateLimiter
+	sessions *cookie.SessionCookies
+	options  Options
 }
 
 type Handlers struct {
-	User usecaseInterfaces.UserService
-	Auth usecaseInterfaces.AuthService
+	User      usecaseInterfaces.UserService
+	Auth      usecaseInterfaces.AuthService
+	TwoFactor usecaseInterfaces.TwoFactorService
+	Profile   usecaseInterfaces.ProfileService
+	Role      usecaseInterfaces.RoleService
+	Audit     usecaseInterfaces.AuditService
+	APIKey    usecaseInterfaces.APIKeyService
+	Session   usecaseInterfaces.SessionService
+}
+
+// TokenService firma y valida los JWT y publica las claves con las que se verifican
+type TokenService interface {
+	domainInterfaces.JWTService
+	domainInterfaces.JWKSProvider
+}
+
+// Options agrupa los servicios de autenticación y la configuración de seguridad aplicada al registrar las rutas
+type Options struct {
+	Tokens          TokenService
+	TokenRevocation usecaseInterfaces.TokenRevocationService
+	TokenVersions   usecaseInterfaces.TokenVersionService
+	Permissions     usecaseInterfaces.PermissionService
+
+	RateLimitStore middleware.RateLimitStore
+	RateLimits     middleware.RateLimitConfig
+
+	// RequireAdminTwoFactor exige sesiones con verificación en dos pasos en las rutas de administración
+	RequireAdminTwoFactor bool
+
+	// IPExtractor obtiene la IP del cliente; nil usa la IP de la conexión sin confiar en headers de proxy
+	IPExtractor echo.IPExtractor
+
+	// SessionCookies habilita el login con cookies HttpOnly y protección CSRF para el portal web
+	SessionCookies cookie.SessionCookieConfig
 }
 
 type CustomValidator struct {
@@ -30,28 +67,42 @@ func (cv *CustomValidator) Validate(i any) error {
 	return cv.validator.Struct(i)
 }
 
-func New(userService usecaseInterfaces.UserService, authService usecaseInterfaces.AuthService, jwtService domainInterfaces.JWTService) *Router {
+func New(handlers Handlers, options Options) *Router {
 	e := echo.New()
+	e.IPExtractor = options.IPExtractor
+	if e.IPExtractor == nil {
+		e.IPExtractor = echo.ExtractIPDirect()
+	}
+
+	sessions := cookie.NewSessionCookies(options.SessionCookies)
 
 	e.Use(
 		echoMiddleware.Recover(),
 		middleware.Logger(),
 		echoMiddleware.Secure(),
 		echoMiddleware.Gzip(),
-		echoMiddleware.CORS(),
+		corsMiddleware(sessions),
 	)
 
 	e.Validator = &CustomValidator{validator: v.Validate}
 
-	jwtMw := middleware.NewJWTMiddleware(jwtService)
+	jwtMw := middleware.NewJWTMiddleware(
+		options.Tokens,
+		options.TokenRevocation,
+		options.TokenVersions,
+		handlers.Session,
+		options.Permissions,
+		sessions,
+		handlers.APIKey,
+	)
 
 	router := &Router{
-		e:     e,
-		jwtMw: jwtMw,
-		handlers: &Handlers{
-			User: userService,
-			Auth: authService,
-		},
+		e:        e,
+		jwtMw:    jwtMw,
+		limiter:  middleware.NewRateLimiter(options.RateLimitStore),
+		sessions: sessions,
+		options:  options,
+		handlers: &handlers,
 	}
 
 	router.registerRoutes()
@@ -60,22 +111,130 @@ func New(userService usecaseInterfaces.UserService, authService usecaseInterface
 }
 
 func (r *Router) registerRoutes() {
+	jwksHandler := handler.NewJWKSHandler(r.options.Tokens)
+	r.e.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
 	v1 := r.e.Group("/api/v1")
 
 	userHandler := handler.NewUserHandler(r.handlers.User)
 	userGroup := v1.Group("/users")
 
-	adminUserGroup := userGroup.Group("", r.jwtMw.Authenticate(), r.jwtMw.RequireAdminRole())
-	adminUserGroup.POST("", userHandler.Create)
-	adminUserGroup.GET("", userHandler.GetAll)
-	adminUserGroup.GET("/:id", userHandler.GetByID)
-	adminUserGroup.PUT("/:id", userHandler.UpdateByID)
-	adminUserGroup.DELETE("/:id", userHandler.Delete)
+	adminUserGroup := userGroup.Group("",
+		r.jwtMw.AuthenticateWithAPIKey(),
+		r.jwtMw.RequireAdminTwoFactor(r.options.RequireAdminTwoFactor),
+		r.limiter.Limit("admin", r.options.RateLimits.Admin, middleware.KeyByUserID),
+	)
+	canReadUsers := r.jwtMw.RequirePermission(domain.PermissionUsersRead)
+	canWriteUsers := r.jwtMw.RequirePermission(domain.PermissionUsersWrite)
+	adminUserGroup.POST("", userHandler.Create, canWriteUsers)
+	adminUserGroup.GET("", userHandler.GetAll, canReadUsers)
+	adminUserGroup.GET("/:id", userHandler.GetByID, canReadUsers)
+	adminUserGroup.PUT("/:id", userHandler.UpdateByID, canWriteUsers)
+	adminUserGroup.DELETE("/:id", userHandler.Delete, canWriteUsers)
+	adminUserGroup.POST("/:id/unlock", userHandler.Unlock, canWriteUsers)
+	adminUserGroup.POST("/:id/invitation", userHandler.ResendInvitation, canWriteUsers)
+	adminUserGroup.POST("/:id/reset-password", userHandler.ForcePasswordReset, canWriteUsers)
+	adminUserGroup.POST("/:id/impersonate", userHandler.Impersonate, r.jwtMw.RequirePermission(domain.PermissionUsersImpersonate))
+
+	sessionHandler := handler.NewSessionHandler(r.handlers.Session)
+	adminUserGroup.GET("/:id/sessions", sessionHandler.GetByUser, canReadUsers)
+	adminUserGroup.DELETE("/:id/sessions/:sid", sessionHandler.Terminate, canWriteUsers)
+
+	roleHandler := handler.NewRoleHandler(r.handlers.Role)
+	roleGroup := v1.Group("/roles",
+		r.jwtMw.AuthenticateWithAPIKey(),
+		r.jwtMw.RequirePermission(domain.PermissionRolesManage),
+		r.jwtMw.RequireAdminTwoFactor(r.options.RequireAdminTwoFactor),
+		r.limiter.Limit("admin", r.options.RateLimits.Admin, middleware.KeyByUserID),
+	)
+	roleGroup.GET("", roleHandler.GetAll)
+	roleGroup.GET("/permissions", roleHandler.GetPermissions)
+	roleGroup.POST("", roleHandler.Create)
+	roleGroup.GET("/:name", roleHandler.GetByName)
+	roleGroup.PUT("/:name", roleHandler.Update)
+	roleGroup.DELETE("/:name", roleHandler.Delete)
+
+	auditHandler := handler.NewAuditHandler(r.handlers.Audit)
+	auditGroup := v1.Group("/audit",
+		r.jwtMw.AuthenticateWithAPIKey(),
+		r.jwtMw.RequirePermission(domain.PermissionAuditRead),
+		r.jwtMw.RequireAdminTwoFactor(r.options.RequireAdminTwoFactor),
+		r.limiter.Limit("admin", r.options.RateLimits.Admin, middleware.KeyByUserID),
+	)
+	auditGroup.GET("", auditHandler.GetAll)
+
+	// Las API keys se gestionan solo desde sesiones de usuario: una clave no puede emitir ni revocar otras
+	apiKeyHandler := handler.NewAPIKeyHandler(r.handlers.APIKey)
+	apiKeyGroup := v1.Group("/api-keys",
+		r.jwtMw.Authenticate(),
+		r.jwtMw.RequirePermission(domain.PermissionAPIKeysManage),
+		r.jwtMw.RequireAdminTwoFactor(r.options.RequireAdminTwoFactor),
+		r.jwtMw.ForbidImpersonation(),
+		r.limiter.Limit("admin", r.options.RateLimits.Admin, middleware.KeyByUserID),
+	)
+	apiKeyGroup.GET("", apiKeyHandler.GetAll)
+	apiKeyGroup.POST("", apiKeyHandler.Create)
+	apiKeyGroup.DELETE("/:id", apiKeyHandler.Revoke)
+
+	meHandler := handler.NewMeHandler(r.handlers.Profile)
+	meGroup := v1.Group("/me",
+		r.jwtMw.Authenticate(),
+		r.limiter.Limit("me", r.options.RateLimits.Me, middleware.KeyByUserID),
+	)
+	meGroup.GET("", meHandler.Get)
+	meGroup.PATCH("", meHandler.Update)
+	meGroup.POST("/password", meHandler.ChangePassword, r.jwtMw.ForbidImpersonation())
+	meGroup.GET("/sessions", meHandler.ListSessions)
 
-	authHandler := handler.NewAuthHandler(r.handlers.Auth)
-	authGroup := v1.Group("/auth")
+	authHandler := handler.NewAuthHandler(r.handlers.Auth, r.sessions)
+	authGroup := v1.Group("/auth", r.limiter.Limit("auth", r.options.RateLimits.Auth, middleware.KeyByIP))
 	authGroup.POST("/login", authHandler.Login)
+	authGroup.POST("/login/2fa", authHandler.VerifyTwoFactor)
+	authGroup.POST("/magic-link", authHandler.RequestMagicLink)
+	authGroup.POST("/magic-link/exchange", authHandler.ExchangeMagicLink)
+	authGroup.GET("/oidc/authorize", authHandler.StartOIDCLogin)
+	authGroup.POST("/oidc/callback", authHandler.CompleteOIDCLogin)
+	authGroup.POST("/oidc/link", authHandler.LinkOIDCIdentity, r.jwtMw.Authenticate(), r.jwtMw.ForbidImpersonation())
 	authGroup.POST("/sign-in-with-token", authHandler.SignInWithToken)
+	authGroup.POST("/refresh", authHandler.Refresh)
+	authGroup.POST("/logout", authHandler.Logout, r.jwtMw.AuthenticatePasswordChange())
+	authGroup.POST("/forgot-password", authHandler.ForgotPassword)
+	authGroup.POST("/reset-password", authHandler.ResetPassword)
+	authGroup.POST("/accept-invitation", authHandler.AcceptInvitation)
+	authGroup.POST("/change-password", meHandler.ChangePassword, r.jwtMw.AuthenticatePasswordChange(), r.jwtMw.ForbidImpersonation())
+	authGroup.GET("/verify-email", authHandler.VerifyEmail)
+	authGroup.POST("/verify-email", authHandler.VerifyEmail)
+	authGroup.POST("/verify-email/resend", authHandler.ResendVerificationEmail)
+
+	twoFactorHandler := handler.NewTwoFactorHandler(r.handlers.TwoFactor)
+	twoFactorGroup := authGroup.Group("/2fa", r.jwtMw.Authenticate(), r.jwtMw.ForbidImpersonation())
+	twoFactorGroup.POST("/enroll", twoFactorHandler.Enroll)
+	twoFactorGroup.POST("/confirm", twoFactorHandler.Confirm)
+	twoFactorGroup.POST("/disable", twoFactorHandler.Disable)
+	twoFactorGroup.POST("/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+}
+
+// corsMiddleware mantiene la configuración abierta por defecto; con sesiones por cookies y orígenes
+// configurados solo esos orígenes pueden enviar credenciales y leer el header CSRF
+func corsMiddleware(sessions *cookie.SessionCookies) echo.MiddlewareFunc {
+	origins := sessions.CORSAllowedOrigins()
+	if len(origins) == 0 {
+		return echoMiddleware.CORS()
+	}
+
+	return echoMiddleware.CORSWithConfig(echoMiddleware.CORSConfig{
+		AllowOrigins:     origins,
+		AllowCredentials: true,
+		AllowHeaders: []string{
+			echo.HeaderOrigin,
+			echo.HeaderContentType,
+			echo.HeaderAccept,
+			echo.HeaderAuthorization,
+			cookie.CSRFTokenHeader,
+			cookie.SessionModeHeader,
+		},
+		ExposeHeaders: []string{cookie.CSRFTokenHeader},
+	})
 }
 
 func (r *Router) Start(addr string) error {
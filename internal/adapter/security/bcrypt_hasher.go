@@ -62,6 +62,16 @@ func (h *BcryptHasher) Verify(hashedPassword, password string) error {
 	return nil
 }
 
+// NeedsRehash indica si el hash fue generado con un costo distinto al configurado
+func (h *BcryptHasher) NeedsRehash(hashedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	if err != nil {
+		return true
+	}
+
+	return cost != h.cost
+}
+
 func (h *BcryptHasher) GetCost() int {
 	return h.cost
 }
@@ -0,0 +1,151 @@
+package security
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+const (
+	sha1HexLength = 40
+
+	// breachScanChunk es el tamaño de lectura al buscar el siguiente salto de línea
+	breachScanChunk = 64
+)
+
+// FileBreachChecker busca contraseñas en un archivo local con el formato de descarga de Have I Been Pwned
+// ordenado por hash: una línea "<SHA-1 en hexadecimal>:<apariciones>" por contraseña, ordenadas por hash.
+// La búsqueda binaria se hace sobre el archivo con lecturas puntuales, sin cargarlo en memoria.
+type FileBreachChecker struct {
+	file *os.File
+	size int64
+}
+
+// NewFileBreachChecker abre el archivo del corpus y valida que su primera línea tenga el formato esperado
+func NewFileBreachChecker(path string) (*FileBreachChecker, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	checker := &FileBreachChecker{file: file, size: info.Size()}
+
+	first, err := checker.hashAt(0)
+	if err != nil || !isHexHash(first) {
+		file.Close()
+		return nil, errors.New(dto.ErrPasswordBreachFileInvalid)
+	}
+
+	return checker, nil
+}
+
+// NewPasswordBreachCheckerFromEnv crea el verificador si PASSWORD_BREACH_CHECK está activo; si no, devuelve nil
+func NewPasswordBreachCheckerFromEnv() (interfaces.PasswordBreachChecker, error) {
+	enabled, _ := strconv.ParseBool(os.Getenv(dto.EnvPasswordBreachCheck))
+	if !enabled {
+		return nil, nil
+	}
+
+	path := os.Getenv(dto.EnvPasswordBreachFile)
+	if path == "" {
+		return nil, errors.New(dto.ErrPasswordBreachFileRequired)
+	}
+
+	return NewFileBreachChecker(path)
+}
+
+func (c *FileBreachChecker) IsBreached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	target := bytes.ToUpper([]byte(hex.EncodeToString(sum[:])))
+
+	// Invariante: lo es siempre el inicio de una línea y la línea buscada, si existe, empieza en [lo, hi)
+	lo, hi := int64(0), c.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		start, err := c.lineStart(mid)
+		if err != nil {
+			return false, err
+		}
+
+		if start >= hi {
+			hi = mid
+			continue
+		}
+
+		hash, err := c.hashAt(start)
+		if err != nil {
+			return false, err
+		}
+
+		switch cmp := bytes.Compare(hash, target); {
+		case cmp == 0:
+			return true, nil
+		case cmp < 0:
+			if lo, err = c.lineStart(start + 1); err != nil {
+				return false, err
+			}
+		default:
+			hi = start
+		}
+	}
+
+	return false, nil
+}
+
+// Close libera el archivo del corpus
+func (c *FileBreachChecker) Close() error {
+	return c.file.Close()
+}
+
+// lineStart devuelve el inicio de la primera línea que empieza en pos o después
+func (c *FileBreachChecker) lineStart(pos int64) (int64, error) {
+	if pos == 0 {
+		return 0, nil
+	}
+
+	buf := make([]byte, breachScanChunk)
+	for offset := pos - 1; offset < c.size; offset += breachScanChunk {
+		n, err := c.file.ReadAt(buf, offset)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			return offset + int64(i) + 1, nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+	}
+
+	return c.size, nil
+}
+
+// hashAt lee el hash de la línea que empieza en start, normalizado a mayúsculas
+func (c *FileBreachChecker) hashAt(start int64) ([]byte, error) {
+	buf := make([]byte, sha1HexLength)
+	n, err := c.file.ReadAt(buf, start)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return bytes.ToUpper(buf[:n]), nil
+}
+
+func isHexHash(hash []byte) bool {
+	if len(hash) != sha1HexLength {
+		return false
+	}
+	_, err := hex.Decode(make([]byte, sha1HexLength/2), hash)
+	return err == nil
+}
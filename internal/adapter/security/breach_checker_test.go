@@ -0,0 +1,83 @@
+package security
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func sha1Upper(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func writeBreachFile(t *testing.T, passwords []string) string {
+	t.Helper()
+
+	hashes := make([]string, 0, len(passwords))
+	for _, p := range passwords {
+		hashes = append(hashes, sha1Upper(p))
+	}
+	sort.Strings(hashes)
+
+	var b strings.Builder
+	for i, h := range hashes {
+		// Conteos de distinto largo para que las líneas no tengan tamaño fijo
+		fmt.Fprintf(&b, "%s:%d\r\n", h, (i+1)*997)
+	}
+
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		t.Fatalf("write corpus: %v", err)
+	}
+	return path
+}
+
+func TestFileBreachChecker_IsBreached(t *testing.T) {
+	breached := make([]string, 0, 200)
+	for i := 0; i < 200; i++ {
+		breached = append(breached, fmt.Sprintf("filtrada-%d", i))
+	}
+
+	checker, err := NewFileBreachChecker(writeBreachFile(t, breached))
+	if err != nil {
+		t.Fatalf("NewFileBreachChecker: %v", err)
+	}
+	defer checker.Close()
+
+	for _, p := range breached {
+		found, err := checker.IsBreached(p)
+		if err != nil {
+			t.Fatalf("IsBreached(%q): %v", p, err)
+		}
+		if !found {
+			t.Errorf("IsBreached(%q) = false, want true", p)
+		}
+	}
+
+	for _, p := range []string{"Segura#2025", "filtrada-200", ""} {
+		found, err := checker.IsBreached(p)
+		if err != nil {
+			t.Fatalf("IsBreached(%q): %v", p, err)
+		}
+		if found {
+			t.Errorf("IsBreached(%q) = true, want false", p)
+		}
+	}
+}
+
+func TestNewFileBreachChecker_RejectsInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invalid.txt")
+	if err := os.WriteFile(path, []byte("no es un hash\n"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	if _, err := NewFileBreachChecker(path); err == nil {
+		t.Error("expected error for file without SHA-1 hashes")
+	}
+}
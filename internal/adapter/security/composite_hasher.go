@@ -0,0 +1,142 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+const (
+	PasswordAlgorithmArgon2id = "argon2id"
+	PasswordAlgorithmBcrypt   = "bcrypt"
+
+	DefaultBcryptCost = 12
+)
+
+// FormatHasher es un hasher que reconoce sus propios hashes por el prefijo
+type FormatHasher interface {
+	interfaces.PasswordHasher
+	IsValidHash(hash string) bool
+}
+
+// CompositeHasher genera hashes con el algoritmo principal y verifica cualquier formato soportado,
+// detectado por el prefijo del hash. Así los hashes bcrypt existentes siguen funcionando y se
+// actualizan al algoritmo principal en el siguiente inicio de sesión.
+type CompositeHasher struct {
+	primary FormatHasher
+	legacy  []FormatHasher
+}
+
+func NewCompositeHasher(primary FormatHasher, legacy ...FormatHasher) *CompositeHasher {
+	return &CompositeHasher{
+		primary: primary,
+		legacy:  legacy,
+	}
+}
+
+// NewPasswordHasherFromEnv arma el hasher según PASSWORD_HASH_ALGORITHM (argon2id por defecto) y los
+// parámetros de costo de cada algoritmo; el algoritmo no elegido queda disponible solo para verificar.
+// Los parámetros de argon2id que no caben en su tipo se rechazan en lugar de truncarse en silencio.
+func NewPasswordHasherFromEnv() (*CompositeHasher, error) {
+	memory, err := uintFromEnv(dto.EnvArgon2MemoryKiB, uint64(DefaultArgon2Params.Memory), math.MaxUint32)
+	if err != nil {
+		return nil, err
+	}
+
+	iterations, err := uintFromEnv(dto.EnvArgon2Iterations, uint64(DefaultArgon2Params.Iterations), math.MaxUint32)
+	if err != nil {
+		return nil, err
+	}
+
+	parallelism, err := uintFromEnv(dto.EnvArgon2Parallelism, uint64(DefaultArgon2Params.Parallelism), math.MaxUint8)
+	if err != nil {
+		return nil, err
+	}
+
+	bcryptHasher := NewBcryptHasher(intFromEnv(dto.EnvBcryptCost, DefaultBcryptCost))
+	argon2Hasher := NewArgon2Hasher(Argon2Params{
+		Memory:      uint32(memory),
+		Iterations:  uint32(iterations),
+		Parallelism: uint8(parallelism),
+	})
+
+	if os.Getenv(dto.EnvPasswordHashAlgorithm) == PasswordAlgorithmBcrypt {
+		return NewCompositeHasher(bcryptHasher, argon2Hasher), nil
+	}
+
+	return NewCompositeHasher(argon2Hasher, bcryptHasher), nil
+}
+
+func (h *CompositeHasher) Hash(password string) (string, error) {
+	return h.primary.Hash(password)
+}
+
+func (h *CompositeHasher) Verify(hashedPassword, password string) error {
+	if hashedPassword == "" {
+		return errors.New(dto.ErrHashEmpty)
+	}
+
+	hasher := h.hasherFor(hashedPassword)
+	if hasher == nil {
+		return errors.New(dto.ErrHashFormatInvalid)
+	}
+
+	return hasher.Verify(hashedPassword, password)
+}
+
+// NeedsRehash es verdadero si el hash usa otro algoritmo que el principal o un costo desactualizado
+func (h *CompositeHasher) NeedsRehash(hashedPassword string) bool {
+	if !h.primary.IsValidHash(hashedPassword) {
+		return true
+	}
+
+	return h.primary.NeedsRehash(hashedPassword)
+}
+
+func (h *CompositeHasher) hasherFor(hashedPassword string) FormatHasher {
+	if h.primary.IsValidHash(hashedPassword) {
+		return h.primary
+	}
+
+	for _, hasher := range h.legacy {
+		if hasher.IsValidHash(hashedPassword) {
+			return hasher
+		}
+	}
+
+	return nil
+}
+
+// uintFromEnv lee un entero entre 1 y limit; sin valor usa fallback y fuera de rango retorna error
+func uintFromEnv(key string, fallback, limit uint64) (uint64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || n == 0 || n > limit {
+		return 0, fmt.Errorf(dto.ErrHashParamOutOfRange, key, limit)
+	}
+
+	return n, nil
+}
+
+func intFromEnv(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return fallback
+	}
+
+	return n
+}
@@ -0,0 +1,119 @@
+package security
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Parámetros bajos para que los tests sean rápidos
+var testArgon2Params = Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+func TestArgon2Hasher_HashAndVerify(t *testing.T) {
+	hasher := NewArgon2Hasher(testArgon2Params)
+
+	hash, err := hasher.Hash("Secreta#123")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Fatalf("unexpected hash format: %s", hash)
+	}
+
+	if err := hasher.Verify(hash, "Secreta#123"); err != nil {
+		t.Errorf("Verify with correct password: %v", err)
+	}
+	if err := hasher.Verify(hash, "Otra#123"); err == nil {
+		t.Error("Verify with wrong password should fail")
+	}
+	if hasher.NeedsRehash(hash) {
+		t.Error("hash with current params should not need rehash")
+	}
+
+	stronger := NewArgon2Hasher(Argon2Params{Memory: 2048, Iterations: 1, Parallelism: 1})
+	if !stronger.NeedsRehash(hash) {
+		t.Error("hash with lower memory should need rehash")
+	}
+}
+
+func TestCompositeHasher_VerifiesLegacyBcrypt(t *testing.T) {
+	legacy := NewBcryptHasher(bcrypt.MinCost)
+	hasher := NewCompositeHasher(NewArgon2Hasher(testArgon2Params), legacy)
+
+	bcryptHash, err := legacy.Hash("Secreta#123")
+	if err != nil {
+		t.Fatalf("bcrypt Hash: %v", err)
+	}
+
+	if err := hasher.Verify(bcryptHash, "Secreta#123"); err != nil {
+		t.Errorf("Verify legacy bcrypt hash: %v", err)
+	}
+	if err := hasher.Verify(bcryptHash, "Otra#123"); err == nil {
+		t.Error("Verify legacy bcrypt hash with wrong password should fail")
+	}
+	if !hasher.NeedsRehash(bcryptHash) {
+		t.Error("bcrypt hash should need rehash when argon2id is primary")
+	}
+
+	argonHash, err := hasher.Hash("Secreta#123")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if hasher.NeedsRehash(argonHash) {
+		t.Error("primary hash should not need rehash")
+	}
+
+	if err := hasher.Verify("plain-text", "Secreta#123"); err == nil {
+		t.Error("Verify with unknown hash format should fail")
+	}
+}
+
+func TestBcryptHasher_NeedsRehashOnCostChange(t *testing.T) {
+	hash, err := NewBcryptHasher(bcrypt.MinCost).Hash("Secreta#123")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	if NewBcryptHasher(bcrypt.MinCost).NeedsRehash(hash) {
+		t.Error("same cost should not need rehash")
+	}
+	if !NewBcryptHasher(bcrypt.MinCost + 1).NeedsRehash(hash) {
+		t.Error("different cost should need rehash")
+	}
+}
+
+func TestNewPasswordHasherFromEnv_Argon2Params(t *testing.T) {
+	tests := []struct {
+		name        string
+		parallelism string
+		memory      string
+		wantErr     bool
+	}{
+		{name: "defaults"},
+		{name: "valid values", parallelism: "4", memory: "1024"},
+		{name: "parallelism upper bound", parallelism: "255"},
+		{name: "parallelism overflows uint8", parallelism: "256", wantErr: true},
+		{name: "parallelism zero", parallelism: "0", wantErr: true},
+		{name: "parallelism negative", parallelism: "-1", wantErr: true},
+		{name: "parallelism not a number", parallelism: "dos", wantErr: true},
+		{name: "memory overflows uint32", memory: "4294967296", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(dto.EnvArgon2Parallelism, tt.parallelism)
+			t.Setenv(dto.EnvArgon2MemoryKiB, tt.memory)
+			t.Setenv(dto.EnvArgon2Iterations, "1")
+
+			hasher, err := NewPasswordHasherFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewPasswordHasherFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && hasher == nil {
+				t.Fatal("NewPasswordHasherFromEnv() returned a nil hasher")
+			}
+		})
+	}
+}
@@ -2,51 +2,231 @@ package security
 
 import (
 	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var (
-	signKey   *rsa.PrivateKey
-	verifyKey *rsa.PublicKey
-	once      sync.Once
+const (
+	privateKeySuffix = ".rsa"
+	publicKeySuffix  = ".rsa.pub"
 )
 
-func LoadFiles(privateFile, publicFile string) error {
-	var err error
-	once.Do(func() {
-		err = loadFiles(privateFile, publicFile)
-	})
-	return err
+// KeySource indica de dónde cargar las claves RSA. Si Dir está definido se usa el directorio;
+// de lo contrario se carga el par único PrivateFile/PublicFile.
+type KeySource struct {
+	// Dir contiene pares <kid>.rsa / <kid>.rsa.pub. Un <kid>.rsa.pub sin clave privada
+	// solo sirve para verificar tokens firmados con una clave ya retirada.
+	Dir string
+
+	// ActiveKID selecciona la clave con la que se firman los tokens nuevos.
+	// Si está vacío se usa el mayor kid (orden lexicográfico) que tenga clave privada.
+	ActiveKID string
+
+	PrivateFile string
+	PublicFile  string
+}
+
+// KeyRing mantiene una clave de firma activa y todas las claves de verificación vigentes.
+// Es seguro para uso concurrente y puede recargarse en caliente con Reload.
+type KeyRing struct {
+	source KeySource
+
+	mu         sync.RWMutex
+	activeKID  string
+	signKey    *rsa.PrivateKey
+	verifyKeys map[string]*rsa.PublicKey
+}
+
+func NewKeyRing(source KeySource) (*KeyRing, error) {
+	k := &KeyRing{source: source}
+	if err := k.Reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
 }
 
-func loadFiles(privateFile, publicFile string) error {
-	privateBytes, err := os.ReadFile(privateFile)
+// Reload vuelve a leer las claves desde su origen. Si la carga falla se conservan las claves actuales.
+func (k *KeyRing) Reload() error {
+	var (
+		activeKID  string
+		signKey    *rsa.PrivateKey
+		verifyKeys map[string]*rsa.PublicKey
+		err        error
+	)
+
+	if k.source.Dir != "" {
+		activeKID, signKey, verifyKeys, err = loadDir(k.source.Dir, k.source.ActiveKID)
+	} else {
+		activeKID, signKey, verifyKeys, err = loadPair(k.source.PrivateFile, k.source.PublicFile)
+	}
 	if err != nil {
 		return err
 	}
 
-	publicBytes, err := os.ReadFile(publicFile)
+	k.mu.Lock()
+	k.activeKID = activeKID
+	k.signKey = signKey
+	k.verifyKeys = verifyKeys
+	k.mu.Unlock()
+
+	return nil
+}
+
+// Signer retorna el kid y la clave privada con la que se firman los tokens nuevos
+func (k *KeyRing) Signer() (string, *rsa.PrivateKey) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.activeKID, k.signKey
+}
+
+// VerificationKey busca la clave pública de un kid. Los tokens emitidos antes de que
+// existieran kids no traen cabecera, así que un kid vacío se verifica con la clave activa.
+func (k *KeyRing) VerificationKey(kid string) (*rsa.PublicKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if kid == "" {
+		kid = k.activeKID
+	}
+
+	key, ok := k.verifyKeys[kid]
+	return key, ok
+}
+
+func (k *KeyRing) PublicJWKs() []domain.JSONWebKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	kids := make([]string, 0, len(k.verifyKeys))
+	for kid := range k.verifyKeys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	keys := make([]domain.JSONWebKey, 0, len(kids))
+	for _, kid := range kids {
+		pub := k.verifyKeys[kid]
+		keys = append(keys, domain.JSONWebKey{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: jwt.SigningMethodRS256.Alg(),
+			Kid: kid,
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		})
+	}
+
+	return keys
+}
+
+func loadDir(dir, activeKID string) (string, *rsa.PrivateKey, map[string]*rsa.PublicKey, error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return err
+		return "", nil, nil, err
+	}
+
+	signKeys := make(map[string]*rsa.PrivateKey)
+	verifyKeys := make(map[string]*rsa.PublicKey)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		path := filepath.Join(dir, name)
+
+		switch {
+		case strings.HasSuffix(name, publicKeySuffix):
+			pub, err := readPublicKey(path)
+			if err != nil {
+				return "", nil, nil, fmt.Errorf("%s: %w", name, err)
+			}
+			verifyKeys[strings.TrimSuffix(name, publicKeySuffix)] = pub
+		case strings.HasSuffix(name, privateKeySuffix):
+			priv, err := readPrivateKey(path)
+			if err != nil {
+				return "", nil, nil, fmt.Errorf("%s: %w", name, err)
+			}
+			signKeys[strings.TrimSuffix(name, privateKeySuffix)] = priv
+		}
+	}
+
+	// Una clave privada sin su .pub sigue pudiendo verificar lo que firma
+	for kid, priv := range signKeys {
+		if _, ok := verifyKeys[kid]; !ok {
+			verifyKeys[kid] = &priv.PublicKey
+		}
+	}
+
+	if activeKID == "" {
+		for kid := range signKeys {
+			if kid > activeKID {
+				activeKID = kid
+			}
+		}
 	}
 
-	return parseRSA(privateBytes, publicBytes)
+	signKey, ok := signKeys[activeKID]
+	if !ok {
+		return "", nil, nil, fmt.Errorf(dto.ErrActiveSigningKeyNotFound, activeKID)
+	}
+
+	return activeKID, signKey, verifyKeys, nil
 }
 
-func parseRSA(privateByes, publicBytes []byte) error {
-	var err error
-	signKey, err = jwt.ParseRSAPrivateKeyFromPEM(privateByes)
+func loadPair(privateFile, publicFile string) (string, *rsa.PrivateKey, map[string]*rsa.PublicKey, error) {
+	signKey, err := readPrivateKey(privateFile)
 	if err != nil {
-		return err
+		return "", nil, nil, err
 	}
 
-	verifyKey, err = jwt.ParseRSAPublicKeyFromPEM(publicBytes)
+	verifyKey, err := readPublicKey(publicFile)
 	if err != nil {
-		return err
+		return "", nil, nil, err
 	}
 
-	return nil
+	if signKey.PublicKey.N.Cmp(verifyKey.N) != 0 || signKey.PublicKey.E != verifyKey.E {
+		return "", nil, nil, errors.New(dto.ErrRSAKeyPairMismatch)
+	}
+
+	kid := thumbprint(verifyKey)
+	return kid, signKey, map[string]*rsa.PublicKey{kid: verifyKey}, nil
+}
+
+func readPrivateKey(path string) (*rsa.PrivateKey, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.ParseRSAPrivateKeyFromPEM(bytes)
+}
+
+func readPublicKey(path string) (*rsa.PublicKey, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.ParseRSAPublicKeyFromPEM(bytes)
+}
+
+// thumbprint calcula el JWK thumbprint (RFC 7638) de una clave pública, usado como kid
+// cuando la clave no proviene de un directorio con nombres explícitos
+func thumbprint(pub *rsa.PublicKey) string {
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	n := base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+	sum := sha256.Sum256([]byte(`{"e":"` + e + `","kty":"RSA","n":"` + n + `"}`))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
 }
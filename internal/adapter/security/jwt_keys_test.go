@@ -0,0 +1,120 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+func writeKeyPair(t *testing.T, dir, kid string, withPrivate bool) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	pubBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey() error = %v", err)
+	}
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubBytes})
+	if err := os.WriteFile(filepath.Join(dir, kid+publicKeySuffix), pubPEM, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if withPrivate {
+		privPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+		if err := os.WriteFile(filepath.Join(dir, kid+privateKeySuffix), privPEM, 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+}
+
+func TestKeyRing_RotationKeepsOldTokensValid(t *testing.T) {
+	dir := t.TempDir()
+	writeKeyPair(t, dir, "2025-01", true)
+
+	keyRing, err := NewKeyRing(KeySource{Dir: dir})
+	if err != nil {
+		t.Fatalf("NewKeyRing() error = %v", err)
+	}
+
+	service, err := NewJWTService(keyRing, time.Minute)
+	if err != nil {
+		t.Fatalf("NewJWTService() error = %v", err)
+	}
+
+	user := domain.User{ID: "user-1", Email: "user@appfe.com", Role: domain.UserRole}
+
+	oldToken, err := service.GenerateToken(user, domain.TokenOptions{})
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	writeKeyPair(t, dir, "2025-02", true)
+	if err := keyRing.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	if kid, _ := keyRing.Signer(); kid != "2025-02" {
+		t.Errorf("Signer() kid = %s, want 2025-02", kid)
+	}
+
+	if _, err := service.ValidateToken(oldToken); err != nil {
+		t.Errorf("ValidateToken() on token signed with retired key error = %v", err)
+	}
+
+	newToken, err := service.GenerateToken(user, domain.TokenOptions{})
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	if _, err := service.ValidateToken(newToken); err != nil {
+		t.Errorf("ValidateToken() on token signed with active key error = %v", err)
+	}
+
+	if keys := keyRing.PublicJWKs(); len(keys) != 2 {
+		t.Errorf("PublicJWKs() returned %d keys, want 2", len(keys))
+	}
+
+	if err := os.Remove(filepath.Join(dir, "2025-01"+publicKeySuffix)); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := os.Remove(filepath.Join(dir, "2025-01"+privateKeySuffix)); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := keyRing.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	if _, err := service.ValidateToken(oldToken); err == nil {
+		t.Error("ValidateToken() expected error after removing the signing key")
+	}
+}
+
+func TestKeyRing_ActiveKIDWithoutPrivateKey(t *testing.T) {
+	dir := t.TempDir()
+	writeKeyPair(t, dir, "signer", true)
+	writeKeyPair(t, dir, "verify-only", false)
+
+	if _, err := NewKeyRing(KeySource{Dir: dir, ActiveKID: "verify-only"}); err == nil {
+		t.Error("NewKeyRing() expected error when active kid has no private key")
+	}
+
+	keyRing, err := NewKeyRing(KeySource{Dir: dir})
+	if err != nil {
+		t.Fatalf("NewKeyRing() error = %v", err)
+	}
+
+	if kid, _ := keyRing.Signer(); kid != "signer" {
+		t.Errorf("Signer() kid = %s, want signer", kid)
+	}
+}
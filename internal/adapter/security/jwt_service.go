@@ -1,55 +1,82 @@
 package security
 
 import (
-	"crypto/rsa"
 	"errors"
 	"time"
 
 	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type JWTClaims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID       string `json:"user_id"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	TokenVersion int    `json:"tv"`
+	TwoFactor    bool   `json:"mfa,omitempty"`
+	PwdChange    bool   `json:"pwd_change,omitempty"`
+	SessionID    string `json:"sid,omitempty"`
+
+	// Act identifica al administrador que suplanta al usuario (RFC 8693, sección 4.1)
+	Act *ActorClaim `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
+type ActorClaim struct {
+	Subject string `json:"sub"`
+}
+
 type JWTService struct {
-	privateKey *rsa.PrivateKey
-	publicKey  *rsa.PublicKey
+	keys *KeyRing
+	ttl  time.Duration
 }
 
-func NewJWTService() (*JWTService, error) {
-	if signKey == nil || verifyKey == nil {
+func NewJWTService(keys *KeyRing, ttl time.Duration) (*JWTService, error) {
+	if keys == nil {
 		return nil, errors.New("las claves JWT no han sido cargadas")
 	}
 
 	return &JWTService{
-		privateKey: signKey,
-		publicKey:  verifyKey,
+		keys: keys,
+		ttl:  ttl,
 	}, nil
 }
 
-func (j *JWTService) GenerateToken(user domain.User) (string, error) {
+func (j *JWTService) GenerateToken(user domain.User, opts domain.TokenOptions) (string, error) {
+	ttl := j.ttl
+	if opts.TTL > 0 {
+		ttl = opts.TTL
+	}
+
 	claims := JWTClaims{
-		UserID: user.ID,
-		Email:  user.Email,
-		Role:   user.Role,
+		UserID:       user.ID,
+		Email:        user.Email,
+		Role:         user.Role,
+		TokenVersion: user.TokenVersion,
+		TwoFactor:    opts.TwoFactor,
+		PwdChange:    opts.PasswordChangeRequired,
+		SessionID:    opts.SessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "geekway-api",
 			Subject:   user.ID,
-			ID:        user.ID,
+			ID:        uuid.NewString(),
 		},
 	}
 
+	if opts.ActorID != "" {
+		claims.Act = &ActorClaim{Subject: opts.ActorID}
+	}
+
+	kid, signKey := j.keys.Signer()
+
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
 
-	tokenString, err := token.SignedString(j.privateKey)
+	tokenString, err := token.SignedString(signKey)
 	if err != nil {
 		return "", err
 	}
@@ -57,12 +84,18 @@ func (j *JWTService) GenerateToken(user domain.User) (string, error) {
 	return tokenString, nil
 }
 
-func (j *JWTService) ValidateToken(tokenString string) (*domain.User, error) {
+func (j *JWTService) ValidateToken(tokenString string) (*domain.TokenClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
 			return nil, errors.New("método de firma inválido")
 		}
-		return j.publicKey, nil
+
+		kid, _ := token.Header["kid"].(string)
+		key, ok := j.keys.VerificationKey(kid)
+		if !ok {
+			return nil, errors.New("clave de firma desconocida")
+		}
+		return key, nil
 	})
 
 	if err != nil {
@@ -78,11 +111,33 @@ func (j *JWTService) ValidateToken(tokenString string) (*domain.User, error) {
 		return nil, errors.New("claims inválidos")
 	}
 
-	user := &domain.User{
-		ID:    claims.UserID,
-		Email: claims.Email,
-		Role:  claims.Role,
+	tokenClaims := &domain.TokenClaims{
+		TokenID:      claims.ID,
+		UserID:       claims.UserID,
+		Email:        claims.Email,
+		Role:         claims.Role,
+		TokenVersion: claims.TokenVersion,
+		TwoFactor:    claims.TwoFactor,
+		SessionID:    claims.SessionID,
+
+		PasswordChangeRequired: claims.PwdChange,
+	}
+
+	if claims.IssuedAt != nil {
+		tokenClaims.IssuedAt = claims.IssuedAt.Time
+	}
+
+	if claims.ExpiresAt != nil {
+		tokenClaims.ExpiresAt = claims.ExpiresAt.Time
 	}
 
-	return user, nil
+	if claims.Act != nil {
+		tokenClaims.ActorID = claims.Act.Subject
+	}
+
+	return tokenClaims, nil
+}
+
+func (j *JWTService) PublicJWKs() []domain.JSONWebKey {
+	return j.keys.PublicJWKs()
 }
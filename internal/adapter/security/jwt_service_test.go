@@ -0,0 +1,79 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+func newTestJWTService(t *testing.T) *JWTService {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeKeyPair(t, dir, "test", true)
+
+	keyRing, err := NewKeyRing(KeySource{Dir: dir})
+	if err != nil {
+		t.Fatalf("NewKeyRing() error = %v", err)
+	}
+
+	service, err := NewJWTService(keyRing, time.Hour)
+	if err != nil {
+		t.Fatalf("NewJWTService() error = %v", err)
+	}
+
+	return service
+}
+
+func TestJWTService_ActorClaimRoundTrip(t *testing.T) {
+	service := newTestJWTService(t)
+	user := domain.User{ID: "user-1", Email: "ana@appfe.com", Role: domain.UserRole, TokenVersion: 3}
+
+	tests := []struct {
+		name              string
+		opts              domain.TokenOptions
+		wantActor         string
+		wantImpersonation bool
+		wantTTL           time.Duration
+	}{
+		{name: "regular session", opts: domain.TokenOptions{SessionID: "session-1"}, wantTTL: time.Hour},
+		{name: "impersonation", opts: domain.TokenOptions{ActorID: "admin-1", TTL: 15 * time.Minute, SessionID: "session-2"}, wantActor: "admin-1", wantImpersonation: true, wantTTL: 15 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuedAt := time.Now()
+
+			token, err := service.GenerateToken(user, tt.opts)
+			if err != nil {
+				t.Fatalf("GenerateToken() error = %v", err)
+			}
+
+			claims, err := service.ValidateToken(token)
+			if err != nil {
+				t.Fatalf("ValidateToken() error = %v", err)
+			}
+
+			if claims.ActorID != tt.wantActor {
+				t.Errorf("ActorID = %q, want %q", claims.ActorID, tt.wantActor)
+			}
+			if claims.IsImpersonation() != tt.wantImpersonation {
+				t.Errorf("IsImpersonation() = %v, want %v", claims.IsImpersonation(), tt.wantImpersonation)
+			}
+			if claims.UserID != user.ID || claims.TokenVersion != user.TokenVersion || claims.SessionID != tt.opts.SessionID {
+				t.Errorf("claims = %+v, want user %q, version %d, session %q", claims, user.ID, user.TokenVersion, tt.opts.SessionID)
+			}
+
+			// Las fechas del JWT tienen resolución de segundos
+			if diff := claims.IssuedAt.Sub(issuedAt); diff < -time.Second || diff > time.Second {
+				t.Errorf("IssuedAt = %v, want about %v", claims.IssuedAt, issuedAt)
+			}
+
+			wantExpiry := issuedAt.Add(tt.wantTTL)
+			if diff := claims.ExpiresAt.Sub(wantExpiry); diff < -time.Second || diff > time.Second {
+				t.Errorf("ExpiresAt = %v, want about %v", claims.ExpiresAt, wantExpiry)
+			}
+		})
+	}
+}
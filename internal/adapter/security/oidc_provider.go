@@ -0,0 +1,430 @@
+package security
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+	// oidcHTTPTimeout limita cada petición al proveedor
+	oidcHTTPTimeout = 10 * time.Second
+
+	// oidcJWKSRefreshInterval evita que un token con un kid desconocido fuerce una descarga del JWKS por petición
+	oidcJWKSRefreshInterval = time.Minute
+
+	// oidcClockSkew tolera pequeñas diferencias de reloj con el proveedor al validar exp, iat y nbf
+	oidcClockSkew = 30 * time.Second
+
+	// oidcMaxResponseBytes limita el tamaño de las respuestas leídas del proveedor
+	oidcMaxResponseBytes = 1 << 20
+)
+
+var (
+	defaultOIDCScopes = []string{"openid", "email", "profile"}
+
+	oidcSigningMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+)
+
+// OIDCConfig identifica a esta API como cliente (relying party) del proveedor
+type OIDCConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// OIDCProvider obtiene los endpoints del proveedor desde su documento de descubrimiento y verifica los
+// ID tokens con las claves publicadas en su JWKS. Ambos se descargan en el primer uso, así el servidor
+// arranca aunque el proveedor no esté disponible.
+type OIDCProvider struct {
+	config OIDCConfig
+	client *http.Client
+
+	mu            sync.Mutex
+	discovery     *oidcDiscovery
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+	keysFetching  bool
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcTokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type oidcIDTokenClaims struct {
+	Email           string   `json:"email"`
+	EmailVerified   oidcBool `json:"email_verified"`
+	Name            string   `json:"name"`
+	Nonce           string   `json:"nonce"`
+	AuthorizedParty string   `json:"azp"`
+	jwt.RegisteredClaims
+}
+
+// oidcBool acepta email_verified como booleano o como texto ("true"), que algunos proveedores envían así
+type oidcBool bool
+
+func (b *oidcBool) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		*b = oidcBool(v)
+	case string:
+		*b = oidcBool(strings.EqualFold(v, "true"))
+	default:
+		*b = false
+	}
+
+	return nil
+}
+
+func NewOIDCProvider(config OIDCConfig, client *http.Client) *OIDCProvider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = defaultOIDCScopes
+	}
+	if client == nil {
+		client = &http.Client{Timeout: oidcHTTPTimeout}
+	}
+
+	return &OIDCProvider{config: config, client: client}
+}
+
+// NewOIDCProviderFromEnv crea el cliente OIDC si OIDC_ISSUER_URL está definido; si no, devuelve nil
+func NewOIDCProviderFromEnv() (interfaces.OIDCProvider, error) {
+	issuer := strings.TrimSpace(os.Getenv(dto.EnvOIDCIssuerURL))
+	if issuer == "" {
+		return nil, nil
+	}
+
+	config := OIDCConfig{
+		IssuerURL:    issuer,
+		ClientID:     os.Getenv(dto.EnvOIDCClientID),
+		ClientSecret: os.Getenv(dto.EnvOIDCClientSecret),
+		RedirectURL:  os.Getenv(dto.EnvOIDCRedirectURL),
+		Scopes:       strings.Fields(os.Getenv(dto.EnvOIDCScopes)),
+	}
+
+	if config.ClientID == "" || config.RedirectURL == "" {
+		return nil, errors.New(dto.ErrOIDCConfigRequired)
+	}
+
+	return NewOIDCProvider(config, nil), nil
+}
+
+func (p *OIDCProvider) AuthorizationURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf(dto.ErrOIDCDiscoveryFailed, err)
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", pkceChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+func (p *OIDCProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.OIDCIdentity, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, err := p.requestIDToken(ctx, discovery.TokenEndpoint, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.verifyIDToken(ctx, discovery.Issuer, rawIDToken, nonce)
+}
+
+func (p *OIDCProvider) requestIDToken(ctx context.Context, tokenEndpoint, code, codeVerifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+
+	// Los clientes públicos se identifican solo con client_id; los confidenciales con client_secret_basic
+	if p.config.ClientSecret == "" {
+		form.Set("client_id", p.config.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf(dto.ErrOIDCTokenRequestFailed, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf(dto.ErrOIDCTokenRequestFailed, err)
+	}
+	defer resp.Body.Close()
+
+	// Las respuestas de error también traen JSON (RFC 6749, sección 5.2), aunque no siempre
+	var body oidcTokenResponse
+	decodeErr := json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponseBytes)).Decode(&body)
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf(dto.ErrOIDCTokenRejected, resp.StatusCode, strings.TrimSpace(body.Error+" "+body.ErrorDescription))
+	}
+
+	if decodeErr != nil {
+		return "", fmt.Errorf(dto.ErrOIDCTokenRequestFailed, decodeErr)
+	}
+
+	if body.IDToken == "" {
+		return "", errors.New(dto.ErrOIDCIDTokenMissing)
+	}
+
+	return body.IDToken, nil
+}
+
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, issuer, rawIDToken, nonce string) (*domain.OIDCIdentity, error) {
+	claims := &oidcIDTokenClaims{}
+
+	_, err := jwt.ParseWithClaims(rawIDToken, claims,
+		func(token *jwt.Token) (any, error) {
+			kid, _ := token.Header["kid"].(string)
+			return p.signingKey(ctx, kid)
+		},
+		jwt.WithValidMethods(oidcSigningMethods),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(oidcClockSkew),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, errors.New(dto.ErrOIDCNonceMismatch)
+	}
+
+	// Con varias audiencias el token debe indicar que fue emitido para este cliente (OIDC Core 3.1.3.7)
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID {
+		return nil, errors.New(dto.ErrOIDCAuthorizedParty)
+	}
+
+	return &domain.OIDCIdentity{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+func (p *OIDCProvider) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	issuer := strings.TrimRight(p.config.IssuerURL, "/")
+
+	var discovery oidcDiscovery
+	if err := p.getJSON(ctx, issuer+oidcDiscoveryPath, &discovery); err != nil {
+		return nil, fmt.Errorf(dto.ErrOIDCDiscoveryFailed, err)
+	}
+
+	if strings.TrimRight(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf(dto.ErrOIDCIssuerMismatch, discovery.Issuer, p.config.IssuerURL)
+	}
+
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf(dto.ErrOIDCDiscoveryFailed, errors.New("missing endpoints"))
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// signingKey busca la clave por kid y vuelve a descargar el JWKS si no la encuentra, para soportar la
+// rotación de claves del proveedor. La descarga se hace sin retener el mutex para no bloquear a las
+// peticiones cuyo kid ya es conocido; mientras tanto, las demás con un kid desconocido fallan.
+func (p *OIDCProvider) signingKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		p.mu.Unlock()
+		return key, nil
+	}
+
+	if p.keysFetching || time.Since(p.keysFetchedAt) < oidcJWKSRefreshInterval {
+		p.mu.Unlock()
+		return nil, fmt.Errorf(dto.ErrOIDCSigningKeyUnknown, kid)
+	}
+
+	p.keysFetching = true
+	jwksURI := p.discovery.JWKSURI
+	p.mu.Unlock()
+
+	var jwks struct {
+		Keys []domain.JSONWebKey `json:"keys"`
+	}
+	err := p.getJSON(ctx, jwksURI, &jwks)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.keysFetching = false
+	if err != nil {
+		return nil, fmt.Errorf(dto.ErrOIDCJWKSFailed, err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if key, err := parseJWK(jwk); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf(dto.ErrOIDCSigningKeyUnknown, kid)
+}
+
+// lookupKey acepta un token sin kid solo si el proveedor publica una única clave
+func (p *OIDCProvider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, endpoint string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d", endpoint, resp.StatusCode)
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponseBytes)).Decode(target)
+}
+
+func parseJWK(jwk domain.JSONWebKey) (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+}
+
+// pkceChallenge calcula el code_challenge S256 del verificador (RFC 7636, sección 4.2)
+func pkceChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
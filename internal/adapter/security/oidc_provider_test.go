@@ -0,0 +1,356 @@
+package security
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	stubClientID     = "appfe-portal"
+	stubClientSecret = "s3cr3t"
+	stubRedirectURL  = "https://portal.appfe.com/oidc/callback"
+)
+
+// stubOIDCServer es un proveedor OIDC mínimo: descubrimiento, JWKS y token endpoint con PKCE
+type stubOIDCServer struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu        sync.Mutex
+	key       *rsa.PrivateKey
+	kid       string
+	challenge map[string]string
+	nonces    map[string]string
+
+	// mutate permite a cada prueba alterar los claims del ID token emitido
+	mutate func(claims jwt.MapClaims)
+	// signWith, si se define, firma el ID token con otra clave que la publicada
+	signWith *rsa.PrivateKey
+}
+
+func newStubOIDCServer(t *testing.T) *stubOIDCServer {
+	t.Helper()
+
+	stub := &stubOIDCServer{
+		t:         t,
+		key:       generateRSAKey(t),
+		kid:       "stub-1",
+		challenge: map[string]string{},
+		nonces:    map[string]string{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", stub.discovery)
+	mux.HandleFunc("/jwks", stub.jwks)
+	mux.HandleFunc("/token", stub.token)
+	stub.server = httptest.NewServer(mux)
+	t.Cleanup(stub.server.Close)
+
+	return stub
+}
+
+func generateRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	return key
+}
+
+func (s *stubOIDCServer) provider() *OIDCProvider {
+	return NewOIDCProvider(OIDCConfig{
+		IssuerURL:    s.server.URL,
+		ClientID:     stubClientID,
+		ClientSecret: stubClientSecret,
+		RedirectURL:  stubRedirectURL,
+	}, s.server.Client())
+}
+
+// authorize simula el inicio de sesión del usuario en el proveedor y retorna el código de autorización
+func (s *stubOIDCServer) authorize(authorizationURL string) string {
+	s.t.Helper()
+
+	parsed, err := url.Parse(authorizationURL)
+	if err != nil {
+		s.t.Fatalf("url.Parse() error = %v", err)
+	}
+	query := parsed.Query()
+
+	if query.Get("code_challenge_method") != "S256" {
+		s.t.Fatalf("code_challenge_method = %q, want S256", query.Get("code_challenge_method"))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code := "code-" + query.Get("state")
+	s.challenge[code] = query.Get("code_challenge")
+	s.nonces[code] = query.Get("nonce")
+	return code
+}
+
+func (s *stubOIDCServer) rotateKey() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.key = generateRSAKey(s.t)
+	s.kid = "stub-2"
+}
+
+func (s *stubOIDCServer) discovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]string{
+		"issuer":                 s.server.URL,
+		"authorization_endpoint": s.server.URL + "/authorize",
+		"token_endpoint":         s.server.URL + "/token",
+		"jwks_uri":               s.server.URL + "/jwks",
+	})
+}
+
+func (s *stubOIDCServer) jwks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pub := s.key.PublicKey
+	json.NewEncoder(w).Encode(map[string]any{
+		"keys": []domain.JSONWebKey{{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: s.kid,
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func (s *stubOIDCServer) token(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clientID, secret, ok := r.BasicAuth()
+	if !ok || clientID != stubClientID || secret != stubClientSecret {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	code := r.FormValue("code")
+	challenge, ok := s.challenge[code]
+	delete(s.challenge, code)
+
+	if !ok || r.FormValue("grant_type") != "authorization_code" || r.FormValue("redirect_uri") != stubRedirectURL ||
+		pkceChallenge(r.FormValue("code_verifier")) != challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            s.server.URL,
+		"aud":            stubClientID,
+		"sub":            "provider-user-1",
+		"email":          "socio@appfe.com",
+		"email_verified": true,
+		"name":           "Socia Ejemplo",
+		"nonce":          s.nonces[code],
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+	}
+	if s.mutate != nil {
+		s.mutate(claims)
+	}
+
+	signKey := s.key
+	if s.signWith != nil {
+		signKey = s.signWith
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = s.kid
+	idToken, err := token.SignedString(signKey)
+	if err != nil {
+		s.t.Errorf("SignedString() error = %v", err)
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"access_token": "opaque", "token_type": "Bearer", "id_token": idToken})
+}
+
+func runOIDCFlow(t *testing.T, stub *stubOIDCServer, provider *OIDCProvider, verifier string) (*domain.OIDCIdentity, error) {
+	t.Helper()
+
+	authorizationURL, err := provider.AuthorizationURL(context.Background(), "state-"+verifier, "nonce-1", "verifier-"+verifier)
+	if err != nil {
+		t.Fatalf("AuthorizationURL() error = %v", err)
+	}
+
+	return provider.Exchange(context.Background(), stub.authorize(authorizationURL), "verifier-"+verifier, "nonce-1")
+}
+
+func TestOIDCProvider_AuthorizationURL(t *testing.T) {
+	stub := newStubOIDCServer(t)
+
+	authorizationURL, err := stub.provider().AuthorizationURL(context.Background(), "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatalf("AuthorizationURL() error = %v", err)
+	}
+
+	if !strings.HasPrefix(authorizationURL, stub.server.URL+"/authorize?") {
+		t.Fatalf("AuthorizationURL() = %q, want provider authorization endpoint", authorizationURL)
+	}
+
+	parsed, _ := url.Parse(authorizationURL)
+	query := parsed.Query()
+
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             stubClientID,
+		"redirect_uri":          stubRedirectURL,
+		"scope":                 "openid email profile",
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge":        pkceChallenge("verifier-1"),
+		"code_challenge_method": "S256",
+	}
+	for param, value := range want {
+		if got := query.Get(param); got != value {
+			t.Errorf("%s = %q, want %q", param, got, value)
+		}
+	}
+
+	if query.Get("code_verifier") != "" {
+		t.Error("code_verifier must not be sent in the authorization URL")
+	}
+}
+
+func TestOIDCProvider_Exchange(t *testing.T) {
+	stub := newStubOIDCServer(t)
+
+	identity, err := runOIDCFlow(t, stub, stub.provider(), "ok")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+
+	if identity.Issuer != stub.server.URL || identity.Subject != "provider-user-1" || identity.Email != "socio@appfe.com" || !identity.EmailVerified || identity.Name != "Socia Ejemplo" {
+		t.Errorf("Exchange() identity = %+v", identity)
+	}
+}
+
+func TestOIDCProvider_ExchangeRejectsWrongCodeVerifier(t *testing.T) {
+	stub := newStubOIDCServer(t)
+	provider := stub.provider()
+
+	authorizationURL, err := provider.AuthorizationURL(context.Background(), "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatalf("AuthorizationURL() error = %v", err)
+	}
+
+	if _, err := provider.Exchange(context.Background(), stub.authorize(authorizationURL), "otro-verifier", "nonce-1"); err == nil {
+		t.Fatal("Exchange() with a different code_verifier should fail")
+	}
+}
+
+func TestOIDCProvider_ExchangeRejectsInvalidIDTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(claims jwt.MapClaims)
+		signWith bool
+	}{
+		{
+			name:   "other audience",
+			mutate: func(claims jwt.MapClaims) { claims["aud"] = "otro-cliente" },
+		},
+		{
+			name:   "other issuer",
+			mutate: func(claims jwt.MapClaims) { claims["iss"] = "https://idp.example.com" },
+		},
+		{
+			name:   "expired",
+			mutate: func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+		},
+		{
+			name:   "missing expiration",
+			mutate: func(claims jwt.MapClaims) { delete(claims, "exp") },
+		},
+		{
+			name:   "other nonce",
+			mutate: func(claims jwt.MapClaims) { claims["nonce"] = "nonce-repetido" },
+		},
+		{
+			name:   "several audiences without azp",
+			mutate: func(claims jwt.MapClaims) { claims["aud"] = []string{stubClientID, "otro-cliente"} },
+		},
+		{
+			name:     "signed with an unpublished key",
+			signWith: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newStubOIDCServer(t)
+			stub.mutate = tt.mutate
+			if tt.signWith {
+				stub.signWith = generateRSAKey(t)
+			}
+
+			if identity, err := runOIDCFlow(t, stub, stub.provider(), "x"); err == nil {
+				t.Fatalf("Exchange() = %+v, want error", identity)
+			}
+		})
+	}
+}
+
+func TestOIDCProvider_EmailVerifiedAsString(t *testing.T) {
+	stub := newStubOIDCServer(t)
+	stub.mutate = func(claims jwt.MapClaims) { claims["email_verified"] = "true" }
+
+	identity, err := runOIDCFlow(t, stub, stub.provider(), "ok")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+
+	if !identity.EmailVerified {
+		t.Error("EmailVerified = false, want true")
+	}
+}
+
+func TestOIDCProvider_RefreshesJWKSAfterKeyRotation(t *testing.T) {
+	stub := newStubOIDCServer(t)
+	provider := stub.provider()
+
+	if _, err := runOIDCFlow(t, stub, provider, "before"); err != nil {
+		t.Fatalf("Exchange() before rotation error = %v", err)
+	}
+
+	stub.rotateKey()
+
+	// Dentro del intervalo mínimo el JWKS no se vuelve a descargar
+	if _, err := runOIDCFlow(t, stub, provider, "throttled"); err == nil {
+		t.Fatal("Exchange() should fail while the JWKS refresh is throttled")
+	}
+
+	provider.mu.Lock()
+	provider.keysFetchedAt = time.Now().Add(-oidcJWKSRefreshInterval)
+	provider.mu.Unlock()
+
+	if _, err := runOIDCFlow(t, stub, provider, "after"); err != nil {
+		t.Fatalf("Exchange() after rotation error = %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+const defaultTokenBytes = 32
+
+// SecureTokenGenerator genera tokens aleatorios criptográficamente seguros y los
+// persiste como SHA-256, de modo que una filtración de la base de datos no expone tokens utilizables
+type SecureTokenGenerator struct {
+	size int
+}
+
+func NewSecureTokenGenerator() *SecureTokenGenerator {
+	return &SecureTokenGenerator{size: defaultTokenBytes}
+}
+
+func (g *SecureTokenGenerator) Generate() (string, string, error) {
+	bytes := make([]byte, g.size)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", fmt.Errorf(dto.ErrTokenRandomGeneration, err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(bytes)
+	return token, g.Hash(token), nil
+}
+
+func (g *SecureTokenGenerator) Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,102 @@
+package security
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Vectores del RFC 6238 (SHA1), truncados a 6 dígitos
+func TestTOTPProvider_ValidateRFCVectors(t *testing.T) {
+	provider := NewTOTPProvider("APPFE Lima")
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, v := range vectors {
+		step, ok := provider.Validate(secret, v.code, time.Unix(v.unix, 0))
+		if !ok {
+			t.Errorf("code %s at %d should be valid", v.code, v.unix)
+			continue
+		}
+		if step != v.unix/30 {
+			t.Errorf("step = %d, want %d", step, v.unix/30)
+		}
+	}
+}
+
+func TestTOTPProvider_ValidateSkew(t *testing.T) {
+	provider := NewTOTPProvider("APPFE Lima")
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	// "287082" es el código del paso 1 (segundos 30 a 59)
+	tests := []struct {
+		name     string
+		code     string
+		unix     int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "same step", code: "287082", unix: 59, wantStep: 1, wantOK: true},
+		{name: "previous step", code: "287082", unix: 59 + 30, wantStep: 1, wantOK: true},
+		{name: "next step", code: "287082", unix: 29, wantStep: 1, wantOK: true},
+		{name: "two steps old", code: "287082", unix: 59 + 60, wantOK: false},
+		{name: "older than one step", code: "287082", unix: 59 + 90, wantOK: false},
+		{name: "short code", code: "28708", unix: 59, wantOK: false},
+		{name: "wrong code", code: "287083", unix: 59, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := provider.Validate(secret, tt.code, time.Unix(tt.unix, 0))
+			if ok != tt.wantOK || (ok && step != tt.wantStep) {
+				t.Errorf("Validate() = %d, %v, want %d, %v", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestTOTPProvider_RecoveryCodes(t *testing.T) {
+	provider := NewTOTPProvider("APPFE Lima")
+
+	codes, err := provider.GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes() error = %v", err)
+	}
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if len(code) != 9 || code[4] != '-' {
+			t.Errorf("unexpected recovery code format %q", code)
+		}
+		seen[code] = true
+	}
+	if len(seen) != len(codes) {
+		t.Error("recovery codes must be unique")
+	}
+
+	if got := provider.NormalizeRecoveryCode(" " + strings.ToUpper(codes[0]) + " "); got != strings.ReplaceAll(codes[0], "-", "") {
+		t.Errorf("NormalizeRecoveryCode() = %q", got)
+	}
+}
+
+func TestTOTPProvider_ProvisioningURI(t *testing.T) {
+	provider := NewTOTPProvider("APPFE Lima")
+
+	uri := provider.ProvisioningURI("JBSWY3DPEHPK3PXP", "admin@appfe.com")
+	if !strings.HasPrefix(uri, "otpauth://totp/APPFE%20Lima:admin@appfe.com?") {
+		t.Errorf("unexpected URI %q", uri)
+	}
+	if !strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP") || !strings.Contains(uri, "issuer=APPFE+Lima") {
+		t.Errorf("URI missing parameters: %q", uri)
+	}
+}
@@ -13,14 +13,14 @@ func NewHTMLTemplateService() interfaces.TemplateService {
 	return &htmlTemplateService{}
 }
 
-func (t *htmlTemplateService) RenderWelcomeEmail(userName, password string) (string, error) {
-	if userName == "" || password == "" {
-		return "", fmt.Errorf(dto.ErrTemplateRenderFailed, fmt.Errorf("userName and password are required"))
+func (t *htmlTemplateService) RenderWelcomeEmail(userName, invitationLink, expiresAt string) (string, error) {
+	if userName == "" || invitationLink == "" || expiresAt == "" {
+		return "", fmt.Errorf(dto.ErrTemplateRenderFailed, fmt.Errorf("userName, invitationLink and expiresAt are required"))
 	}
 
 	title := "Bienvenido a APPFE Lima"
 	header := "Bienvenido(a) a APPFE Lima"
-	content := fmt.Sprintf(welcomeContentTemplate, userName, password)
+	content := fmt.Sprintf(welcomeContentTemplate, userName, invitationLink, expiresAt)
 
 	return fmt.Sprintf(baseHTMLTemplate, title, header, content), nil
 }
@@ -48,3 +48,27 @@ func (t *htmlTemplateService) RenderEmailValidation(userName, validationLink str
 
 	return fmt.Sprintf(baseHTMLTemplate, title, header, content), nil
 }
+
+func (t *htmlTemplateService) RenderAccountLockedEmail(userName, lockedUntil string) (string, error) {
+	if userName == "" || lockedUntil == "" {
+		return "", fmt.Errorf(dto.ErrTemplateRenderFailed, fmt.Errorf("userName and lockedUntil are required"))
+	}
+
+	title := "Cuenta Bloqueada - APPFE Lima"
+	header := "Cuenta Bloqueada Temporalmente"
+	content := fmt.Sprintf(accountLockedContentTemplate, userName, lockedUntil)
+
+	return fmt.Sprintf(baseHTMLTemplate, title, header, content), nil
+}
+
+func (t *htmlTemplateService) RenderMagicLinkEmail(userName, loginLink string) (string, error) {
+	if userName == "" || loginLink == "" {
+		return "", fmt.Errorf(dto.ErrTemplateRenderFailed, fmt.Errorf("userName and loginLink are required"))
+	}
+
+	title := "Iniciar Sesión - APPFE Lima"
+	header := "Tu Enlace de Acceso"
+	content := fmt.Sprintf(magicLinkContentTemplate, userName, loginLink)
+
+	return fmt.Sprintf(baseHTMLTemplate, title, header, content), nil
+}
@@ -9,40 +9,45 @@ func TestHTMLTemplateService_RenderWelcomeEmail(t *testing.T) {
 	service := NewHTMLTemplateService()
 
 	tests := []struct {
-		name     string
-		userName string
-		password string
-		wantErr  bool
+		name           string
+		userName       string
+		invitationLink string
+		expiresAt      string
+		wantErr        bool
 	}{
 		{
-			name:     "valid parameters",
-			userName: "John Doe",
-			password: "tempPassword123",
-			wantErr:  false,
+			name:           "valid parameters",
+			userName:       "John Doe",
+			invitationLink: "https://example.com/accept-invitation?token=abc123",
+			expiresAt:      "20/10/2026 10:00",
+			wantErr:        false,
 		},
 		{
-			name:     "empty userName",
-			userName: "",
-			password: "tempPassword123",
-			wantErr:  true,
+			name:           "empty userName",
+			userName:       "",
+			invitationLink: "https://example.com/accept-invitation?token=abc123",
+			expiresAt:      "20/10/2026 10:00",
+			wantErr:        true,
 		},
 		{
-			name:     "empty password",
-			userName: "John Doe",
-			password: "",
-			wantErr:  true,
+			name:           "empty invitationLink",
+			userName:       "John Doe",
+			invitationLink: "",
+			expiresAt:      "20/10/2026 10:00",
+			wantErr:        true,
 		},
 		{
-			name:     "both empty",
-			userName: "",
-			password: "",
-			wantErr:  true,
+			name:           "empty expiresAt",
+			userName:       "John Doe",
+			invitationLink: "https://example.com/accept-invitation?token=abc123",
+			expiresAt:      "",
+			wantErr:        true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := service.RenderWelcomeEmail(tt.userName, tt.password)
+			result, err := service.RenderWelcomeEmail(tt.userName, tt.invitationLink, tt.expiresAt)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("RenderWelcomeEmail() error = %v, wantErr %v", err, tt.wantErr)
@@ -57,8 +62,11 @@ func TestHTMLTemplateService_RenderWelcomeEmail(t *testing.T) {
 				if !strings.Contains(result, tt.userName) {
 					t.Error("Expected userName to be in template")
 				}
-				if !strings.Contains(result, tt.password) {
-					t.Error("Expected password to be in template")
+				if !strings.Contains(result, tt.invitationLink) {
+					t.Error("Expected invitationLink to be in template")
+				}
+				if !strings.Contains(result, tt.expiresAt) {
+					t.Error("Expected expiresAt to be in template")
 				}
 				if !strings.Contains(result, "APPFE Lima") {
 					t.Error("Expected company name in template")
@@ -179,3 +187,115 @@ func TestHTMLTemplateService_RenderEmailValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestHTMLTemplateService_RenderAccountLockedEmail(t *testing.T) {
+	service := NewHTMLTemplateService()
+
+	tests := []struct {
+		name        string
+		userName    string
+		lockedUntil string
+		wantErr     bool
+	}{
+		{
+			name:        "valid parameters",
+			userName:    "Jane Doe",
+			lockedUntil: "17/10/2026 15:04",
+			wantErr:     false,
+		},
+		{
+			name:        "empty userName",
+			userName:    "",
+			lockedUntil: "17/10/2026 15:04",
+			wantErr:     true,
+		},
+		{
+			name:        "empty lockedUntil",
+			userName:    "Jane Doe",
+			lockedUntil: "",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.RenderAccountLockedEmail(tt.userName, tt.lockedUntil)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RenderAccountLockedEmail() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr {
+				if !strings.Contains(result, "DOCTYPE html") {
+					t.Error("Expected HTML5 doctype")
+				}
+				if !strings.Contains(result, tt.userName) {
+					t.Error("Expected userName to be in template")
+				}
+				if !strings.Contains(result, tt.lockedUntil) {
+					t.Error("Expected lockedUntil to be in template")
+				}
+				if !strings.Contains(result, "Cuenta Bloqueada") {
+					t.Error("Expected account locked text in template")
+				}
+			}
+		})
+	}
+}
+
+func TestHTMLTemplateService_RenderMagicLinkEmail(t *testing.T) {
+	service := NewHTMLTemplateService()
+
+	tests := []struct {
+		name      string
+		userName  string
+		loginLink string
+		wantErr   bool
+	}{
+		{
+			name:      "valid parameters",
+			userName:  "Jane Doe",
+			loginLink: "https://appfe.com/magic-link?token=abc123",
+			wantErr:   false,
+		},
+		{
+			name:      "empty userName",
+			userName:  "",
+			loginLink: "https://appfe.com/magic-link?token=abc123",
+			wantErr:   true,
+		},
+		{
+			name:      "empty loginLink",
+			userName:  "Jane Doe",
+			loginLink: "",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.RenderMagicLinkEmail(tt.userName, tt.loginLink)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RenderMagicLinkEmail() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr {
+				if !strings.Contains(result, "DOCTYPE html") {
+					t.Error("Expected HTML5 doctype")
+				}
+				if !strings.Contains(result, tt.userName) {
+					t.Error("Expected userName to be in template")
+				}
+				if !strings.Contains(result, tt.loginLink) {
+					t.Error("Expected loginLink to be in template")
+				}
+				if !strings.Contains(result, "Iniciar Sesión") {
+					t.Error("Expected sign in text in template")
+				}
+			}
+		})
+	}
+}
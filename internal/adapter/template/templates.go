@@ -61,14 +61,15 @@ const (
 		<div class="message">
 			¡Hola %s! <br><br>
 			
-			Te damos la bienvenida al portal administrativo de APPFE Lima. Tu cuenta ha sido creada exitosamente y ya puedes comenzar a usar nuestra plataforma.
+			Te damos la bienvenida al portal administrativo de APPFE Lima. Tu cuenta ha sido creada; para comenzar a usar la plataforma, establece tu contraseña desde el siguiente enlace:
 			<br><br>
 			
 			<div class="highlight-box">
-				<div class="highlight-label">Tu contraseña de acceso:</div>
-				<div class="highlight-value">%s</div>
+				<a href="%s" class="button">Establecer Contraseña</a>
 			</div>
 			
+			Este enlace es de un solo uso y expirará el %s.<br><br>
+			
 			Si tienes alguna pregunta, no dudes en contactarnos.
 		</div>`
 
@@ -105,4 +106,37 @@ const (
 			
 			Si no te registraste en nuestra plataforma, puedes ignorar este correo.
 		</div>`
+
+	// Template para aviso de bloqueo por intentos fallidos
+	accountLockedContentTemplate = `
+		<div class="message">
+			Hola %s, <br><br>
+			
+			Detectamos varios intentos fallidos de inicio de sesión en tu cuenta, por lo que la bloqueamos temporalmente por seguridad.
+			<br><br>
+			
+			<div class="highlight-box">
+				<div class="highlight-label">Podrás volver a intentarlo después de:</div>
+				<div class="highlight-value">%s</div>
+			</div>
+			
+			Si no fuiste tú, te recomendamos restablecer tu contraseña o contactar a un administrador.
+		</div>`
+
+	// Template para inicio de sesión sin contraseña
+	magicLinkContentTemplate = `
+		<div class="message">
+			Hola %s, <br><br>
+			
+			Recibimos una solicitud para iniciar sesión en tu cuenta de APPFE Lima. Haz clic en el siguiente enlace para ingresar:
+			<br><br>
+			
+			<div class="highlight-box">
+				<a href="%s" class="button">Iniciar Sesión</a>
+			</div>
+			
+			Este enlace es de un solo uso y expirará en 15 minutos.<br><br>
+			
+			Si no solicitaste este acceso, puedes ignorar este correo.
+		</div>`
 )
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// APIKeyPrefix identifica las claves de la API; el resto de la clave es un token aleatorio
+	APIKeyPrefix = "appfe_"
+
+	// APIKeyDisplayLength es la cantidad de caracteres de la clave que se guardan en claro para reconocerla
+	APIKeyDisplayLength = len(APIKeyPrefix) + 8
+
+	// APIKeyLastUsedInterval limita la frecuencia con que se actualiza LastUsedAt para no escribir en cada petición
+	APIKeyLastUsedInterval = time.Minute
+)
+
+// APIKey es una credencial para clientes máquina a máquina. Actúa en nombre del administrador que la creó
+// y solo con los permisos listados en Scopes. Solo se almacena el hash; la clave completa se muestra una vez.
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  string     `json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && now.After(*k.ExpiresAt)
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// IsValidAPIKeyScope indica si un permiso puede delegarse en una API key. La gestión de claves y la
+// suplantación quedan reservadas a sesiones de usuario para que una clave no pueda emitir otras credenciales.
+func IsValidAPIKeyScope(scope string) bool {
+	return IsValidPermission(scope) && scope != PermissionAPIKeysManage && scope != PermissionUsersImpersonate
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Tipos de evento del registro de auditoría
+const (
+	AuditLoginSucceeded       = "auth.login.succeeded"
+	AuditLoginFailed          = "auth.login.failed"
+	AuditTokenSignIn          = "auth.token_sign_in"
+	AuditPasswordChanged      = "user.password.changed"
+	AuditPasswordReset        = "user.password.reset"
+	AuditInvitationAccepted   = "user.invitation.accepted"
+	AuditPasswordAdminSet     = "user.password.admin_set"
+	AuditPasswordAdminReset   = "user.password.admin_reset"
+	AuditUserRoleChanged      = "user.role.changed"
+	AuditUserDeleted          = "user.deleted"
+	AuditImpersonationStarted = "user.impersonation.started"
+	AuditUserProvisioned      = "user.provisioned"
+	AuditOIDCLinked           = "user.oidc.linked"
+	AuditSessionTerminated    = "user.session.terminated"
+	AuditRoleCreated          = "role.created"
+	AuditRoleUpdated          = "role.updated"
+	AuditRoleDeleted          = "role.deleted"
+	AuditAPIKeyCreated        = "api_key.created"
+	AuditAPIKeyRevoked        = "api_key.revoked"
+
+	AuditTargetUser   = "user"
+	AuditTargetRole   = "role"
+	AuditTargetAPIKey = "api_key"
+
+	// Claves de AuditEvent.Details
+	AuditDetailReason           = "reason"
+	AuditDetailEmail            = "email"
+	AuditDetailPreviousRole     = "previous_role"
+	AuditDetailNewRole          = "new_role"
+	AuditDetailMethod           = "method"
+	AuditDetailPermissions      = "permissions"
+	AuditDetailTwoFactor        = "two_factor"
+	AuditDetailPasswordChange   = "password_change_required"
+	AuditDetailImpersonatedUser = "impersonated_user_id"
+	AuditDetailSubject          = "subject"
+	AuditDetailIssuer           = "issuer"
+	AuditDetailAPIKey           = "api_key_id"
+	AuditDetailScopes           = "scopes"
+	AuditDetailSession          = "session_id"
+)
+
+// AuditEvent es una entrada del registro de auditoría. ActorID es quien ejecuta la acción (nil en
+// peticiones anónimas) y TargetID el recurso afectado, de tipo TargetType.
+type AuditEvent struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	ActorID    *string        `json:"actor_id,omitempty"`
+	TargetType string         `json:"target_type,omitempty"`
+	TargetID   *string        `json:"target_id,omitempty"`
+	IP         string         `json:"ip"`
+	UserAgent  string         `json:"user_agent"`
+	RequestID  string         `json:"request_id"`
+	Details    map[string]any `json:"details,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// AuditFilter restringe el listado de eventos; los campos vacíos no filtran
+type AuditFilter struct {
+	Type     string
+	ActorID  string
+	TargetID string
+	From     *time.Time
+	To       *time.Time
+}
+
+// RequestMetadata identifica la petición HTTP que origina un evento de auditoría
+type RequestMetadata struct {
+	IP        string
+	UserAgent string
+	RequestID string
+	ActorID   string
+
+	// ImpersonatedUserID es el usuario suplantado por ActorID; vacío si la sesión no es una suplantación
+	ImpersonatedUserID string
+
+	// APIKeyID es la API key con la que se autenticó la petición; ActorID es su dueño
+	APIKeyID string
+}
+
+type requestMetadataKey struct{}
+
+// WithRequestMetadata guarda los datos de la petición en el contexto que reciben los servicios
+func WithRequestMetadata(ctx context.Context, meta RequestMetadata) context.Context {
+	return context.WithValue(ctx, requestMetadataKey{}, meta)
+}
+
+// RequestMetadataFromContext retorna los datos de la petición; vacío fuera de una petición HTTP
+func RequestMetadataFromContext(ctx context.Context) RequestMetadata {
+	meta, _ := ctx.Value(requestMetadataKey{}).(RequestMetadata)
+	return meta
+}
+
+// WithActor agrega al contexto el usuario autenticado que ejecuta la petición
+func WithActor(ctx context.Context, userID string) context.Context {
+	meta := RequestMetadataFromContext(ctx)
+	meta.ActorID = userID
+	return WithRequestMetadata(ctx, meta)
+}
+
+// WithImpersonation registra que actorID ejecuta la petición en nombre de userID
+func WithImpersonation(ctx context.Context, actorID, userID string) context.Context {
+	meta := RequestMetadataFromContext(ctx)
+	meta.ActorID = actorID
+	meta.ImpersonatedUserID = userID
+	return WithRequestMetadata(ctx, meta)
+}
+
+// WithAPIKey registra que la petición se autenticó con la API key keyID de ownerID
+func WithAPIKey(ctx context.Context, ownerID, keyID string) context.Context {
+	meta := RequestMetadataFromContext(ctx)
+	meta.ActorID = ownerID
+	meta.APIKeyID = keyID
+	return WithRequestMetadata(ctx, meta)
+}
+
+// UserID es el dueño de la sesión: el usuario suplantado o, si no hay suplantación, el actor
+func (m RequestMetadata) UserID() string {
+	if m.ImpersonatedUserID != "" {
+		return m.ImpersonatedUserID
+	}
+	return m.ActorID
+}
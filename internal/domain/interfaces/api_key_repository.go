@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+type APIKeyRepository interface {
+	Migrate(ctx context.Context) error
+	Create(ctx context.Context, key *domain.APIKey) error
+	FindByID(ctx context.Context, id string) (*domain.APIKey, error)
+	FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	ListByOwner(ctx context.Context, ownerID string) ([]*domain.APIKey, error)
+	Revoke(ctx context.Context, id string, at time.Time) error
+
+	// TouchLastUsed registra el uso de la clave si el último registrado es anterior a since
+	TouchLastUsed(ctx context.Context, id string, now, since time.Time) error
+}
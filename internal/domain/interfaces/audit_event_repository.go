@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+type AuditEventRepository interface {
+	Migrate(ctx context.Context) error
+	Create(ctx context.Context, event *domain.AuditEvent) error
+	// List retorna los eventos más recientes primero junto con el total que cumple el filtro
+	List(ctx context.Context, filter domain.AuditFilter, pagination *domain.Pagination) ([]*domain.AuditEvent, int64, error)
+}
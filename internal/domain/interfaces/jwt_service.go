@@ -3,6 +3,11 @@ package interfaces
 import "github.com/JacobD36/appfe_frontpage_api/internal/domain"
 
 type JWTService interface {
-	GenerateToken(user domain.User) (string, error)
-	ValidateToken(tokenString string) (*domain.User, error)
+	GenerateToken(user domain.User, opts domain.TokenOptions) (string, error)
+	ValidateToken(tokenString string) (*domain.TokenClaims, error)
+}
+
+// JWKSProvider expone las claves públicas con las que otros servicios pueden verificar nuestros tokens
+type JWKSProvider interface {
+	PublicJWKs() []domain.JSONWebKey
 }
@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+type LoginAttemptRepository interface {
+	Migrate(ctx context.Context) error
+	Create(ctx context.Context, attempt *domain.LoginAttempt) error
+	CountFailedByIP(ctx context.Context, ip string, since time.Time) (int, error)
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
+
+type AccountLockoutRepository interface {
+	Migrate(ctx context.Context) error
+	// Get retorna un bloqueo vacío si la cuenta no tiene registro
+	Get(ctx context.Context, userID string) (*domain.AccountLockout, error)
+	Save(ctx context.Context, lockout *domain.AccountLockout) error
+	Reset(ctx context.Context, userID string) error
+}
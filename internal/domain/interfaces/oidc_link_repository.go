@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+type OIDCLinkRepository interface {
+	Migrate(ctx context.Context) error
+	Create(ctx context.Context, link *domain.OIDCLink) error
+
+	// FindBySubject retorna la cuenta asociada a la identidad (issuer, subject)
+	FindBySubject(ctx context.Context, issuer, subject string) (*domain.OIDCLink, error)
+
+	// FindByUser retorna la identidad del proveedor issuer asociada a la cuenta
+	FindByUser(ctx context.Context, userID, issuer string) (*domain.OIDCLink, error)
+}
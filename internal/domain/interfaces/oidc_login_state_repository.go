@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+type OIDCLoginStateRepository interface {
+	Migrate(ctx context.Context) error
+	Create(ctx context.Context, state *domain.OIDCLoginState) error
+
+	// Consume elimina y retorna el estado con ese hash, de modo que cada state solo se acepta una vez
+	Consume(ctx context.Context, stateHash string) (*domain.OIDCLoginState, error)
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
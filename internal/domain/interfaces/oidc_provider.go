@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+// OIDCProvider implementa el flujo authorization code + PKCE contra un proveedor OpenID Connect
+type OIDCProvider interface {
+	// AuthorizationURL construye la URL del proveedor a la que se redirige al usuario
+	AuthorizationURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+
+	// Exchange canjea el código de autorización y retorna la identidad del ID token ya verificado
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.OIDCIdentity, error)
+}
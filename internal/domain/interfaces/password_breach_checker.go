@@ -0,0 +1,7 @@
+package interfaces
+
+// PasswordBreachChecker consulta un corpus de contraseñas filtradas en brechas de datos conocidas
+type PasswordBreachChecker interface {
+	// IsBreached indica si la contraseña aparece en el corpus
+	IsBreached(password string) (bool, error)
+}
@@ -3,4 +3,6 @@ package interfaces
 type PasswordHasher interface {
 	Hash(password string) (string, error)
 	Verify(hashedPassword, password string) error
+	// NeedsRehash indica si el hash usa un algoritmo o costo anterior y debe regenerarse
+	NeedsRehash(hashedPassword string) bool
 }
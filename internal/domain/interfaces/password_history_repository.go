@@ -0,0 +1,13 @@
+package interfaces
+
+import "context"
+
+// PasswordHistoryRepository guarda los hashes de contraseñas anteriores para impedir su reutilización
+type PasswordHistoryRepository interface {
+	Migrate(ctx context.Context) error
+	Add(ctx context.Context, userID, passwordHash string) error
+	// ListRecent retorna los hashes más recientes primero
+	ListRecent(ctx context.Context, userID string, limit int) ([]string, error)
+	// Prune conserva solo los keep hashes más recientes del usuario
+	Prune(ctx context.Context, userID string, keep int) error
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+type RefreshTokenRepository interface {
+	Migrate(ctx context.Context) error
+	Create(ctx context.Context, token *domain.RefreshToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	MarkUsed(ctx context.Context, id string) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeByUser(ctx context.Context, userID string) error
+}
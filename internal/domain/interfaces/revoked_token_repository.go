@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+type RevokedTokenRepository interface {
+	Migrate(ctx context.Context) error
+	Create(ctx context.Context, token *domain.RevokedToken) error
+	ListActive(ctx context.Context, now time.Time) ([]*domain.RevokedToken, error)
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+type RoleRepository interface {
+	// Migrate crea las tablas y siembra los roles de sistema; ADMIN_ROLE recibe siempre todos los permisos
+	Migrate(ctx context.Context) error
+	GetAll(ctx context.Context) ([]*domain.Role, error)
+	GetByName(ctx context.Context, name string) (*domain.Role, error)
+	Create(ctx context.Context, role *domain.Role) error
+	// Update reemplaza la descripción y el conjunto completo de permisos
+	Update(ctx context.Context, role *domain.Role) error
+	Delete(ctx context.Context, name string) error
+	GetPermissions(ctx context.Context, name string) ([]string, error)
+	CountUsers(ctx context.Context, name string) (int64, error)
+}
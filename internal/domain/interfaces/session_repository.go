@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+type SessionRepository interface {
+	Migrate(ctx context.Context) error
+	Create(ctx context.Context, session *domain.Session) error
+	FindByID(ctx context.Context, id string) (*domain.Session, error)
+	// ListActiveByUser retorna las sesiones no terminadas ni vencidas, de la más reciente a la más antigua
+	ListActiveByUser(ctx context.Context, userID string, now time.Time) ([]*domain.Session, error)
+
+	// Touch registra actividad en la sesión e indica si sigue activa
+	Touch(ctx context.Context, id string, now time.Time) (bool, error)
+
+	// Extend renueva la vigencia de una sesión activa al rotar su refresh token; false si no existe
+	Extend(ctx context.Context, id string, expiresAt, now time.Time) (bool, error)
+	Revoke(ctx context.Context, id string, now time.Time) error
+	RevokeByUser(ctx context.Context, userID string, now time.Time) error
+}
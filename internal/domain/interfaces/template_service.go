@@ -2,12 +2,18 @@ package interfaces
 
 // TemplateService define la interfaz para el servicio de plantillas
 type TemplateService interface {
-	// RenderWelcomeEmail renderiza la plantilla de email de bienvenida
-	RenderWelcomeEmail(userName, password string) (string, error)
+	// RenderWelcomeEmail renderiza la plantilla de bienvenida con el enlace para establecer la contraseña
+	RenderWelcomeEmail(userName, invitationLink, expiresAt string) (string, error)
 
 	// RenderPasswordResetEmail renderiza la plantilla de email para reset de contraseña
 	RenderPasswordResetEmail(userName, resetLink string) (string, error)
 
 	// RenderEmailValidation renderiza la plantilla de email para validación
 	RenderEmailValidation(userName, validationLink string) (string, error)
+
+	// RenderAccountLockedEmail renderiza la plantilla de aviso de bloqueo de cuenta
+	RenderAccountLockedEmail(userName, lockedUntil string) (string, error)
+
+	// RenderMagicLinkEmail renderiza la plantilla con el enlace de inicio de sesión sin contraseña
+	RenderMagicLinkEmail(userName, loginLink string) (string, error)
 }
@@ -0,0 +1,10 @@
+package interfaces
+
+// TokenGenerator genera tokens opacos aleatorios y calcula el hash con el que se persisten
+type TokenGenerator interface {
+	// Generate retorna el token en texto plano y su hash
+	Generate() (token string, hash string, err error)
+
+	// Hash calcula el hash de un token recibido del cliente
+	Hash(token string) string
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import "time"
+
+// TwoFactorProvider genera y valida códigos TOTP (RFC 6238) y códigos de recuperación
+type TwoFactorProvider interface {
+	GenerateSecret() (string, error)
+
+	// ProvisioningURI construye el URI otpauth:// que las apps autenticadoras leen desde un código QR
+	ProvisioningURI(secret, accountName string) string
+
+	// Validate retorna el paso de tiempo que coincide con el código, tolerando un paso de desfase
+	Validate(secret, code string, now time.Time) (int64, bool)
+
+	GenerateRecoveryCodes(count int) ([]string, error)
+
+	// NormalizeRecoveryCode unifica el formato ingresado por el usuario antes de hashearlo
+	NormalizeRecoveryCode(code string) string
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+type TwoFactorRepository interface {
+	Migrate(ctx context.Context) error
+	Get(ctx context.Context, userID string) (*domain.TwoFactor, error)
+	Save(ctx context.Context, twoFactor *domain.TwoFactor) error
+	// Delete elimina la configuración TOTP y los códigos de recuperación del usuario
+	Delete(ctx context.Context, userID string) error
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+	// UseRecoveryCode marca como usado un código pendiente; retorna false si no existe o ya se usó
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+}
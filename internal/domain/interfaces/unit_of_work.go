@@ -6,6 +6,19 @@ type UnitOfWork interface {
 	Commit() error
 	Rollback() error
 	UserRepository() UserRepository
+	UserTokenRepository() UserTokenRepository
+	RefreshTokenRepository() RefreshTokenRepository
+	RevokedTokenRepository() RevokedTokenRepository
+	LoginAttemptRepository() LoginAttemptRepository
+	AccountLockoutRepository() AccountLockoutRepository
+	TwoFactorRepository() TwoFactorRepository
+	RoleRepository() RoleRepository
+	PasswordHistoryRepository() PasswordHistoryRepository
+	AuditEventRepository() AuditEventRepository
+	OIDCLoginStateRepository() OIDCLoginStateRepository
+	OIDCLinkRepository() OIDCLinkRepository
+	APIKeyRepository() APIKeyRepository
+	SessionRepository() SessionRepository
 }
 
 type UnitOfWorkFactory interface {
@@ -10,8 +10,12 @@ type UserRepository interface {
 	Migrate(ctx context.Context) error
 	Create(ctx context.Context, user *domain.User) error
 	UpdateByID(ctx context.Context, input UpdateUserInput) error
-	GetAll(ctx context.Context, pagination *domain.Pagination) ([]*domain.User, int64, error)
+	// GetAll retorna el total de usuarios que cumplen el filtro, salvo cuando pagination trae un cursor: esas
+	// páginas no cuentan filas y el total es 0
+	GetAll(ctx context.Context, filter domain.UserFilter, pagination *domain.Pagination) ([]*domain.User, int64, error)
 	GetByID(ctx context.Context, id string) (*domain.User, error)
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
 	Delete(ctx context.Context, id string) error
+	GetTokenVersion(ctx context.Context, id string) (int, error)
+	UpdatePasswordHash(ctx context.Context, id, hash string) error
 }
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+type UserTokenRepository interface {
+	Migrate(ctx context.Context) error
+	Create(ctx context.Context, token *domain.UserToken) error
+	FindByHash(ctx context.Context, purpose, tokenHash string) (*domain.UserToken, error)
+	MarkUsed(ctx context.Context, id string) error
+	InvalidateByUser(ctx context.Context, userID, purpose string) error
+	CountCreatedSince(ctx context.Context, userID, purpose string, since time.Time) (int, error)
+}
@@ -0,0 +1,15 @@
+package domain
+
+// JSONWebKey representa una clave pública de verificación en formato JWK (RFC 7517).
+// N y E corresponden a claves RSA; Crv, X e Y a claves EC, usadas por algunos proveedores OIDC.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
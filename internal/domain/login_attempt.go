@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// LoginAttempt registra cada intento de inicio de sesión. UserID es nil cuando el correo no existe.
+type LoginAttempt struct {
+	ID        string    `json:"id"`
+	UserID    *string   `json:"user_id,omitempty"`
+	Email     string    `json:"email"`
+	IP        string    `json:"ip"`
+	Success   bool      `json:"success"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AccountLockout guarda el estado de bloqueo de una cuenta. LockoutCount cuenta los bloqueos
+// consecutivos y determina la duración exponencial del siguiente.
+type AccountLockout struct {
+	UserID       string     `json:"user_id"`
+	FailedCount  int        `json:"failed_count"`
+	LockoutCount int        `json:"lockout_count"`
+	LockedUntil  *time.Time `json:"locked_until,omitempty"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (l *AccountLockout) IsLocked(now time.Time) bool {
+	return l.LockedUntil != nil && now.Before(*l.LockedUntil)
+}
+
+// IsClean indica que no hay fallos ni bloqueos que reiniciar
+func (l *AccountLockout) IsClean() bool {
+	return l.FailedCount == 0 && l.LockoutCount == 0 && l.LockedUntil == nil
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// OIDCLoginStateTTL es el tiempo para volver del proveedor de identidad con el código de autorización
+const OIDCLoginStateTTL = 10 * time.Minute
+
+// OIDCLoginState guarda los datos de un inicio de sesión OIDC en curso. El state viaja al proveedor y
+// vuelve en el callback; solo se almacena su hash. Nonce y CodeVerifier no salen del servidor salvo
+// dentro de la petición al proveedor.
+type OIDCLoginState struct {
+	StateHash    string    `json:"-"`
+	Nonce        string    `json:"-"`
+	CodeVerifier string    `json:"-"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (s *OIDCLoginState) IsExpired(now time.Time) bool {
+	return now.After(s.ExpiresAt)
+}
+
+// OIDCIdentity es la identidad verificada a partir del ID token del proveedor
+type OIDCIdentity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Origen de un OIDCLink: creado junto con la cuenta, por coincidencia de correo al iniciar sesión, o
+// pedido por el usuario desde una sesión ya iniciada
+const (
+	OIDCLinkProvisioned = "provisioned"
+	OIDCLinkEmail       = "email"
+	OIDCLinkSession     = "session"
+)
+
+// OIDCLink asocia una cuenta con una identidad del proveedor. El par (Issuer, Subject) es el identificador
+// estable del usuario en el proveedor; el correo puede cambiar o reasignarse y no basta para reconocerlo.
+type OIDCLink struct {
+	UserID    string    `json:"user_id"`
+	Issuer    string    `json:"issuer"`
+	Subject   string    `json:"subject"`
+	Method    string    `json:"method"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsExplicit indica que el propio usuario vinculó la identidad desde una sesión iniciada
+func (l *OIDCLink) IsExplicit() bool {
+	return l.Method == OIDCLinkSession
+}
@@ -1,17 +1,28 @@
 package domain
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"slices"
 	"strconv"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 const (
 	ErrInvalidPaginationPage  = "el número de página debe ser mayor a 0"
 	ErrInvalidPaginationLimit = "el límite debe estar entre 10 y 100"
+	ErrInvalidCursor          = "cursor inválido, usa el valor next_cursor o prev_cursor de la respuesta anterior"
+	ErrCursorWithPage         = "page no puede combinarse con la paginación por cursor"
 	DefaultPage               = 1
 	DefaultLimit              = 10
 	MaxLimit                  = 100
 	MinLimit                  = 10
+
+	// PaginationModeCursor es el valor del parámetro pagination que pide la primera página en modo cursor
+	PaginationModeCursor = "cursor"
 )
 
 type Pagination struct {
@@ -19,6 +30,18 @@ type Pagination struct {
 	Limit  int    `json:"limit" validate:"min=10,max=100"`
 	Search string `json:"search,omitempty"`
 	Offset int    `json:"-"`
+
+	// CursorMode pagina por clave (created_at, id) en lugar de OFFSET; Cursor es nil en la primera página
+	CursorMode bool    `json:"-"`
+	Cursor     *Cursor `json:"-"`
+}
+
+// Cursor es la posición de la última fila vista en la paginación por clave. Se entrega al cliente como
+// un valor opaco; Backward indica que la página pedida es la anterior a esa posición.
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+	Backward  bool      `json:"b,omitempty"`
 }
 
 type PaginatedResult[T any] struct {
@@ -26,15 +49,20 @@ type PaginatedResult[T any] struct {
 	Pagination *PaginationInfo `json:"pagination"`
 }
 
+// PaginationInfo describe la página devuelta. Total y TotalPages faltan en las páginas de modo cursor
+// posteriores a la primera, que no cuentan filas.
 type PaginationInfo struct {
-	Page         int   `json:"page"`
-	Limit        int   `json:"limit"`
-	Total        int64 `json:"total"`
-	TotalPages   int   `json:"total_pages"`
-	HasPrevious  bool  `json:"has_previous"`
-	HasNext      bool  `json:"has_next"`
-	PreviousPage *int  `json:"previous_page,omitempty"`
-	NextPage     *int  `json:"next_page,omitempty"`
+	Page         int    `json:"page,omitempty"`
+	Limit        int    `json:"limit"`
+	Total        *int64 `json:"total,omitempty"`
+	TotalPages   *int   `json:"total_pages,omitempty"`
+	HasPrevious  bool   `json:"has_previous"`
+	HasNext      bool   `json:"has_next"`
+	PreviousPage *int   `json:"previous_page,omitempty"`
+	NextPage     *int   `json:"next_page,omitempty"`
+
+	NextCursor *string `json:"next_cursor,omitempty"`
+	PrevCursor *string `json:"prev_cursor,omitempty"`
 }
 
 func NewPagination(page, limit int, search string) *Pagination {
@@ -86,8 +114,8 @@ func NewPaginatedResult[T any](data []T, pagination *Pagination, total int64) *P
 	info := &PaginationInfo{
 		Page:        pagination.Page,
 		Limit:       pagination.Limit,
-		Total:       total,
-		TotalPages:  totalPages,
+		Total:       &total,
+		TotalPages:  &totalPages,
 		HasPrevious: pagination.Page > 1,
 		HasNext:     pagination.Page < totalPages,
 	}
@@ -108,6 +136,90 @@ func NewPaginatedResult[T any](data []T, pagination *Pagination, total int64) *P
 	}
 }
 
+// FetchLimit es la cantidad de filas a pedir en modo cursor: una más que Limit para saber si hay otra página
+func (p *Pagination) FetchLimit() int {
+	return p.Limit + 1
+}
+
+// Encode devuelve el cursor como texto opaco apto para query strings
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor interpreta un cursor generado por Encode; el ID debe ser un UUID para que un cursor
+// manipulado se rechace con 400 en lugar de llegar como parámetro inválido a la consulta
+func DecodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New(ErrInvalidCursor)
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.CreatedAt.IsZero() {
+		return nil, errors.New(ErrInvalidCursor)
+	}
+
+	if _, err := uuid.Parse(cursor.ID); err != nil {
+		return nil, errors.New(ErrInvalidCursor)
+	}
+
+	return &cursor, nil
+}
+
+// NewCursorPaginatedResult arma una página en modo cursor. data son las filas devueltas por el repositorio:
+// hasta FetchLimit filas en el sentido del recorrido, que al retroceder es el inverso del orden pedido.
+// cursorOf extrae la posición de una fila para generar next_cursor y prev_cursor. total solo se informa en la
+// primera página: contar todas las filas en cada página anularía la ventaja del modo cursor.
+func NewCursorPaginatedResult[T any](data []T, pagination *Pagination, total int64, cursorOf func(T) Cursor) *PaginatedResult[T] {
+	hasMore := len(data) > pagination.Limit
+	if hasMore {
+		data = data[:pagination.Limit]
+	}
+
+	backward := pagination.Cursor != nil && pagination.Cursor.Backward
+	if backward {
+		slices.Reverse(data)
+	}
+
+	info := &PaginationInfo{Limit: pagination.Limit}
+
+	if pagination.Cursor == nil {
+		totalPages := int((total + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+		info.Total, info.TotalPages = &total, &totalPages
+	}
+
+	if backward {
+		info.HasPrevious = hasMore
+		info.HasNext = true
+	} else {
+		info.HasPrevious = pagination.Cursor != nil
+		info.HasNext = hasMore
+	}
+
+	// Una página vacía conserva la posición del cursor recibido para poder volver sobre sus pasos
+	first, last := pagination.Cursor, pagination.Cursor
+	if len(data) > 0 {
+		firstCursor, lastCursor := cursorOf(data[0]), cursorOf(data[len(data)-1])
+		first, last = &firstCursor, &lastCursor
+	}
+
+	if info.HasNext && last != nil {
+		next := Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+		info.NextCursor = &next
+	}
+
+	if info.HasPrevious && first != nil {
+		prev := Cursor{CreatedAt: first.CreatedAt, ID: first.ID, Backward: true}.Encode()
+		info.PrevCursor = &prev
+	}
+
+	return &PaginatedResult[T]{
+		Data:       data,
+		Pagination: info,
+	}
+}
+
 func ParsePaginationFromQuery(pageStr, limitStr, searchStr string) (*Pagination, error) {
 	var page, limit int
 	var err error
@@ -134,3 +246,21 @@ func ParsePaginationFromQuery(pageStr, limitStr, searchStr string) (*Pagination,
 
 	return pagination, nil
 }
+
+// ParseCursorPaginationFromQuery crea una paginación en modo cursor; cursorStr vacío pide la primera página
+func ParseCursorPaginationFromQuery(limitStr, cursorStr, searchStr string) (*Pagination, error) {
+	pagination, err := ParsePaginationFromQuery("", limitStr, searchStr)
+	if err != nil {
+		return nil, err
+	}
+
+	pagination.CursorMode = true
+
+	if cursorStr != "" {
+		if pagination.Cursor, err = DecodeCursor(cursorStr); err != nil {
+			return nil, err
+		}
+	}
+
+	return pagination, nil
+}
@@ -0,0 +1,206 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	testCursorID1 = "0b7e6a52-3f0c-4c6e-9d2a-1f4b5c6d7e01"
+	testCursorID2 = "0b7e6a52-3f0c-4c6e-9d2a-1f4b5c6d7e02"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2025, 3, 1, 10, 30, 0, 123456000, time.UTC)
+
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{name: "forward", cursor: Cursor{CreatedAt: createdAt, ID: testCursorID1}},
+		{name: "backward", cursor: Cursor{CreatedAt: createdAt, ID: testCursorID1, Backward: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(tt.cursor.Encode())
+			if err != nil {
+				t.Fatalf("DecodeCursor() error = %v", err)
+			}
+			if !got.CreatedAt.Equal(tt.cursor.CreatedAt) || got.ID != tt.cursor.ID || got.Backward != tt.cursor.Backward {
+				t.Errorf("DecodeCursor() = %+v, want %+v", *got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor_Rejects(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "not base64", value: "%%%"},
+		{name: "not json", value: encode("created_at=2025")},
+		{name: "missing id", value: encode(`{"t":"2025-03-01T10:30:00Z"}`)},
+		{name: "missing time", value: encode(`{"id":"` + testCursorID1 + `"}`)},
+		{name: "id is not a uuid", value: encode(`{"t":"2025-03-01T10:30:00Z","id":"user-1"}`)},
+		{name: "injected SQL in id", value: encode(`{"t":"2025-03-01T10:30:00Z","id":"' OR 1=1 --"}`)},
+		{name: "time is not a timestamp", value: encode(`{"t":"yesterday","id":"` + testCursorID1 + `"}`)},
+		{name: "tampered encoding", value: Cursor{CreatedAt: time.Now(), ID: testCursorID1}.Encode()[1:]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := DecodeCursor(tt.value)
+			if err == nil || err.Error() != ErrInvalidCursor {
+				t.Fatalf("DecodeCursor() = %+v, %v, want error %q", cursor, err, ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+// testRow es una fila mínima para probar la paginación por cursor
+type testRow struct {
+	ID        string
+	CreatedAt time.Time
+}
+
+func testRowCursor(row testRow) Cursor {
+	return Cursor{CreatedAt: row.CreatedAt, ID: row.ID}
+}
+
+func TestNewCursorPaginatedResult(t *testing.T) {
+	base := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	row := func(i int) testRow {
+		return testRow{ID: fmt.Sprintf("00000000-0000-0000-0000-%012d", i), CreatedAt: base.Add(-time.Duration(i) * time.Hour)}
+	}
+	rows := func(from, to int) []testRow {
+		result := []testRow{}
+		for i := from; i <= to; i++ {
+			result = append(result, row(i))
+		}
+		return result
+	}
+	reversed := func(data []testRow) []testRow {
+		result := []testRow{}
+		for i := len(data) - 1; i >= 0; i-- {
+			result = append(result, data[i])
+		}
+		return result
+	}
+	encoded := func(r testRow, backward bool) *string {
+		value := Cursor{CreatedAt: r.CreatedAt, ID: r.ID, Backward: backward}.Encode()
+		return &value
+	}
+
+	received := &Cursor{CreatedAt: base.Add(-5 * time.Hour), ID: testCursorID2}
+	receivedBackward := &Cursor{CreatedAt: received.CreatedAt, ID: received.ID, Backward: true}
+
+	tests := []struct {
+		name        string
+		data        []testRow
+		cursor      *Cursor
+		wantData    []testRow
+		wantHasPrev bool
+		wantHasNext bool
+		wantPrev    *string
+		wantNext    *string
+	}{
+		{
+			name:        "first page with more rows",
+			data:        rows(0, 10),
+			wantData:    rows(0, 9),
+			wantHasNext: true,
+			wantNext:    encoded(row(9), false),
+		},
+		{
+			name:     "first page is the only page",
+			data:     rows(0, 3),
+			wantData: rows(0, 3),
+		},
+		{
+			name:        "forward page in the middle",
+			cursor:      received,
+			data:        rows(10, 20),
+			wantData:    rows(10, 19),
+			wantHasPrev: true,
+			wantHasNext: true,
+			wantPrev:    encoded(row(10), true),
+			wantNext:    encoded(row(19), false),
+		},
+		{
+			name:        "backward page restores the order",
+			cursor:      receivedBackward,
+			data:        reversed(rows(10, 20)),
+			wantData:    rows(11, 20),
+			wantHasPrev: true,
+			wantHasNext: true,
+			wantPrev:    encoded(row(11), true),
+			wantNext:    encoded(row(20), false),
+		},
+		{
+			name:        "backward page reaching the start",
+			cursor:      receivedBackward,
+			data:        reversed(rows(0, 3)),
+			wantData:    rows(0, 3),
+			wantHasNext: true,
+			wantNext:    encoded(row(3), false),
+		},
+		{
+			name:        "empty forward page keeps the received position",
+			cursor:      received,
+			data:        []testRow{},
+			wantData:    []testRow{},
+			wantHasPrev: true,
+			wantPrev:    encoded(testRow{ID: received.ID, CreatedAt: received.CreatedAt}, true),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pagination := &Pagination{Limit: 10, CursorMode: true, Cursor: tt.cursor}
+
+			result := NewCursorPaginatedResult(tt.data, pagination, 25, testRowCursor)
+			info := result.Pagination
+
+			if !reflect.DeepEqual(result.Data, tt.wantData) {
+				t.Errorf("Data = %v, want %v", result.Data, tt.wantData)
+			}
+			if info.HasPrevious != tt.wantHasPrev || info.HasNext != tt.wantHasNext {
+				t.Errorf("HasPrevious, HasNext = %v, %v, want %v, %v", info.HasPrevious, info.HasNext, tt.wantHasPrev, tt.wantHasNext)
+			}
+			if !reflect.DeepEqual(info.PrevCursor, tt.wantPrev) {
+				t.Errorf("PrevCursor = %v, want %v", deref(info.PrevCursor), deref(tt.wantPrev))
+			}
+			if !reflect.DeepEqual(info.NextCursor, tt.wantNext) {
+				t.Errorf("NextCursor = %v, want %v", deref(info.NextCursor), deref(tt.wantNext))
+			}
+			if info.Limit != 10 || info.Page != 0 {
+				t.Errorf("Pagination = %+v, want limit 10 and no page number", *info)
+			}
+
+			// Solo la primera página informa el total
+			if tt.cursor == nil {
+				if info.Total == nil || *info.Total != 25 || info.TotalPages == nil || *info.TotalPages != 3 {
+					t.Errorf("Total, TotalPages = %v, %v, want 25 and 3 on the first page", info.Total, info.TotalPages)
+				}
+			} else if info.Total != nil || info.TotalPages != nil {
+				t.Errorf("Total set = %v, TotalPages set = %v, want them unset after the first page", info.Total != nil, info.TotalPages != nil)
+			}
+		})
+	}
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return "<nil>"
+	}
+	return *value
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// RefreshToken representa un token opaco de refresco. Los tokens emitidos a partir del mismo
+// inicio de sesión comparten FamilyID, lo que permite revocar toda la cadena si se detecta reutilización.
+type RefreshToken struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	FamilyID  string     `json:"family_id"`
+	TwoFactor bool       `json:"two_factor"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return now.After(t.ExpiresAt)
+}
+
+func (t *RefreshToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// RevokedToken registra un JWT de acceso invalidado antes de su expiración.
+// Solo es necesario conservarlo hasta ExpiresAt; después el token es rechazado por sí mismo.
+type RevokedToken struct {
+	TokenID   string    `json:"token_id"`
+	UserID    string    `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Permisos reconocidos por la API. Se asignan a roles y se exigen con JWTMiddleware.RequirePermission.
+const (
+	PermissionUsersRead        = "users:read"
+	PermissionUsersWrite       = "users:write"
+	PermissionUsersImpersonate = "users:impersonate"
+	PermissionRolesManage      = "roles:manage"
+	PermissionAuditRead        = "audit:read"
+	PermissionAPIKeysManage    = "api_keys:manage"
+
+	// PermissionNewsPublish queda reservado para el módulo de noticias de la portada
+	PermissionNewsPublish = "news:publish"
+)
+
+var Permissions = []string{
+	PermissionUsersRead,
+	PermissionUsersWrite,
+	PermissionUsersImpersonate,
+	PermissionRolesManage,
+	PermissionAuditRead,
+	PermissionAPIKeysManage,
+	PermissionNewsPublish,
+}
+
+// AdminPermissions convierten a un rol en administrativo: permiten actuar sobre las cuentas, roles o
+// credenciales de otros usuarios. Los roles que tienen alguno quedan sujetos a las exigencias de administrador.
+var AdminPermissions = []string{
+	PermissionUsersWrite,
+	PermissionUsersImpersonate,
+	PermissionRolesManage,
+	PermissionAPIKeysManage,
+}
+
+// HasAdminPermission indica si el conjunto incluye alguno de los AdminPermissions
+func HasAdminPermission(permissions []string) bool {
+	for _, permission := range AdminPermissions {
+		if slices.Contains(permissions, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsAllPermissions indica si granted incluye cada uno de los permisos de required
+func ContainsAllPermissions(granted, required []string) bool {
+	for _, permission := range required {
+		if !slices.Contains(granted, permission) {
+			return false
+		}
+	}
+	return true
+}
+
+func IsValidPermission(permission string) bool {
+	for _, p := range Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Role agrupa permisos. Los roles de sistema (USER_ROLE y ADMIN_ROLE) se crean en la migración y no pueden eliminarse.
+type Role struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Permissions []string   `json:"permissions"`
+	IsSystem    bool       `json:"is_system"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+// SystemRoles son los roles sembrados en la migración con sus permisos iniciales
+var SystemRoles = []Role{
+	{
+		Name:        UserRole,
+		Description: "Usuario básico",
+		Permissions: []string{},
+		IsSystem:    true,
+	},
+	{
+		Name:        AdminRole,
+		Description: "Administrador con acceso completo",
+		Permissions: Permissions,
+		IsSystem:    true,
+	},
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// Session es un inicio de sesión de un usuario. Su ID viaja en el claim "sid" de los tokens de acceso
+// y es también el FamilyID de sus refresh tokens, de modo que terminarla invalida ambos.
+type Session struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	IP         string     `json:"ip"`
+	UserAgent  string     `json:"user_agent"`
+	TwoFactor  bool       `json:"two_factor"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (s *Session) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+// IsActive indica que la sesión no fue terminada y sigue vigente
+func (s *Session) IsActive(now time.Time) bool {
+	return !s.IsRevoked() && s.ExpiresAt.After(now)
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// TokenClaims representa la información extraída de un JWT de acceso válido
+type TokenClaims struct {
+	TokenID   string
+	UserID    string
+	Email     string
+	Role      string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+
+	// TokenVersion es la versión de seguridad del usuario al emitir el token;
+	// si difiere de la actual, el token quedó invalidado por un cambio en la cuenta
+	TokenVersion int
+
+	// TwoFactor indica que la sesión completó la verificación en dos pasos
+	TwoFactor bool
+
+	// PasswordChangeRequired indica un token restringido que solo permite cambiar la contraseña
+	PasswordChangeRequired bool
+
+	// ActorID es el administrador que suplanta a UserID (claim "act"); vacío en sesiones normales
+	ActorID string
+
+	// SessionID es la sesión a la que pertenece el token (claim "sid"); vacío en tokens sin sesión
+	SessionID string
+}
+
+// IsSessionlessSince indica que el token no pertenece a ninguna sesión pese a haberse emitido desde cutoff,
+// el arranque del proceso: desde el inventario de sesiones todo token lleva "sid", así que solo se aceptan
+// sin él los emitidos antes, que vencen en como máximo su TTL
+func (c *TokenClaims) IsSessionlessSince(cutoff time.Time) bool {
+	return c.SessionID == "" && !c.IssuedAt.Before(cutoff)
+}
+
+// IsImpersonation indica que el token fue emitido para que un administrador actúe como el usuario
+func (c *TokenClaims) IsImpersonation() bool {
+	return c.ActorID != ""
+}
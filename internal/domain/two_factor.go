@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+const (
+	TokenPurposeTwoFactorChallenge = "TWO_FACTOR_CHALLENGE"
+
+	// TwoFactorChallengeTTL es el tiempo para completar el segundo paso del inicio de sesión
+	TwoFactorChallengeTTL = 5 * time.Minute
+
+	// RecoveryCodeCount es la cantidad de códigos de recuperación emitidos al activar 2FA
+	RecoveryCodeCount = 10
+)
+
+// TwoFactor guarda la configuración TOTP de un usuario. Enabled permanece en false hasta que el
+// usuario confirma la inscripción con un código válido.
+type TwoFactor struct {
+	UserID  string `json:"user_id"`
+	Secret  string `json:"-"`
+	Enabled bool   `json:"enabled"`
+
+	// LastUsedStep es el último paso de tiempo aceptado; impide reutilizar un mismo código
+	LastUsedStep int64 `json:"-"`
+
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TokenOptions agrega información de la sesión a los tokens de acceso emitidos
+type TokenOptions struct {
+	// TwoFactor indica que la sesión se inició completando la verificación en dos pasos
+	TwoFactor bool
+
+	// PasswordChangeRequired restringe el token al cambio de contraseña obligatorio
+	PasswordChangeRequired bool
+
+	// ActorID emite un token de suplantación: el token pertenece al usuario pero lo usa este administrador
+	ActorID string
+
+	// TTL reemplaza la vigencia por defecto del token de acceso cuando es mayor que cero
+	TTL time.Duration
+
+	// SessionID asocia el token a una sesión; vacío en los tokens que no pertenecen a un inicio de sesión
+	SessionID string
+}
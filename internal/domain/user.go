@@ -1,39 +1,13 @@
 package domain
 
-import (
-	"errors"
-	"time"
-)
+import "time"
 
 const (
 	UserRole       = "USER_ROLE"
 	AdminRole      = "ADMIN_ROLE"
-	ErrInvalidRole = "rol inválido. Los roles válidos son: USER_ROLE, ADMIN_ROLE"
+	ErrInvalidRole = "rol inválido, el rol no existe"
 )
 
-var ValidRoles = []string{UserRole, AdminRole}
-
-func IsValidRole(role string) bool {
-	for _, validRole := range ValidRoles {
-		if role == validRole {
-			return true
-		}
-	}
-	return false
-}
-
-func ValidateRole(role string) (string, error) {
-	if role == "" {
-		return UserRole, nil
-	}
-
-	if !IsValidRole(role) {
-		return "", errors.New(ErrInvalidRole)
-	}
-
-	return role, nil
-}
-
 type User struct {
 	ID             string     `json:"id"`
 	Name           string     `json:"name"`
@@ -45,4 +19,12 @@ type User struct {
 	EmailValidated bool       `json:"emailValidated"`
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      *time.Time `json:"updated_at"`
+	TokenVersion   int        `json:"-"`
+
+	// MustChangePassword obliga a elegir una contraseña nueva; mientras esté activo el login solo emite
+	// un token restringido al cambio de contraseña
+	MustChangePassword bool `json:"mustChangePassword"`
+
+	// PasswordChangedAt es la fecha del último cambio de contraseña, usada para su vencimiento
+	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty"`
 }
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Campos por los que puede ordenarse el listado de usuarios
+const (
+	UserSortName      = "name"
+	UserSortEmail     = "email"
+	UserSortRole      = "role"
+	UserSortStatus    = "status"
+	UserSortCreatedAt = "created_at"
+	UserSortUpdatedAt = "updated_at"
+
+	SortAsc  = "asc"
+	SortDesc = "desc"
+
+	ErrInvalidUserSort       = "orden inválido, usa sort=<campo>:<asc|desc> con campo name, email, role, status, created_at o updated_at"
+	ErrCursorSortUnsupported = "la paginación por cursor solo admite sort=created_at:asc o sort=created_at:desc"
+)
+
+var userSortFields = map[string]bool{
+	UserSortName:      true,
+	UserSortEmail:     true,
+	UserSortRole:      true,
+	UserSortStatus:    true,
+	UserSortCreatedAt: true,
+	UserSortUpdatedAt: true,
+}
+
+// UserSort indica el campo y la dirección del orden del listado de usuarios
+type UserSort struct {
+	Field string
+	Desc  bool
+}
+
+// DefaultUserSort mantiene el orden histórico del listado: los más recientes primero
+var DefaultUserSort = UserSort{Field: UserSortCreatedAt, Desc: true}
+
+// UserFilter restringe el listado de usuarios; los campos vacíos o nil no filtran
+type UserFilter struct {
+	Role           string
+	Status         *bool
+	EmailValidated *bool
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	UpdatedFrom    *time.Time
+	UpdatedTo      *time.Time
+	Sort           UserSort
+}
+
+// IsEmpty indica si el filtro no restringe el listado, sin considerar el orden
+func (f UserFilter) IsEmpty() bool {
+	return f.Role == "" && f.Status == nil && f.EmailValidated == nil &&
+		f.CreatedFrom == nil && f.CreatedTo == nil && f.UpdatedFrom == nil && f.UpdatedTo == nil
+}
+
+// ParseUserSort interpreta "<campo>[:<asc|desc>]"; sin valor devuelve DefaultUserSort y sin dirección ordena ascendente
+func ParseUserSort(value string) (UserSort, error) {
+	if value == "" {
+		return DefaultUserSort, nil
+	}
+
+	field, direction, _ := strings.Cut(strings.ToLower(strings.TrimSpace(value)), ":")
+	if !userSortFields[field] {
+		return UserSort{}, errors.New(ErrInvalidUserSort)
+	}
+
+	switch direction {
+	case "", SortAsc:
+		return UserSort{Field: field}, nil
+	case SortDesc:
+		return UserSort{Field: field, Desc: true}, nil
+	default:
+		return UserSort{}, errors.New(ErrInvalidUserSort)
+	}
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "testing"
+
+func TestParseUserSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    UserSort
+		wantErr bool
+	}{
+		{name: "empty uses default", value: "", want: DefaultUserSort},
+		{name: "field without direction is ascending", value: "email", want: UserSort{Field: UserSortEmail}},
+		{name: "explicit ascending", value: "name:asc", want: UserSort{Field: UserSortName}},
+		{name: "explicit descending", value: "updated_at:desc", want: UserSort{Field: UserSortUpdatedAt, Desc: true}},
+		{name: "case and spaces are ignored", value: "  Created_At:DESC ", want: UserSort{Field: UserSortCreatedAt, Desc: true}},
+		{name: "unknown column", value: "password", wantErr: true},
+		{name: "id is not sortable", value: "id:asc", wantErr: true},
+		{name: "injected SQL", value: "name; DROP TABLE users", wantErr: true},
+		{name: "unknown direction", value: "name:up", wantErr: true},
+		{name: "empty direction after colon", value: "name:", want: UserSort{Field: UserSortName}},
+		{name: "extra segment", value: "name:asc:desc", wantErr: true},
+		{name: "direction without field", value: ":desc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUserSort(tt.value)
+
+			if tt.wantErr {
+				if err == nil || err.Error() != ErrInvalidUserSort {
+					t.Fatalf("ParseUserSort(%q) error = %v, want %q", tt.value, err, ErrInvalidUserSort)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseUserSort(%q) error = %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseUserSort(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+const (
+	TokenPurposePasswordReset     = "PASSWORD_RESET"
+	TokenPurposeEmailVerification = "EMAIL_VERIFICATION"
+	TokenPurposeInvitation        = "INVITATION"
+	TokenPurposeMagicLink         = "MAGIC_LINK"
+
+	PasswordResetTokenTTL     = 1 * time.Hour
+	EmailVerificationTokenTTL = 24 * time.Hour
+	MagicLinkTokenTTL         = 15 * time.Minute
+
+	// DefaultInvitationTokenTTL es la vigencia por defecto del enlace para establecer la contraseña inicial
+	DefaultInvitationTokenTTL = 72 * time.Hour
+
+	// DefaultImpersonationTTL es la vigencia por defecto de los tokens de suplantación, que no se pueden renovar
+	DefaultImpersonationTTL = 15 * time.Minute
+
+	// Límites de reenvío del email de verificación
+	EmailVerificationResendInterval = 1 * time.Minute
+	EmailVerificationMaxPerHour     = 5
+)
+
+// UserToken representa un token de un solo uso asociado a un usuario.
+// Solo se almacena el hash del token; el valor en texto plano viaja únicamente en el enlace enviado por email.
+type UserToken struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	Purpose   string     `json:"purpose"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (t *UserToken) IsExpired(now time.Time) bool {
+	return now.After(t.ExpiresAt)
+}
+
+func (t *UserToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
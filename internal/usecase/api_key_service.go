@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+)
+
+const DefaultAPIKeyCacheTTL = 30 * time.Second
+
+type apiKeyEntry struct {
+	key   *domain.APIKey
+	owner *domain.User
+}
+
+// apiKeyService autentica las API keys cacheando la clave y su dueño para no abrir una transacción en cada
+// request; last_used_at se actualiza al refrescar la entrada. Una clave revocada desde otra instancia, o un
+// dueño deshabilitado, deja de aceptarse en como máximo el TTL.
+type apiKeyService struct {
+	uowFactory     ui.UnitOfWorkFactory
+	tokenGenerator ui.TokenGenerator
+	audit          interfaces.AuditService
+	// cache se indexa por el hash de la clave
+	cache *ttlCache[string, apiKeyEntry]
+}
+
+func NewAPIKeyService(uowFactory ui.UnitOfWorkFactory, tokenGenerator ui.TokenGenerator, audit interfaces.AuditService, ttl time.Duration) interfaces.APIKeyService {
+	return &apiKeyService{
+		uowFactory:     uowFactory,
+		tokenGenerator: tokenGenerator,
+		audit:          audit,
+		cache:          newTTLCache[string, apiKeyEntry](ttl),
+	}
+}
+
+// Create emite una clave para ownerID. Solo puede delegar permisos que su propio rol tiene.
+func (s *apiKeyService) Create(ctx context.Context, ownerID string, input dto.CreateAPIKeyInput) (*dto.APIKeyCreatedResponse, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	owner, err := uow.UserRepository().GetByID(ctx, ownerID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	granted, err := uow.RoleRepository().GetPermissions(ctx, owner.Role)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	scopes := uniquePermissions(input.Scopes)
+	for _, scope := range scopes {
+		if !slices.Contains(granted, scope) {
+			return nil, errors.New(dto.ErrAPIKeyScopeNotGranted)
+		}
+	}
+
+	token, _, err := s.tokenGenerator.Generate()
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	plain := domain.APIKeyPrefix + token
+	key := &domain.APIKey{
+		Name:      input.Name,
+		Prefix:    plain[:domain.APIKeyDisplayLength],
+		KeyHash:   s.tokenGenerator.Hash(plain),
+		Scopes:    scopes,
+		CreatedBy: owner.ID,
+		ExpiresAt: input.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	if err := uow.APIKeyRepository().Create(ctx, key); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := s.audit.Record(ctx, uow, &domain.AuditEvent{
+		Type:       domain.AuditAPIKeyCreated,
+		TargetType: domain.AuditTargetAPIKey,
+		TargetID:   &key.ID,
+		Details:    map[string]any{domain.AuditDetailScopes: key.Scopes},
+	}); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	return &dto.APIKeyCreatedResponse{Key: plain, APIKey: key}, nil
+}
+
+// List retorna las claves creadas por ownerID, incluidas las revocadas
+func (s *apiKeyService) List(ctx context.Context, ownerID string) ([]*domain.APIKey, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	keys, err := uow.APIKeyRepository().ListByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	return keys, nil
+}
+
+// Revoke revoca una clave de ownerID; las de otros usuarios se reportan como inexistentes
+func (s *apiKeyService) Revoke(ctx context.Context, ownerID, id string) error {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	repo := uow.APIKeyRepository()
+
+	key, err := repo.FindByID(ctx, id)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return errors.New(dto.ErrAPIKeyNotFound)
+		}
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if key.CreatedBy != ownerID {
+		return errors.New(dto.ErrAPIKeyNotFound)
+	}
+
+	if key.IsRevoked() {
+		return errors.New(dto.ErrAPIKeyAlreadyRevoked)
+	}
+
+	if err := repo.Revoke(ctx, key.ID, time.Now()); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := s.audit.Record(ctx, uow, &domain.AuditEvent{
+		Type:       domain.AuditAPIKeyRevoked,
+		TargetType: domain.AuditTargetAPIKey,
+		TargetID:   &key.ID,
+	}); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	s.cache.Delete(key.KeyHash)
+
+	return nil
+}
+
+// Authenticate rechaza con el mismo error las claves desconocidas, revocadas, expiradas o cuyo dueño
+// fue deshabilitado, para no revelar cuál de las condiciones falló
+func (s *apiKeyService) Authenticate(ctx context.Context, plain string) (*domain.APIKey, *domain.User, error) {
+	if !strings.HasPrefix(plain, domain.APIKeyPrefix) {
+		return nil, nil, errors.New(dto.ErrAPIKeyInvalid)
+	}
+
+	now := time.Now()
+	keyHash := s.tokenGenerator.Hash(plain)
+
+	entry, ok := s.cache.Get(keyHash, now)
+	if !ok {
+		var err error
+		entry, err = s.fetch(ctx, keyHash, now)
+		if err != nil {
+			return nil, nil, errors.New(dto.ErrInternalServer)
+		}
+
+		// Las claves desconocidas no se cachean: cualquiera puede presentar una distinta en cada petición
+		if entry.key != nil {
+			s.cache.Set(keyHash, entry, now)
+		}
+	}
+
+	if entry.key == nil || entry.owner == nil || entry.key.IsRevoked() || entry.key.IsExpired(now) || !entry.owner.Status {
+		return nil, nil, errors.New(dto.ErrAPIKeyInvalid)
+	}
+
+	// Se retornan copias para que quien las reciba no modifique la entrada cacheada
+	key, owner := *entry.key, *entry.owner
+	return &key, &owner, nil
+}
+
+// fetch carga la clave y su dueño y registra el uso de la clave si sigue vigente. Una clave desconocida o sin
+// dueño se retorna con key u owner en nil.
+func (s *apiKeyService) fetch(ctx context.Context, keyHash string, now time.Time) (apiKeyEntry, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return apiKeyEntry{}, err
+	}
+	defer uow.Rollback()
+
+	key, err := uow.APIKeyRepository().FindByHash(ctx, keyHash)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return apiKeyEntry{}, nil
+		}
+		return apiKeyEntry{}, err
+	}
+
+	owner, err := uow.UserRepository().GetByID(ctx, key.CreatedBy)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return apiKeyEntry{key: key}, nil
+		}
+		return apiKeyEntry{}, err
+	}
+	owner.Password = nil
+
+	if key.IsRevoked() || key.IsExpired(now) || !owner.Status {
+		return apiKeyEntry{key: key, owner: owner}, nil
+	}
+
+	if err := uow.APIKeyRepository().TouchLastUsed(ctx, key.ID, now, now.Add(-domain.APIKeyLastUsedInterval)); err != nil {
+		return apiKeyEntry{}, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return apiKeyEntry{}, err
+	}
+
+	return apiKeyEntry{key: key, owner: owner}, nil
+}
@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+// newTestAPIKeyService crea el servicio con un administrador y un agente de soporte (users:read y users:write)
+func newTestAPIKeyService(t *testing.T) (*fakeUnitOfWork, *fakeAuditService, *apiKeyService) {
+	t.Helper()
+
+	uow := newFakeUnitOfWork()
+	uow.roles.permissions[supportRole] = []string{domain.PermissionUsersRead, domain.PermissionUsersWrite}
+	uow.users.users["admin-1"] = &domain.User{ID: "admin-1", Email: "admin@appfe.com", Role: domain.AdminRole, Status: true}
+	uow.users.users["support-1"] = &domain.User{ID: "support-1", Email: "soporte@appfe.com", Role: supportRole, Status: true}
+
+	audit := &fakeAuditService{}
+	service := NewAPIKeyService(fakeUnitOfWorkFactory{uow: uow}, newFakeTokenGenerator(), audit, time.Minute)
+
+	return uow, audit, service.(*apiKeyService)
+}
+
+// createTestAPIKey emite una clave para ownerID y retorna la clave completa junto a su ID
+func createTestAPIKey(t *testing.T, service *apiKeyService, ownerID string, scopes ...string) (string, string) {
+	t.Helper()
+
+	response, err := service.Create(context.Background(), ownerID, dto.CreateAPIKeyInput{Name: "Generador", Scopes: scopes})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return response.Key, response.APIKey.ID
+}
+
+func TestAPIKeyService_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		ownerID string
+		scopes  []string
+		wantErr string
+	}{
+		{name: "scopes granted by the owner role", ownerID: "support-1", scopes: []string{domain.PermissionUsersRead, domain.PermissionUsersRead}},
+		{name: "scope the owner role lacks", ownerID: "support-1", scopes: []string{domain.PermissionRolesManage}, wantErr: dto.ErrAPIKeyScopeNotGranted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, audit, service := newTestAPIKeyService(t)
+
+			response, err := service.Create(context.Background(), tt.ownerID, dto.CreateAPIKeyInput{Name: "Generador", Scopes: tt.scopes})
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Create() error = %v, want %q", err, tt.wantErr)
+				}
+				if len(uow.apiKeys.keys) != 0 {
+					t.Error("Create() stored a rejected key")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			key := uow.apiKeys.keys[response.APIKey.ID]
+			if response.Key != domain.APIKeyPrefix+"plain-token-1" || key.KeyHash != "sha:"+response.Key || key.Prefix != response.Key[:domain.APIKeyDisplayLength] {
+				t.Errorf("key = %q, stored hash %q and prefix %q", response.Key, key.KeyHash, key.Prefix)
+			}
+			if key.CreatedBy != tt.ownerID || !slices.Equal(key.Scopes, []string{domain.PermissionUsersRead}) {
+				t.Errorf("stored key = %+v, want deduplicated scopes owned by %q", key, tt.ownerID)
+			}
+			if !slices.Equal(audit.eventTypes(), []string{domain.AuditAPIKeyCreated}) {
+				t.Errorf("audit events = %v, want %q", audit.eventTypes(), domain.AuditAPIKeyCreated)
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_ListIsScopedToOwner(t *testing.T) {
+	_, _, service := newTestAPIKeyService(t)
+	_, adminKey := createTestAPIKey(t, service, "admin-1", domain.PermissionUsersRead)
+	createTestAPIKey(t, service, "support-1", domain.PermissionUsersRead)
+
+	keys, err := service.List(context.Background(), "admin-1")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0].ID != adminKey {
+		t.Errorf("List() = %v, want only %q", keys, adminKey)
+	}
+}
+
+func TestAPIKeyService_Revoke(t *testing.T) {
+	tests := []struct {
+		name    string
+		ownerID string
+		id      func(ownKey string) string
+		revoked bool
+		wantErr string
+	}{
+		{name: "own key", ownerID: "support-1"},
+		{name: "key of another user", ownerID: "admin-1", wantErr: dto.ErrAPIKeyNotFound},
+		{name: "already revoked", ownerID: "support-1", revoked: true, wantErr: dto.ErrAPIKeyAlreadyRevoked},
+		{name: "unknown key", ownerID: "support-1", id: func(string) string { return "key-99" }, wantErr: dto.ErrAPIKeyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, audit, service := newTestAPIKeyService(t)
+			plain, id := createTestAPIKey(t, service, "support-1", domain.PermissionUsersRead)
+			if tt.revoked {
+				revokedAt := time.Now().Add(-time.Hour)
+				uow.apiKeys.keys[id].RevokedAt = &revokedAt
+			}
+			if tt.id != nil {
+				id = tt.id(id)
+			}
+
+			err := service.Revoke(context.Background(), tt.ownerID, id)
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Revoke() error = %v, want %q", err, tt.wantErr)
+				}
+				if !tt.revoked {
+					if _, _, err := service.Authenticate(context.Background(), plain); err != nil {
+						t.Errorf("rejected revocation disabled the key: %v", err)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Revoke() error = %v", err)
+			}
+			if !uow.apiKeys.keys[id].IsRevoked() {
+				t.Error("key was not revoked")
+			}
+			if !slices.Contains(audit.eventTypes(), domain.AuditAPIKeyRevoked) {
+				t.Errorf("audit events = %v, want %q", audit.eventTypes(), domain.AuditAPIKeyRevoked)
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		key     func(plain string) string
+		setup   func(uow *fakeUnitOfWork, id string)
+		wantErr string
+	}{
+		{name: "valid key"},
+		{name: "missing prefix", key: func(string) string { return "plain-token-1" }, wantErr: dto.ErrAPIKeyInvalid},
+		{name: "unknown key", key: func(string) string { return domain.APIKeyPrefix + "unknown" }, wantErr: dto.ErrAPIKeyInvalid},
+		{name: "revoked", setup: func(uow *fakeUnitOfWork, id string) { uow.apiKeys.keys[id].RevokedAt = &past }, wantErr: dto.ErrAPIKeyInvalid},
+		{name: "expired", setup: func(uow *fakeUnitOfWork, id string) { uow.apiKeys.keys[id].ExpiresAt = &past }, wantErr: dto.ErrAPIKeyInvalid},
+		{name: "owner disabled", setup: func(uow *fakeUnitOfWork, _ string) { uow.users.users["support-1"].Status = false }, wantErr: dto.ErrAPIKeyInvalid},
+		{name: "owner deleted", setup: func(uow *fakeUnitOfWork, _ string) { delete(uow.users.users, "support-1") }, wantErr: dto.ErrAPIKeyInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, _, service := newTestAPIKeyService(t)
+			plain, id := createTestAPIKey(t, service, "support-1", domain.PermissionUsersRead)
+			if tt.setup != nil {
+				tt.setup(uow, id)
+			}
+			if tt.key != nil {
+				plain = tt.key(plain)
+			}
+
+			key, owner, err := service.Authenticate(context.Background(), plain)
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Authenticate() error = %v, want %q", err, tt.wantErr)
+				}
+				if uow.apiKeys.touches != 0 {
+					t.Error("Authenticate() recorded the use of a rejected key")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if key.ID != id || owner.ID != "support-1" || owner.Password != nil {
+				t.Errorf("Authenticate() = key %q owner %q, want %q owned by support-1 without password", key.ID, owner.ID, id)
+			}
+			if uow.apiKeys.keys[id].LastUsedAt == nil {
+				t.Error("last_used_at was not recorded")
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_AuthenticateCache(t *testing.T) {
+	uow, _, service := newTestAPIKeyService(t)
+	plain, id := createTestAPIKey(t, service, "support-1", domain.PermissionUsersRead)
+	ctx := context.Background()
+	commits := uow.commits
+
+	for range 3 {
+		if _, _, err := service.Authenticate(ctx, plain); err != nil {
+			t.Fatalf("Authenticate() error = %v", err)
+		}
+	}
+	if uow.apiKeys.lookups != 1 || uow.apiKeys.touches != 1 || uow.commits != commits+1 {
+		t.Errorf("lookups = %d, touches = %d, commits = %d, want one transaction for cached requests",
+			uow.apiKeys.lookups, uow.apiKeys.touches, uow.commits-commits)
+	}
+
+	// La entrada vencida se vuelve a consultar
+	entry, _ := service.cache.Get("sha:"+plain, time.Now())
+	service.cache.Set("sha:"+plain, entry, time.Now().Add(-2*time.Minute))
+
+	if _, _, err := service.Authenticate(ctx, plain); err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if uow.apiKeys.lookups != 2 {
+		t.Errorf("lookups = %d, want the expired entry refetched", uow.apiKeys.lookups)
+	}
+
+	// La revocación en esta instancia se aplica de inmediato
+	if err := service.Revoke(ctx, "support-1", id); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, _, err := service.Authenticate(ctx, plain); err == nil || err.Error() != dto.ErrAPIKeyInvalid {
+		t.Errorf("Authenticate() after Revoke error = %v, want %q", err, dto.ErrAPIKeyInvalid)
+	}
+
+	// Las claves desconocidas no ocupan la caché
+	for range 2 {
+		service.Authenticate(ctx, domain.APIKeyPrefix+"unknown")
+	}
+	if _, ok := service.cache.Get("sha:"+domain.APIKeyPrefix+"unknown", time.Now()); ok {
+		t.Error("unknown key was cached")
+	}
+}
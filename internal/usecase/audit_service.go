@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/pkg/logger"
+)
+
+type auditService struct {
+	uowFactory ui.UnitOfWorkFactory
+}
+
+func NewAuditService(uowFactory ui.UnitOfWorkFactory) interfaces.AuditService {
+	return &auditService{uowFactory: uowFactory}
+}
+
+// Record completa el evento con los datos de la petición presentes en ctx y lo guarda en uow.
+// Además lo emite en el log de la aplicación, donde los intentos de login usan LogAuthentication.
+func (s *auditService) Record(ctx context.Context, uow ui.UnitOfWork, event *domain.AuditEvent) error {
+	meta := domain.RequestMetadataFromContext(ctx)
+
+	event.IP = meta.IP
+	event.UserAgent = meta.UserAgent
+	event.RequestID = meta.RequestID
+	event.CreatedAt = time.Now()
+
+	if event.ActorID == nil && meta.ActorID != "" {
+		event.ActorID = &meta.ActorID
+	}
+
+	// En una suplantación el actor es siempre el administrador, aunque el servicio indique al usuario
+	if meta.ImpersonatedUserID != "" {
+		event.ActorID = &meta.ActorID
+		if event.Details == nil {
+			event.Details = map[string]any{}
+		}
+		event.Details[domain.AuditDetailImpersonatedUser] = meta.ImpersonatedUserID
+	}
+
+	if meta.APIKeyID != "" {
+		if event.Details == nil {
+			event.Details = map[string]any{}
+		}
+		event.Details[domain.AuditDetailAPIKey] = meta.APIKeyID
+	}
+
+	if err := uow.AuditEventRepository().Create(ctx, event); err != nil {
+		return err
+	}
+
+	switch event.Type {
+	case domain.AuditLoginSucceeded, domain.AuditLoginFailed:
+		email, _ := event.Details[domain.AuditDetailEmail].(string)
+		reason, _ := event.Details[domain.AuditDetailReason].(string)
+		logger.GetLogger().LogAuthentication(ctx, email, event.Type == domain.AuditLoginSucceeded, reason)
+	default:
+		actorID := ""
+		if event.ActorID != nil {
+			actorID = *event.ActorID
+		}
+		details := map[string]any{"audit_event_id": event.ID}
+		if event.TargetID != nil {
+			details["target_id"] = *event.TargetID
+		}
+		logger.GetLogger().LogBusinessOperation(ctx, event.Type, actorID, details)
+	}
+
+	return nil
+}
+
+func (s *auditService) List(ctx context.Context, filter domain.AuditFilter, pagination *domain.Pagination) (*domain.PaginatedResult[*domain.AuditEvent], error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	if pagination == nil {
+		pagination = domain.NewPagination(domain.DefaultPage, domain.DefaultLimit, "")
+	}
+
+	events, total, err := uow.AuditEventRepository().List(ctx, filter, pagination)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if events == nil {
+		events = []*domain.AuditEvent{}
+	}
+
+	return domain.NewPaginatedResult(events, pagination, total), nil
+}
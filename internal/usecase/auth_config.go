@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+// AuthConfig agrupa la configuración de los flujos de autenticación
+type AuthConfig struct {
+	// FrontendURL es la URL base del portal usada para construir los enlaces enviados por email
+	FrontendURL string
+
+	// AccessTokenTTL es la vigencia de los JWT de acceso
+	AccessTokenTTL time.Duration
+
+	// RefreshTokenTTL es la vigencia de cada refresh token; se renueva en cada rotación
+	RefreshTokenTTL time.Duration
+
+	// MaxFailedLoginAttempts es el número de fallos consecutivos que bloquea una cuenta
+	MaxFailedLoginAttempts int
+
+	// LockoutBaseDuration es la duración del primer bloqueo; cada bloqueo consecutivo la duplica
+	LockoutBaseDuration time.Duration
+
+	// LockoutMaxDuration limita el crecimiento exponencial del bloqueo
+	LockoutMaxDuration time.Duration
+
+	// IPMaxFailedAttempts es el número de fallos permitidos desde una misma IP dentro de IPAttemptWindow
+	IPMaxFailedAttempts int
+
+	// IPAttemptWindow es la ventana usada para contar los fallos por IP
+	IPAttemptWindow time.Duration
+
+	// LoginAttemptRetention es el tiempo que se conservan los intentos de login; nunca es menor que IPAttemptWindow
+	LoginAttemptRetention time.Duration
+
+	// RequireAdminTwoFactor exige que las cuentas con permisos de administrador inicien sesión con verificación en dos pasos
+	RequireAdminTwoFactor bool
+
+	// InvitationTTL es la vigencia del enlace de invitación enviado al crear un usuario
+	InvitationTTL time.Duration
+
+	// ImpersonationTTL es la vigencia de los tokens de suplantación emitidos a los administradores
+	ImpersonationTTL time.Duration
+
+	// OIDCAutoProvisionDomains son los dominios de correo cuyas cuentas se crean con USER_ROLE al iniciar
+	// sesión con el proveedor OIDC por primera vez; vacío deshabilita la creación automática
+	OIDCAutoProvisionDomains []string
+}
+
+const (
+	DefaultAccessTokenTTL  = 15 * time.Minute
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+	DefaultMaxFailedLoginAttempts = 5
+	DefaultLockoutBaseDuration    = 5 * time.Minute
+	DefaultLockoutMaxDuration     = 24 * time.Hour
+	DefaultIPMaxFailedAttempts    = 50
+	DefaultIPAttemptWindow        = 15 * time.Minute
+	DefaultLoginAttemptRetention  = 30 * 24 * time.Hour
+)
+
+// NewAuthConfigFromEnv crea la configuración de autenticación desde variables de entorno
+func NewAuthConfigFromEnv() AuthConfig {
+	return AuthConfig{
+		FrontendURL:     strings.TrimRight(os.Getenv(dto.EnvFrontendURL), "/"),
+		AccessTokenTTL:  durationFromEnv(dto.EnvAccessTokenTTL, DefaultAccessTokenTTL),
+		RefreshTokenTTL: durationFromEnv(dto.EnvRefreshTokenTTL, DefaultRefreshTokenTTL),
+
+		MaxFailedLoginAttempts: intFromEnv(dto.EnvLoginMaxFailedAttempts, DefaultMaxFailedLoginAttempts),
+		LockoutBaseDuration:    durationFromEnv(dto.EnvLoginLockoutBase, DefaultLockoutBaseDuration),
+		LockoutMaxDuration:     durationFromEnv(dto.EnvLoginLockoutMax, DefaultLockoutMaxDuration),
+		IPMaxFailedAttempts:    intFromEnv(dto.EnvLoginIPMaxFailedAttempts, DefaultIPMaxFailedAttempts),
+		IPAttemptWindow:        durationFromEnv(dto.EnvLoginIPAttemptWindow, DefaultIPAttemptWindow),
+		LoginAttemptRetention:  durationFromEnv(dto.EnvLoginAttemptRetention, DefaultLoginAttemptRetention),
+
+		RequireAdminTwoFactor: boolFromEnv(dto.EnvRequireAdminTwoFactor),
+
+		InvitationTTL:    durationFromEnv(dto.EnvInvitationTTL, domain.DefaultInvitationTokenTTL),
+		ImpersonationTTL: durationFromEnv(dto.EnvImpersonationTTL, domain.DefaultImpersonationTTL),
+
+		OIDCAutoProvisionDomains: splitLowerList(os.Getenv(dto.EnvOIDCAutoProvisionDomains)),
+	}
+}
+
+// durationFromEnv lee una duración en formato Go (ej. "15m", "720h"); si no existe o es inválida usa el valor por defecto
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fallback
+	}
+
+	return d
+}
+
+// intFromEnv lee un entero positivo; si no existe o es inválido usa el valor por defecto
+func intFromEnv(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return fallback
+	}
+
+	return n
+}
+
+// boolFromEnv interpreta "true", "1", etc. como verdadero; cualquier otro valor es falso
+func boolFromEnv(key string) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	return err == nil && value
+}
+
+// boolFromEnvOr lee un booleano; si no existe o es inválido usa el valor por defecto
+func boolFromEnvOr(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// splitLowerList separa una lista por comas, en minúsculas y sin elementos vacíos
+func splitLowerList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// lockoutDuration calcula la duración del bloqueo número lockoutCount (empezando en 0) con crecimiento exponencial
+func (c AuthConfig) lockoutDuration(lockoutCount int) time.Duration {
+	d := c.LockoutBaseDuration
+	for i := 0; i < lockoutCount && d < c.LockoutMaxDuration; i++ {
+		d *= 2
+	}
+
+	if d > c.LockoutMaxDuration {
+		return c.LockoutMaxDuration
+	}
+
+	return d
+}
+
+// buildFrontendLink construye un enlace del portal que transporta un token en el query string
+func (c AuthConfig) buildFrontendLink(path, token string) string {
+	query := url.Values{}
+	query.Set("token", token)
+	return c.FrontendURL + path + "?" + query.Encode()
+}
@@ -3,54 +3,312 @@ package usecase
 import (
 	"context"
 	"errors"
+	"sync"
+	"time"
 
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
 	"github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
 	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	usecaseInterfaces "github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/pkg/logger"
+	"github.com/google/uuid"
 )
 
 type AuthService struct {
-	uowFactory     interfaces.UnitOfWorkFactory
-	passwordHasher interfaces.PasswordHasher
-	jwtService     interfaces.JWTService
+	uowFactory       interfaces.UnitOfWorkFactory
+	passwordHasher   interfaces.PasswordHasher
+	passwordPolicy   *PasswordPolicy
+	audit            usecaseInterfaces.AuditService
+	jwtService       interfaces.JWTService
+	tokenGenerator   interfaces.TokenGenerator
+	messagingService interfaces.MessagingService
+	templateService  interfaces.TemplateService
+	revocation       usecaseInterfaces.TokenRevocationService
+	twoFactor        interfaces.TwoFactorProvider
+	oidc             interfaces.OIDCProvider
+	config           AuthConfig
+
+	// sessionlessCutoff es el arranque del proceso, igual que en el middleware JWT
+	sessionlessCutoff time.Time
+
+	// now es el reloj del login; los tests lo reemplazan para simular el paso del tiempo
+	now func() time.Time
+
+	dummyHashOnce sync.Once
+	dummyHash     string
 }
 
 func NewAuthService(
 	uowFactory interfaces.UnitOfWorkFactory,
 	passwordHasher interfaces.PasswordHasher,
 	jwtService interfaces.JWTService,
+	tokenGenerator interfaces.TokenGenerator,
+	messagingService interfaces.MessagingService,
+	templateService interfaces.TemplateService,
+	revocation usecaseInterfaces.TokenRevocationService,
+	twoFactor interfaces.TwoFactorProvider,
+	oidc interfaces.OIDCProvider,
+	passwordPolicy *PasswordPolicy,
+	audit usecaseInterfaces.AuditService,
+	config AuthConfig,
 ) *AuthService {
 	return &AuthService{
-		uowFactory:     uowFactory,
-		passwordHasher: passwordHasher,
-		jwtService:     jwtService,
+		uowFactory:       uowFactory,
+		passwordHasher:   passwordHasher,
+		jwtService:       jwtService,
+		tokenGenerator:   tokenGenerator,
+		messagingService: messagingService,
+		templateService:  templateService,
+		revocation:       revocation,
+		twoFactor:        twoFactor,
+		oidc:             oidc,
+		passwordPolicy:   passwordPolicy,
+		audit:            audit,
+		config:           config,
+
+		sessionlessCutoff: time.Now(),
+		now:               time.Now,
+	}
+}
+
+func (s *AuthService) Login(ctx context.Context, input dto.AuthLoginInput) (*dto.AuthLoginResponse, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	now := s.now()
+
+	if err := s.checkIPThrottle(ctx, uow.LoginAttemptRepository(), input.IP, now); err != nil {
+		return nil, err
+	}
+
+	// Correo desconocido y contraseña incorrecta responden igual para no revelar qué cuentas existen
+	user, err := uow.UserRepository().FindByEmail(ctx, input.Email)
+	if err != nil {
+		if err.Error() != dto.ErrNoRowsFound {
+			return nil, errors.New(dto.ErrInternalServer)
+		}
+		s.verifyPassword(nil, input.Password)
+		return nil, s.rejectLogin(ctx, uow, input, nil, dto.ErrInvalidCredentials)
+	}
+
+	lockoutRepo := uow.AccountLockoutRepository()
+
+	lockout, err := lockoutRepo.Get(ctx, user.ID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	passwordValid := s.verifyPassword(user, input.Password)
+
+	if lockout.IsLocked(now) {
+		// Solo quien conoce la contraseña se entera del bloqueo; el resto recibe el error genérico
+		if passwordValid {
+			return nil, s.rejectLogin(ctx, uow, input, &user.ID, dto.ErrAccountLocked)
+		}
+		return nil, s.rejectLogin(ctx, uow, input, &user.ID, dto.ErrInvalidCredentials)
+	}
+
+	if !passwordValid {
+		lockedUntil, err := s.registerFailedLogin(ctx, lockoutRepo, lockout, now)
+		if err != nil {
+			return nil, errors.New(dto.ErrInternalServer)
+		}
+
+		rejectErr := s.rejectLogin(ctx, uow, input, &user.ID, dto.ErrInvalidCredentials)
+		if lockedUntil != nil && rejectErr.Error() == dto.ErrInvalidCredentials {
+			s.notifyAccountLocked(ctx, user, *lockedUntil)
+		}
+		return nil, rejectErr
+	}
+
+	if !user.EmailValidated {
+		return nil, errors.New(dto.ErrEmailNotValidated)
+	}
+
+	if !user.Status {
+		return nil, errors.New(dto.ErrAccountDisabled)
+	}
+
+	if err := s.upgradePasswordHash(ctx, uow.UserRepository(), user, input.Password); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	twoFactorEnabled, err := isTwoFactorEnabled(ctx, uow.TwoFactorRepository(), user.ID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if twoFactorEnabled {
+		return s.startTwoFactorChallenge(ctx, uow, user)
+	}
+
+	return s.completeLogin(ctx, uow, user, lockout, input, domain.TokenOptions{})
+}
+
+// startTwoFactorChallenge emite el desafío del segundo paso en lugar de los tokens de la sesión.
+// El bloqueo se reinicia recién al completar el segundo paso, así los códigos fallidos también cuentan.
+func (s *AuthService) startTwoFactorChallenge(ctx context.Context, uow interfaces.UnitOfWork, user *domain.User) (*dto.AuthLoginResponse, error) {
+	challenge, err := issueUserToken(ctx, uow.UserTokenRepository(), s.tokenGenerator, user.ID, domain.TokenPurposeTwoFactorChallenge, domain.TwoFactorChallengeTTL)
+	if err != nil {
+		return nil, errors.New(dto.ErrTokenGenerationFailed)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	return &dto.AuthLoginResponse{TwoFactorRequired: true, ChallengeToken: challenge}, nil
+}
+
+// upgradePasswordHash regenera el hash con el algoritmo y costo actuales aprovechando que la contraseña
+// en texto plano está disponible tras verificarla. Si el hash no se puede generar el login continúa igual.
+func (s *AuthService) upgradePasswordHash(ctx context.Context, repo interfaces.UserRepository, user *domain.User, password string) error {
+	if !s.passwordHasher.NeedsRehash(*user.Password) {
+		return nil
 	}
+
+	hashed, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		logger.Warn(ctx, dto.MsgPasswordRehashFailed,
+			logger.String("user_id", user.ID),
+			logger.Error("error", err),
+		)
+		return nil
+	}
+
+	if err := repo.UpdatePasswordHash(ctx, user.ID, hashed); err != nil {
+		return err
+	}
+
+	user.Password = &hashed
+	logger.Info(ctx, dto.MsgPasswordRehashed, logger.String("user_id", user.ID))
+
+	return nil
 }
 
-func (s *AuthService) Login(input dto.AuthLoginInput) (*dto.AuthLoginResponse, error) {
-	ctx := context.Background()
+// completeLogin reinicia el bloqueo, registra el intento exitoso y emite el par de tokens de la nueva sesión
+func (s *AuthService) completeLogin(ctx context.Context, uow interfaces.UnitOfWork, user *domain.User, lockout *domain.AccountLockout, input dto.AuthLoginInput, opts domain.TokenOptions) (*dto.AuthLoginResponse, error) {
+	if !lockout.IsClean() {
+		if err := uow.AccountLockoutRepository().Reset(ctx, user.ID); err != nil {
+			return nil, errors.New(dto.ErrInternalServer)
+		}
+	}
+
+	if err := s.recordLoginAttempt(ctx, uow.LoginAttemptRepository(), input, &user.ID, true); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	// Con el cambio de contraseña pendiente o la contraseña vencida solo se emite un token restringido
+	// y sin refresh token
+	opts.PasswordChangeRequired = user.MustChangePassword || s.passwordPolicy.IsExpired(user, time.Now())
+
+	// Sin refresh token la sesión no puede extenderse más allá del token de acceso
+	sessionTTL := s.config.RefreshTokenTTL
+	if opts.PasswordChangeRequired {
+		sessionTTL = s.config.AccessTokenTTL
+	}
+
+	opts.SessionID = uuid.NewString()
+	if err := createSession(ctx, uow.SessionRepository(), opts.SessionID, user.ID, opts.TwoFactor, time.Now().Add(sessionTTL)); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	token, err := s.jwtService.GenerateToken(*user, opts)
+	if err != nil {
+		return nil, errors.New(dto.ErrTokenGenerationFailed)
+	}
+
+	var refreshToken string
+	if !opts.PasswordChangeRequired {
+		refreshToken, err = s.issueRefreshToken(ctx, uow.RefreshTokenRepository(), user.ID, opts.SessionID, opts.TwoFactor)
+		if err != nil {
+			return nil, errors.New(dto.ErrTokenGenerationFailed)
+		}
+	}
+
+	if err := s.audit.Record(ctx, uow, &domain.AuditEvent{
+		Type:       domain.AuditLoginSucceeded,
+		ActorID:    &user.ID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &user.ID,
+		Details: map[string]any{
+			domain.AuditDetailEmail:          user.Email,
+			domain.AuditDetailTwoFactor:      opts.TwoFactor,
+			domain.AuditDetailPasswordChange: opts.PasswordChangeRequired,
+		},
+	}); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
 
+	user.Password = nil
+
+	response := &dto.AuthLoginResponse{
+		Token:                  token,
+		RefreshToken:           refreshToken,
+		User:                   *user,
+		PasswordChangeRequired: opts.PasswordChangeRequired,
+		SessionID:              opts.SessionID,
+	}
+
+	return response, nil
+}
+
+// Refresh rota un refresh token: el token presentado queda usado y se emite uno nuevo de la misma familia.
+// Presentar un token ya usado se considera un robo y revoca la familia completa.
+func (s *AuthService) Refresh(ctx context.Context, input dto.AuthRefreshInput) (*dto.AuthLoginResponse, error) {
 	uow, err := s.uowFactory.New(ctx)
 	if err != nil {
 		return nil, errors.New(dto.ErrInternalServer)
 	}
 	defer uow.Rollback()
 
-	userRepo := uow.UserRepository()
+	refreshRepo := uow.RefreshTokenRepository()
 
-	user, err := userRepo.FindByEmail(ctx, input.Email)
+	current, err := refreshRepo.FindByHash(ctx, s.tokenGenerator.Hash(input.RefreshToken))
 	if err != nil {
 		if err.Error() == dto.ErrNoRowsFound {
-			return nil, errors.New(dto.ErrInvalidCredentials)
+			return nil, errors.New(dto.ErrRefreshTokenInvalid)
 		}
 		return nil, errors.New(dto.ErrInternalServer)
 	}
 
-	if user.Password == nil {
-		return nil, errors.New(dto.ErrInvalidCredentials)
+	if input.SessionID != "" && current.FamilyID != input.SessionID {
+		return nil, errors.New(dto.ErrRefreshTokenInvalid)
+	}
+
+	if current.IsRevoked() {
+		return nil, errors.New(dto.ErrRefreshTokenInvalid)
+	}
+
+	if current.IsUsed() {
+		logger.Warn(ctx, dto.MsgRefreshTokenReuseDetected,
+			logger.String("user_id", current.UserID),
+			logger.String("family_id", current.FamilyID),
+		)
+
+		if err := s.endSession(ctx, uow, current.FamilyID); err != nil {
+			return nil, errors.New(dto.ErrInternalServer)
+		}
+		if err := uow.Commit(); err != nil {
+			return nil, errors.New(dto.ErrInternalServer)
+		}
+		return nil, errors.New(dto.ErrRefreshTokenReused)
 	}
 
-	if err := s.passwordHasher.Verify(*user.Password, input.Password); err != nil {
-		return nil, errors.New(dto.ErrPasswordIncorrect)
+	if current.IsExpired(time.Now()) {
+		return nil, errors.New(dto.ErrRefreshTokenInvalid)
+	}
+
+	user, err := uow.UserRepository().GetByID(ctx, current.UserID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
 	}
 
 	if !user.EmailValidated {
@@ -61,7 +319,28 @@ func (s *AuthService) Login(input dto.AuthLoginInput) (*dto.AuthLoginResponse, e
 		return nil, errors.New(dto.ErrAccountDisabled)
 	}
 
-	token, err := s.jwtService.GenerateToken(*user)
+	if user.MustChangePassword {
+		return nil, errors.New(dto.ErrPasswordChangeRequired)
+	}
+
+	if s.passwordPolicy.IsExpired(user, time.Now()) {
+		return nil, errors.New(dto.ErrPasswordExpired)
+	}
+
+	if err := refreshRepo.MarkUsed(ctx, current.ID); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := s.extendSession(ctx, uow.SessionRepository(), current); err != nil {
+		return nil, err
+	}
+
+	token, err := s.jwtService.GenerateToken(*user, domain.TokenOptions{TwoFactor: current.TwoFactor, SessionID: current.FamilyID})
+	if err != nil {
+		return nil, errors.New(dto.ErrTokenGenerationFailed)
+	}
+
+	refreshToken, err := s.issueRefreshToken(ctx, refreshRepo, user.ID, current.FamilyID, current.TwoFactor)
 	if err != nil {
 		return nil, errors.New(dto.ErrTokenGenerationFailed)
 	}
@@ -73,18 +352,74 @@ func (s *AuthService) Login(input dto.AuthLoginInput) (*dto.AuthLoginResponse, e
 	user.Password = nil
 
 	response := &dto.AuthLoginResponse{
-		Token: token,
-		User:  *user,
+		Token:        token,
+		RefreshToken: refreshToken,
+		User:         *user,
+		SessionID:    current.FamilyID,
 	}
 
 	return response, nil
 }
 
-func (s *AuthService) SignInWithToken(input dto.AuthTokenSignInInput) (*dto.AuthLoginResponse, error) {
-	ctx := context.Background()
+// extendSession renueva la sesión de la familia del refresh token. Una sesión terminada no se puede extender
+// aunque su familia siga vigente; las familias emitidas antes del inventario de sesiones obtienen su sesión
+// en la primera rotación.
+func (s *AuthService) extendSession(ctx context.Context, repo interfaces.SessionRepository, current *domain.RefreshToken) error {
+	now := time.Now()
+	expiresAt := now.Add(s.config.RefreshTokenTTL)
 
-	user, err := s.jwtService.ValidateToken(input.Token)
+	session, err := repo.FindByID(ctx, current.FamilyID)
 	if err != nil {
+		if err.Error() != dto.ErrNoRowsFound {
+			return errors.New(dto.ErrInternalServer)
+		}
+		if err := createSession(ctx, repo, current.FamilyID, current.UserID, current.TwoFactor, expiresAt); err != nil {
+			return errors.New(dto.ErrInternalServer)
+		}
+		return nil
+	}
+
+	if !session.IsActive(now) {
+		return errors.New(dto.ErrRefreshTokenInvalid)
+	}
+
+	extended, err := repo.Extend(ctx, session.ID, expiresAt, now)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+	if !extended {
+		return errors.New(dto.ErrRefreshTokenInvalid)
+	}
+
+	return nil
+}
+
+func (s *AuthService) issueRefreshToken(ctx context.Context, repo interfaces.RefreshTokenRepository, userID, familyID string, twoFactor bool) (string, error) {
+	plainToken, tokenHash, err := s.tokenGenerator.Generate()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	refreshToken := &domain.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TwoFactor: twoFactor,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(s.config.RefreshTokenTTL),
+		CreatedAt: now,
+	}
+
+	if err := repo.Create(ctx, refreshToken); err != nil {
+		return "", err
+	}
+
+	return plainToken, nil
+}
+
+func (s *AuthService) SignInWithToken(ctx context.Context, input dto.AuthTokenSignInInput) (*dto.AuthLoginResponse, error) {
+	claims, err := s.jwtService.ValidateToken(input.Token)
+	if err != nil || s.revocation.IsRevoked(claims.TokenID) {
 		return nil, errors.New(dto.ErrInvalidToken)
 	}
 
@@ -96,7 +431,7 @@ func (s *AuthService) SignInWithToken(input dto.AuthTokenSignInInput) (*dto.Auth
 
 	userRepo := uow.UserRepository()
 
-	fullUser, err := userRepo.FindByEmail(ctx, user.Email)
+	fullUser, err := userRepo.FindByEmail(ctx, claims.Email)
 	if err != nil {
 		if err.Error() == dto.ErrNoRowsFound {
 			return nil, errors.New(dto.ErrUserNotFoundForToken)
@@ -104,6 +439,10 @@ func (s *AuthService) SignInWithToken(input dto.AuthTokenSignInInput) (*dto.Auth
 		return nil, errors.New(dto.ErrInternalServer)
 	}
 
+	if fullUser.TokenVersion != claims.TokenVersion {
+		return nil, errors.New(dto.ErrTokenOutdated)
+	}
+
 	if !fullUser.EmailValidated {
 		return nil, errors.New(dto.ErrEmailNotValidated)
 	}
@@ -112,11 +451,43 @@ func (s *AuthService) SignInWithToken(input dto.AuthTokenSignInInput) (*dto.Auth
 		return nil, errors.New(dto.ErrAccountDisabled)
 	}
 
-	newToken, err := s.jwtService.GenerateToken(*fullUser)
+	// Igual que el middleware: el token de una sesión terminada no puede canjearse por una sesión nueva
+	if err := s.checkTokenSession(ctx, uow.SessionRepository(), claims); err != nil {
+		return nil, err
+	}
+
+	// El token se vuelve a firmar solo para asociarlo a una sesión nueva: conserva los claims y el
+	// vencimiento del presentado, de modo que extender la sesión solo es posible con un refresh token
+	opts := domain.TokenOptions{
+		TwoFactor:              claims.TwoFactor,
+		PasswordChangeRequired: claims.PasswordChangeRequired,
+		ActorID:                claims.ActorID,
+		TTL:                    time.Until(claims.ExpiresAt),
+		SessionID:              uuid.NewString(),
+	}
+
+	if opts.TTL <= 0 {
+		return nil, errors.New(dto.ErrInvalidToken)
+	}
+
+	if err := createSession(ctx, uow.SessionRepository(), opts.SessionID, fullUser.ID, opts.TwoFactor, claims.ExpiresAt); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	token, err := s.jwtService.GenerateToken(*fullUser, opts)
 	if err != nil {
 		return nil, errors.New(dto.ErrTokenGenerationFailed)
 	}
 
+	if err := s.audit.Record(ctx, uow, &domain.AuditEvent{
+		Type:       domain.AuditTokenSignIn,
+		ActorID:    &fullUser.ID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &fullUser.ID,
+	}); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
 	if err := uow.Commit(); err != nil {
 		return nil, errors.New(dto.ErrInternalServer)
 	}
@@ -124,9 +495,343 @@ func (s *AuthService) SignInWithToken(input dto.AuthTokenSignInInput) (*dto.Auth
 	fullUser.Password = nil
 
 	response := &dto.AuthLoginResponse{
-		Token: newToken,
-		User:  *fullUser,
+		Token:                  token,
+		User:                   *fullUser,
+		PasswordChangeRequired: claims.PasswordChangeRequired,
+		SessionID:              opts.SessionID,
 	}
 
 	return response, nil
 }
+
+// checkTokenSession aplica las mismas reglas de sesión que el middleware JWT, consultando la sesión sin caché
+func (s *AuthService) checkTokenSession(ctx context.Context, repo interfaces.SessionRepository, claims *domain.TokenClaims) error {
+	if claims.SessionID == "" {
+		if claims.IsSessionlessSince(s.sessionlessCutoff) {
+			return errors.New(dto.ErrInvalidToken)
+		}
+		return nil
+	}
+
+	session, err := repo.FindByID(ctx, claims.SessionID)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return errors.New(dto.ErrSessionTerminated)
+		}
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if !session.IsActive(time.Now()) {
+		return errors.New(dto.ErrSessionTerminated)
+	}
+
+	return nil
+}
+
+// Logout revoca el token de acceso presentado y termina su sesión junto con sus refresh tokens.
+// Si se envía un refresh token de otra sesión del mismo usuario, también se termina esa sesión.
+func (s *AuthService) Logout(ctx context.Context, input dto.AuthLogoutInput) error {
+	claims := &domain.TokenClaims{
+		TokenID:   input.TokenID,
+		UserID:    input.UserID,
+		ExpiresAt: input.ExpiresAt,
+	}
+
+	if err := s.revocation.Revoke(ctx, claims); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if input.RefreshToken == "" && input.SessionID == "" {
+		return nil
+	}
+
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	sessionIDs := []string{}
+	if input.SessionID != "" {
+		sessionIDs = append(sessionIDs, input.SessionID)
+	}
+
+	if input.RefreshToken != "" {
+		refreshToken, err := uow.RefreshTokenRepository().FindByHash(ctx, s.tokenGenerator.Hash(input.RefreshToken))
+		if err != nil && err.Error() != dto.ErrNoRowsFound {
+			return errors.New(dto.ErrInternalServer)
+		}
+
+		// Un usuario solo puede cerrar sus propias sesiones
+		if err == nil && refreshToken.UserID == input.UserID && refreshToken.FamilyID != input.SessionID {
+			sessionIDs = append(sessionIDs, refreshToken.FamilyID)
+		}
+	}
+
+	for _, sessionID := range sessionIDs {
+		if err := s.endSession(ctx, uow, sessionID); err != nil {
+			return errors.New(dto.ErrInternalServer)
+		}
+	}
+
+	return uow.Commit()
+}
+
+// endSession termina la sesión y revoca sus refresh tokens, que comparten su ID como familia
+func (s *AuthService) endSession(ctx context.Context, uow interfaces.UnitOfWork, sessionID string) error {
+	if err := uow.RefreshTokenRepository().RevokeFamily(ctx, sessionID); err != nil {
+		return err
+	}
+
+	return uow.SessionRepository().Revoke(ctx, sessionID, time.Now())
+}
+
+// ForgotPassword emite un enlace de restablecimiento de un solo uso.
+// No revela si el correo está registrado: para cuentas inexistentes o deshabilitadas retorna nil sin enviar nada.
+func (s *AuthService) ForgotPassword(ctx context.Context, input dto.AuthForgotPasswordInput) error {
+	if s.messagingService == nil || s.templateService == nil {
+		return errors.New(dto.ErrEmailServiceUnavailable)
+	}
+
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	user, err := uow.UserRepository().FindByEmail(ctx, input.Email)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			logger.Info(ctx, dto.MsgPasswordResetUnknownEmail, logger.String("email", input.Email))
+			return nil
+		}
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if !user.Status {
+		logger.Info(ctx, dto.MsgPasswordResetUnknownEmail, logger.String("email", input.Email))
+		return nil
+	}
+
+	plainToken, err := issueUserToken(ctx, uow.UserTokenRepository(), s.tokenGenerator, user.ID, domain.TokenPurposePasswordReset, domain.PasswordResetTokenTTL)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	resetContent, err := s.templateService.RenderPasswordResetEmail(user.Name, s.config.buildFrontendLink(dto.PasswordResetPath, plainToken))
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	sendEmailAsync(s.messagingService, user.Email, dto.PasswordResetEmailSubject, resetContent, dto.MsgPasswordResetEmailFailed)
+
+	return nil
+}
+
+// ResetPassword consume un token de restablecimiento y reemplaza la contraseña del usuario
+func (s *AuthService) ResetPassword(ctx context.Context, input dto.AuthResetPasswordInput) error {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	resetToken, err := consumeUserToken(ctx, uow.UserTokenRepository(), s.tokenGenerator, domain.TokenPurposePasswordReset, input.Token)
+	if err != nil {
+		if errors.Is(err, errUserTokenInvalid) {
+			return errors.New(dto.ErrPasswordResetTokenInvalid)
+		}
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	user, err := uow.UserRepository().GetByID(ctx, resetToken.UserID)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if !user.Status {
+		return errors.New(dto.ErrAccountDisabled)
+	}
+
+	hashed, err := s.passwordPolicy.Apply(ctx, uow.PasswordHistoryRepository(), user, input.Password)
+	if err != nil {
+		return err
+	}
+
+	mustChange := false
+	if err := uow.UserRepository().UpdateByID(ctx, &dto.UpdateUserInput{ID: user.ID, Password: &hashed, MustChangePassword: &mustChange}); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	// Cerrar las sesiones abiertas con la contraseña anterior
+	if err := uow.RefreshTokenRepository().RevokeByUser(ctx, user.ID); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.SessionRepository().RevokeByUser(ctx, user.ID, time.Now()); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	// Quien demuestra el control del correo recupera el acceso aunque la cuenta estuviera bloqueada
+	if err := uow.AccountLockoutRepository().Reset(ctx, user.ID); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := s.audit.Record(ctx, uow, &domain.AuditEvent{
+		Type:       domain.AuditPasswordReset,
+		ActorID:    &user.ID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &user.ID,
+	}); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	return uow.Commit()
+}
+
+// AcceptInvitation consume un token de invitación y establece la contraseña inicial. Como el enlace llegó
+// al correo del usuario, también marca el correo como validado.
+func (s *AuthService) AcceptInvitation(ctx context.Context, input dto.AuthAcceptInvitationInput) error {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	invitation, err := consumeUserToken(ctx, uow.UserTokenRepository(), s.tokenGenerator, domain.TokenPurposeInvitation, input.Token)
+	if err != nil {
+		if errors.Is(err, errUserTokenInvalid) {
+			return errors.New(dto.ErrInvitationTokenInvalid)
+		}
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	user, err := uow.UserRepository().GetByID(ctx, invitation.UserID)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if !user.Status {
+		return errors.New(dto.ErrAccountDisabled)
+	}
+
+	if user.Password != nil {
+		return errors.New(dto.ErrInvitationTokenInvalid)
+	}
+
+	hashed, err := s.passwordPolicy.Apply(ctx, uow.PasswordHistoryRepository(), user, input.Password)
+	if err != nil {
+		return err
+	}
+
+	validated := true
+	if err := uow.UserRepository().UpdateByID(ctx, &dto.UpdateUserInput{ID: user.ID, Password: &hashed, EmailValidated: &validated}); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := s.audit.Record(ctx, uow, &domain.AuditEvent{
+		Type:       domain.AuditInvitationAccepted,
+		ActorID:    &user.ID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &user.ID,
+	}); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	return uow.Commit()
+}
+
+// VerifyEmail consume un token de verificación y marca el correo del usuario como validado
+func (s *AuthService) VerifyEmail(ctx context.Context, input dto.AuthVerifyEmailInput) error {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	verificationToken, err := consumeUserToken(ctx, uow.UserTokenRepository(), s.tokenGenerator, domain.TokenPurposeEmailVerification, input.Token)
+	if err != nil {
+		if errors.Is(err, errUserTokenInvalid) {
+			return errors.New(dto.ErrEmailVerificationTokenInvalid)
+		}
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	validated := true
+	if err := uow.UserRepository().UpdateByID(ctx, &dto.UpdateUserInput{ID: verificationToken.UserID, EmailValidated: &validated}); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	return uow.Commit()
+}
+
+// ResendVerificationEmail emite un nuevo enlace de verificación respetando los límites de reenvío.
+// Al igual que ForgotPassword, no revela si el correo está registrado o ya fue validado.
+func (s *AuthService) ResendVerificationEmail(ctx context.Context, input dto.AuthResendVerificationInput) error {
+	if s.messagingService == nil || s.templateService == nil {
+		return errors.New(dto.ErrEmailServiceUnavailable)
+	}
+
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	user, err := uow.UserRepository().FindByEmail(ctx, input.Email)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return nil
+		}
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if user.EmailValidated || !user.Status {
+		return nil
+	}
+
+	// Un reenvío limitado responde igual que uno exitoso: un 429 solo ocurriría para cuentas existentes
+	// sin verificar y permitiría enumerarlas
+	throttled, err := s.verificationThrottled(ctx, uow.UserTokenRepository(), user.ID)
+	if err != nil {
+		return err
+	}
+	if throttled {
+		logger.Warn(ctx, dto.MsgEmailVerificationThrottled, logger.String("user_id", user.ID))
+		return nil
+	}
+
+	content, err := renderVerificationEmail(ctx, uow.UserTokenRepository(), s.tokenGenerator, s.templateService, s.config, user)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	sendEmailAsync(s.messagingService, user.Email, dto.EmailValidationSubject, content, dto.MsgEmailVerificationFailed)
+
+	return nil
+}
+
+// verificationThrottled indica si el usuario alcanzó el límite de reenvíos del enlace de verificación
+func (s *AuthService) verificationThrottled(ctx context.Context, repo interfaces.UserTokenRepository, userID string) (bool, error) {
+	now := time.Now()
+
+	recent, err := repo.CountCreatedSince(ctx, userID, domain.TokenPurposeEmailVerification, now.Add(-domain.EmailVerificationResendInterval))
+	if err != nil {
+		return false, errors.New(dto.ErrInternalServer)
+	}
+
+	hourly, err := repo.CountCreatedSince(ctx, userID, domain.TokenPurposeEmailVerification, now.Add(-time.Hour))
+	if err != nil {
+		return false, errors.New(dto.ErrInternalServer)
+	}
+
+	return recent > 0 || hourly >= domain.EmailVerificationMaxPerHour, nil
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+func TestAuthService_SignInWithToken(t *testing.T) {
+	startedAt := time.Now()
+
+	tests := []struct {
+		name        string
+		sessionID   string
+		session     *domain.Session
+		terminate   bool
+		issuedAt    time.Time
+		revoke      bool
+		wantErr     string
+		wantSession bool
+	}{
+		{name: "active session", sessionID: "session-1", session: &domain.Session{ExpiresAt: startedAt.Add(time.Hour)}, wantSession: true},
+		{name: "terminated session", sessionID: "session-1", session: &domain.Session{ExpiresAt: startedAt.Add(time.Hour)}, terminate: true, wantErr: dto.ErrSessionTerminated},
+		{name: "expired session", sessionID: "session-1", session: &domain.Session{ExpiresAt: startedAt.Add(-time.Minute)}, wantErr: dto.ErrSessionTerminated},
+		{name: "unknown session", sessionID: "session-9", wantErr: dto.ErrSessionTerminated},
+		{name: "sessionless token issued before startup", issuedAt: startedAt.Add(-time.Minute), wantSession: true},
+		{name: "sessionless token issued after startup", issuedAt: startedAt.Add(time.Second), wantErr: dto.ErrInvalidToken},
+		{name: "revoked token", sessionID: "session-1", session: &domain.Session{ExpiresAt: startedAt.Add(time.Hour)}, revoke: true, wantErr: dto.ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, _, service := newTestAuthService(t)
+			service.sessionlessCutoff = startedAt
+			ctx := context.Background()
+
+			if tt.session != nil {
+				tt.session.ID, tt.session.UserID = tt.sessionID, "user-1"
+				uow.sessions.sessions[tt.sessionID] = tt.session
+			}
+
+			claims := &domain.TokenClaims{
+				TokenID:   "presented",
+				UserID:    "user-1",
+				Email:     "ana@appfe.com",
+				Role:      domain.UserRole,
+				SessionID: tt.sessionID,
+				IssuedAt:  tt.issuedAt,
+				ExpiresAt: time.Now().Add(10 * time.Minute),
+			}
+			service.jwtService.(*fakeJWTService).issued["presented"] = claims
+
+			if tt.terminate {
+				uow.users.users["admin-1"] = &domain.User{ID: "admin-1", Role: domain.AdminRole, Status: true}
+				adminCtx := domain.WithActor(ctx, "admin-1")
+				if err := NewSessionService(fakeUnitOfWorkFactory{uow: uow}, &fakeAuditService{}, time.Minute).Terminate(adminCtx, "user-1", tt.sessionID); err != nil {
+					t.Fatalf("Terminate() error = %v", err)
+				}
+			}
+			if tt.revoke {
+				if err := service.revocation.Revoke(ctx, claims); err != nil {
+					t.Fatalf("Revoke() error = %v", err)
+				}
+			}
+			sessionsBefore := len(uow.sessions.sessions)
+
+			response, err := service.SignInWithToken(ctx, dto.AuthTokenSignInInput{Token: "presented"})
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("SignInWithToken() error = %v, want %q", err, tt.wantErr)
+				}
+				if len(uow.sessions.sessions) != sessionsBefore {
+					t.Error("a rejected exchange created a session")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SignInWithToken() error = %v", err)
+			}
+
+			session, ok := uow.sessions.sessions[response.SessionID]
+			if !ok || response.SessionID == tt.sessionID || session.UserID != "user-1" {
+				t.Fatalf("SignInWithToken() session = %q, want a new session of user-1", response.SessionID)
+			}
+			if !session.ExpiresAt.Equal(claims.ExpiresAt) {
+				t.Errorf("session expires at %v, want the presented token expiry %v", session.ExpiresAt, claims.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		used          bool
+		expiresAt     time.Time
+		session       *domain.Session
+		logout        bool
+		revokeSession bool
+		wantErr       string
+		wantRevoked   bool
+	}{
+		{name: "rotation", expiresAt: now.Add(time.Hour), session: &domain.Session{ExpiresAt: now.Add(time.Hour)}},
+		{name: "family without session gets one", expiresAt: now.Add(time.Hour)},
+		{name: "reuse of a rotated token", used: true, expiresAt: now.Add(time.Hour), session: &domain.Session{ExpiresAt: now.Add(time.Hour)}, wantErr: dto.ErrRefreshTokenReused, wantRevoked: true},
+		{name: "expired token", expiresAt: now.Add(-time.Minute), session: &domain.Session{ExpiresAt: now.Add(time.Hour)}, wantErr: dto.ErrRefreshTokenInvalid},
+		{name: "after logout", expiresAt: now.Add(time.Hour), session: &domain.Session{ExpiresAt: now.Add(time.Hour)}, logout: true, wantErr: dto.ErrRefreshTokenInvalid},
+		{name: "revoked session with a valid family", expiresAt: now.Add(time.Hour), session: &domain.Session{ExpiresAt: now.Add(time.Hour)}, revokeSession: true, wantErr: dto.ErrRefreshTokenInvalid},
+		{name: "expired session", expiresAt: now.Add(time.Hour), session: &domain.Session{ExpiresAt: now.Add(-time.Minute)}, wantErr: dto.ErrRefreshTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, _, service := newTestAuthService(t)
+			ctx := context.Background()
+
+			presented := &domain.RefreshToken{ID: "refresh-0", UserID: "user-1", FamilyID: "session-1", TokenHash: "sha:presented", ExpiresAt: tt.expiresAt}
+			if tt.used {
+				usedAt := now.Add(-time.Minute)
+				presented.UsedAt = &usedAt
+			}
+			uow.refreshTokens.tokens = append(uow.refreshTokens.tokens,
+				presented,
+				&domain.RefreshToken{ID: "refresh-other", UserID: "user-1", FamilyID: "session-1", TokenHash: "sha:successor", ExpiresAt: now.Add(time.Hour)},
+			)
+			if tt.session != nil {
+				tt.session.ID, tt.session.UserID = "session-1", "user-1"
+				uow.sessions.sessions["session-1"] = tt.session
+			}
+
+			if tt.logout {
+				input := dto.AuthLogoutInput{RefreshToken: "presented", TokenID: "access-1", UserID: "user-1", ExpiresAt: now.Add(time.Minute)}
+				if err := service.Logout(ctx, input); err != nil {
+					t.Fatalf("Logout() error = %v", err)
+				}
+				uow.commits = 0
+			}
+			if tt.revokeSession {
+				revokedAt := now.Add(-time.Minute)
+				tt.session.RevokedAt = &revokedAt
+			}
+			tokensBefore := len(uow.refreshTokens.tokens)
+
+			response, err := service.Refresh(ctx, dto.AuthRefreshInput{RefreshToken: "presented"})
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Refresh() error = %v, want %q", err, tt.wantErr)
+				}
+				if len(uow.refreshTokens.tokens) != tokensBefore {
+					t.Error("a rejected refresh issued a new refresh token")
+				}
+				if tt.wantRevoked {
+					for _, token := range uow.refreshTokens.tokens {
+						if !token.IsRevoked() {
+							t.Errorf("token %s of the reused family is still valid", token.ID)
+						}
+					}
+					if !uow.sessions.sessions["session-1"].IsRevoked() || uow.commits != 1 {
+						t.Errorf("session revoked = %v with %d commits, want it revoked and committed", uow.sessions.sessions["session-1"].IsRevoked(), uow.commits)
+					}
+				} else if uow.commits != 0 {
+					t.Errorf("rejected refresh committed %d times", uow.commits)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Refresh() error = %v", err)
+			}
+
+			if response.RefreshToken != "plain-token-1" || response.SessionID != "session-1" {
+				t.Errorf("Refresh() = token %q in session %q, want plain-token-1 in session-1", response.RefreshToken, response.SessionID)
+			}
+			if !presented.IsUsed() {
+				t.Error("the presented token was not marked as used")
+			}
+			successor := uow.refreshTokens.tokens[len(uow.refreshTokens.tokens)-1]
+			if len(uow.refreshTokens.tokens) != tokensBefore+1 || successor.FamilyID != "session-1" || successor.TokenHash != "sha:plain-token-1" {
+				t.Errorf("issued token = %+v, want one successor in session-1", successor)
+			}
+			session, ok := uow.sessions.sessions["session-1"]
+			if !ok || session.ExpiresAt.Before(now.Add(23*time.Hour)) {
+				t.Errorf("session = %+v, want it to last the refresh TTL", session)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+// AdminPasswordResetInput elige cómo se entrega la nueva credencial: una contraseña temporal que el
+// administrador comparte, o un enlace de restablecimiento enviado al correo del usuario
+type AdminPasswordResetInput struct {
+	Method string `json:"method" validate:"omitempty,oneof=temporary_password link"`
+}
+
+type AdminPasswordResetResponse struct {
+	Method string `json:"method"`
+
+	// TemporaryPassword se muestra una sola vez; solo se guarda su hash
+	TemporaryPassword string `json:"temporary_password,omitempty"`
+
+	// ResetLink solo se retorna cuando no hay servicio de correo para enviarlo
+	ResetLink string     `json:"reset_link,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/pkg/validator"
+)
+
+type CreateAPIKeyInput struct {
+	Name      string     `json:"name" validate:"required,min=3,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// Validate verifica que los alcances sean permisos delegables y que la expiración, si existe, sea futura
+func (r *CreateAPIKeyInput) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+
+	for _, scope := range r.Scopes {
+		if !domain.IsValidAPIKeyScope(scope) {
+			return fmt.Errorf(ErrAPIKeyScopeInvalid, scope)
+		}
+	}
+
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		return errors.New(ErrAPIKeyExpiresInPast)
+	}
+
+	return nil
+}
+
+// APIKeyCreatedResponse es la única respuesta que incluye la clave completa
+type APIKeyCreatedResponse struct {
+	Key    string         `json:"key"`
+	APIKey *domain.APIKey `json:"api_key"`
+}
@@ -3,4 +3,7 @@ package dto
 type AuthLoginInput struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=8"`
+
+	// IP la asigna el handler a partir de la petición; se usa para limitar intentos por origen
+	IP string `json:"-"`
 }
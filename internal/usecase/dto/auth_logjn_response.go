@@ -3,6 +3,17 @@ package dto
 import "github.com/JacobD36/appfe_frontpage_api/internal/domain"
 
 type AuthLoginResponse struct {
-	Token string      `json:"token"`
-	User  domain.User `json:"user"`
+	Token        string      `json:"token"`
+	RefreshToken string      `json:"refresh_token,omitempty"`
+	User         domain.User `json:"user"`
+
+	// TwoFactorRequired indica que falta el segundo paso; ChallengeToken debe enviarse junto al código TOTP
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+
+	// PasswordChangeRequired indica que Token es restringido y solo sirve para cambiar la contraseña
+	PasswordChangeRequired bool `json:"password_change_required,omitempty"`
+
+	// SessionID es la sesión del token emitido; no se serializa, el modo cookie la usa para el token CSRF
+	SessionID string `json:"-"`
 }
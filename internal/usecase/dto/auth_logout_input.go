@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+type AuthLogoutInput struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+
+	// Datos del token de acceso, tomados del contexto del middleware
+	TokenID   string    `json:"-"`
+	UserID    string    `json:"-"`
+	ExpiresAt time.Time `json:"-"`
+	SessionID string    `json:"-"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+type AuthMagicLinkInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type AuthMagicLinkExchangeInput struct {
+	Token string `json:"token" validate:"required"`
+
+	// IP la asigna el handler a partir de la petición
+	IP string `json:"-"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+type AuthOIDCCallbackInput struct {
+	Code  string `json:"code" validate:"required"`
+	State string `json:"state" validate:"required"`
+
+	// IP la asigna el handler a partir de la petición
+	IP string `json:"-"`
+
+	// BrowserState es el state de la cookie HttpOnly fijada al iniciar el flujo; debe coincidir con State
+	BrowserState string `json:"-"`
+}
+
+// OIDCAuthorizationResponse contiene la URL del proveedor a la que el portal redirige al usuario
+type OIDCAuthorizationResponse struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	ExpiresAt        time.Time `json:"expires_at"`
+
+	// State no se devuelve en el cuerpo: el handler lo guarda en una cookie HttpOnly para ligar el
+	// callback al navegador que inició el flujo
+	State string `json:"-"`
+}
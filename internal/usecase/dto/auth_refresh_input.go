@@ -0,0 +1,8 @@
+package dto
+
+type AuthRefreshInput struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+
+	// SessionID, si no está vacío, exige que el refresh token pertenezca a esa sesión (la del token CSRF)
+	SessionID string `json:"-"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+import "github.com/JacobD36/appfe_frontpage_api/pkg/validator"
+
+type AuthResetPasswordInput struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+func (r *AuthResetPasswordInput) Validate() error {
+	return validator.Validate.Struct(r)
+}
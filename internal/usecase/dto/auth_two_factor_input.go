@@ -0,0 +1,24 @@
+package dto
+
+// AuthTwoFactorLoginInput completa el inicio de sesión de una cuenta con verificación en dos pasos.
+// Code acepta un código TOTP o un código de recuperación.
+type AuthTwoFactorLoginInput struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+
+	// IP la asigna el handler a partir de la petición
+	IP string `json:"-"`
+}
+
+type TwoFactorCodeInput struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type TwoFactorEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
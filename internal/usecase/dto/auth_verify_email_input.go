@@ -0,0 +1,9 @@
+package dto
+
+type AuthVerifyEmailInput struct {
+	Token string `json:"token" query:"token" validate:"required"`
+}
+
+type AuthResendVerificationInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
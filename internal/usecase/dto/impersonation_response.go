@@ -0,0 +1,16 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+// ImpersonationResponse contiene el token de suplantación; no incluye refresh token, por lo que la
+// sesión termina al expirar el token
+type ImpersonationResponse struct {
+	Token     string      `json:"token"`
+	ExpiresAt time.Time   `json:"expires_at"`
+	ActorID   string      `json:"actor_id"`
+	User      domain.User `json:"user"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PasswordPolicyError es el rechazo de una regla de la política de contraseñas. El mensaje ya está
+// listo para el cliente, así que los handlers lo responden como 400 sin traducirlo.
+type PasswordPolicyError struct {
+	message string
+}
+
+func NewPasswordPolicyError(format string, args ...any) error {
+	if len(args) == 0 {
+		return &PasswordPolicyError{message: format}
+	}
+	return &PasswordPolicyError{message: fmt.Sprintf(format, args...)}
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return e.message
+}
+
+func IsPasswordPolicyError(err error) bool {
+	var policyErr *PasswordPolicyError
+	return errors.As(err, &policyErr)
+}
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/JacobD36/appfe_frontpage_api/pkg/validator"
+)
+
+// UpdateProfileInput contiene los campos que un usuario puede editar de su propia cuenta
+type UpdateProfileInput struct {
+	Name *string `json:"name" validate:"omitempty,min=3"`
+	Img  *string `json:"img" validate:"omitempty,url"`
+}
+
+func (p *UpdateProfileInput) Validate() error {
+	if err := validator.Validate.Struct(p); err != nil {
+		return err
+	}
+
+	if p.Name == nil && p.Img == nil {
+		return errors.New(ErrProfileNoFields)
+	}
+
+	return nil
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+func (p *ChangePasswordInput) Validate() error {
+	if err := validator.Validate.Struct(p); err != nil {
+		return err
+	}
+
+	if p.CurrentPassword == p.NewPassword {
+		return errors.New(ErrNewPasswordSameAsCurrent)
+	}
+
+	return nil
+}
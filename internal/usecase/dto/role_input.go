@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/pkg/validator"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+type CreateRoleInput struct {
+	Name        string   `json:"name" validate:"required,min=3,max=50"`
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions"`
+}
+
+// Validate normaliza el nombre a mayúsculas (ej. "editor" -> "EDITOR") y verifica los permisos
+func (r *CreateRoleInput) Validate() error {
+	r.Name = strings.ToUpper(strings.TrimSpace(r.Name))
+
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+
+	if !roleNamePattern.MatchString(r.Name) {
+		return errors.New(ErrRoleNameInvalid)
+	}
+
+	return validatePermissions(r.Permissions)
+}
+
+type UpdateRoleInput struct {
+	Description *string   `json:"description" validate:"omitempty,max=255"`
+	Permissions *[]string `json:"permissions"`
+}
+
+func (r *UpdateRoleInput) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+
+	if r.Permissions == nil {
+		return nil
+	}
+
+	return validatePermissions(*r.Permissions)
+}
+
+func validatePermissions(permissions []string) error {
+	for _, permission := range permissions {
+		if !domain.IsValidPermission(permission) {
+			return fmt.Errorf(ErrPermissionInvalid, permission)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// UserInvitation describe la invitación emitida al crear un usuario o al reenviarla
+type UserInvitation struct {
+	ExpiresAt time.Time `json:"invitation_expires_at"`
+
+	// Link solo se retorna cuando no hay servicio de correo; el administrador debe compartirlo por otro medio
+	Link string `json:"invitation_link,omitempty"`
+}
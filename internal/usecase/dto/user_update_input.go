@@ -10,6 +10,8 @@ type UpdateUserInput struct {
 	Role           *string
 	Status         *bool
 	EmailValidated *bool
+
+	MustChangePassword *bool
 }
 
 func (u UpdateUserInput) GetID() string {
@@ -37,5 +39,8 @@ func (u UpdateUserInput) FieldsToUpdate() map[string]any {
 	if u.EmailValidated != nil {
 		fields["email_validated"] = *u.EmailValidated
 	}
+	if u.MustChangePassword != nil {
+		fields["must_change_password"] = *u.MustChangePassword
+	}
 	return fields
 }
@@ -8,10 +8,19 @@ import (
 
 // Constantes para mensajes de validación y errores del sistema
 const (
-	// Mensajes de validación de contraseña
-	ErrPasswordMinLength   = "la contraseña debe tener al menos 8 caracteres"
-	ErrPasswordComplexity  = "la contraseña debe contener al menos una mayúscula, una minúscula, un número y un carácter especial"
-	ErrPasswordsDoNotMatch = "las contraseñas no coinciden"
+	// Mensajes de validación de contraseña (política configurable)
+	ErrPasswordMinLength     = "la contraseña debe tener al menos %d caracteres"
+	ErrPasswordMaxLength     = "la contraseña no puede exceder %d caracteres"
+	ErrPasswordUppercase     = "la contraseña debe contener al menos una letra mayúscula"
+	ErrPasswordLowercase     = "la contraseña debe contener al menos una letra minúscula"
+	ErrPasswordDigit         = "la contraseña debe contener al menos un número"
+	ErrPasswordSpecial       = "la contraseña debe contener al menos un carácter especial"
+	ErrPasswordForbiddenWord = "la contraseña contiene una palabra no permitida"
+	ErrPasswordPersonalInfo  = "la contraseña no puede contener tu nombre ni tu correo"
+	ErrPasswordReused        = "la contraseña no puede ser igual a ninguna de las últimas %d utilizadas"
+	ErrPasswordExpired       = "tu contraseña ha vencido, debes cambiarla para continuar"
+	ErrPasswordBreached      = "la contraseña aparece en filtraciones de datos conocidas, elige otra"
+	ErrPasswordsDoNotMatch   = "las contraseñas no coinciden"
 
 	// Mensajes de validación de entrada
 	ErrInvalidInput   = "entrada inválida"
@@ -43,27 +52,203 @@ const (
 	ErrLoginSuccess          = "inicio de sesión exitoso"
 	ErrTokenGenerationFailed = "error al generar el token de acceso"
 
+	// Mensajes de restablecimiento de contraseña
+	ErrPasswordResetRequested    = "si el correo está registrado, recibirás un enlace para restablecer tu contraseña"
+	ErrPasswordResetSuccess      = "contraseña restablecida exitosamente"
+	ErrPasswordResetTokenInvalid = "el enlace de restablecimiento es inválido o ha expirado"
+	ErrEmailServiceUnavailable   = "el servicio de correo no está disponible"
+	ErrTokenRandomGeneration     = "error al generar token aleatorio: %w"
+	PasswordResetPath            = "/reset-password"
+
+	// Mensajes de verificación de correo
+	ErrEmailVerificationSuccess      = "correo electrónico verificado exitosamente"
+	ErrEmailVerificationTokenInvalid = "el enlace de verificación es inválido o ha expirado"
+	ErrEmailVerificationResent       = "si el correo está pendiente de verificación, recibirás un nuevo enlace"
+	EmailVerificationPath            = "/verify-email"
+
+	// Mensajes de invitaciones
+	ErrInvitationTokenInvalid    = "el enlace de invitación es inválido o ha expirado"
+	ErrInvitationAcceptedSuccess = "contraseña establecida exitosamente, ya puedes iniciar sesión"
+	ErrInvitationAlreadyAccepted = "el usuario ya estableció su contraseña"
+	ErrInvitationResentSuccess   = "Invitación reenviada exitosamente"
+	InvitationPath               = "/accept-invitation"
+	MsgInvitationLinkNotEmailed  = "Email service unavailable, invitation link returned to the administrator"
+
+	// Mensajes de inicio de sesión con enlace mágico
+	ErrMagicLinkRequested    = "si el correo está registrado, recibirás un enlace para iniciar sesión"
+	ErrMagicLinkTokenInvalid = "el enlace de inicio de sesión es inválido o ha expirado"
+	MagicLinkPath            = "/magic-link"
+
+	// Mensajes de inicio de sesión con proveedor externo (OpenID Connect)
+	ErrOIDCUnavailable          = "el inicio de sesión con proveedor externo no está configurado"
+	ErrOIDCAuthorizationCreated = "redirige al usuario a la URL de autorización"
+	ErrOIDCStateInvalid         = "la solicitud de inicio de sesión es inválida o ha expirado"
+	ErrOIDCLoginFailed          = "no se pudo verificar la identidad con el proveedor externo"
+	ErrOIDCEmailNotVerified     = "el proveedor externo no ha verificado el correo electrónico"
+	ErrOIDCAccountNotFound      = "no existe una cuenta asociada a este correo electrónico"
+	ErrOIDCLinkRequired         = "las cuentas con permisos de administración deben vincular el proveedor externo desde una sesión iniciada"
+	ErrOIDCLinkMismatch         = "la cuenta ya está vinculada a otra identidad del proveedor externo"
+	ErrOIDCIdentityInUse        = "la identidad del proveedor externo ya está vinculada a otra cuenta"
+	ErrOIDCLinkedSuccess        = "Proveedor externo vinculado exitosamente"
+
+	// Mensajes de refresh tokens
+	ErrRefreshTokenInvalid = "refresh token inválido o expirado"
+	ErrRefreshTokenReused  = "refresh token reutilizado, la sesión ha sido revocada"
+	ErrTokenRefreshSuccess = "token renovado exitosamente"
+
+	// Mensajes de cierre de sesión y revocación
+	ErrLogoutSuccess = "sesión cerrada exitosamente"
+	ErrTokenRevoked  = "el token ha sido revocado"
+	ErrTokenOutdated = "la sesión ya no es válida, inicia sesión nuevamente"
+
+	// Mensajes de protección contra fuerza bruta
+	ErrAccountLocked        = "la cuenta está bloqueada temporalmente por demasiados intentos fallidos"
+	ErrTooManyLoginAttempts = "demasiados intentos de inicio de sesión, inténtalo más tarde"
+	ErrUserUnlockedSuccess  = "Usuario desbloqueado exitosamente"
+
+	// Mensajes de verificación en dos pasos
+	ErrTwoFactorChallengeIssued   = "ingresa el código de verificación en dos pasos"
+	ErrTwoFactorChallengeInvalid  = "el desafío de verificación en dos pasos es inválido o ha expirado"
+	ErrTwoFactorCodeInvalid       = "el código de verificación es inválido"
+	ErrTwoFactorAlreadyEnabled    = "la verificación en dos pasos ya está activada"
+	ErrTwoFactorNotEnabled        = "la verificación en dos pasos no está activada"
+	ErrTwoFactorNotEnrolled       = "primero debes iniciar la activación de la verificación en dos pasos"
+	ErrTwoFactorRequiredForAdmins = "la verificación en dos pasos es obligatoria para administradores"
+	ErrTwoFactorLoginRequired     = "inicia sesión con verificación en dos pasos para acceder a este recurso"
+	ErrTwoFactorEnrollStarted     = "escanea el código QR y confirma con un código para activar la verificación en dos pasos"
+	ErrTwoFactorEnabledSuccess    = "verificación en dos pasos activada, guarda tus códigos de recuperación"
+	ErrTwoFactorDisabledSuccess   = "verificación en dos pasos desactivada"
+	ErrRecoveryCodesRegenerated   = "códigos de recuperación regenerados"
+	TwoFactorIssuer               = "APPFE Lima"
+
+	// Mensajes de la cuenta propia (/me)
+	ErrProfileRetrievedSuccess  = "Perfil obtenido exitosamente"
+	ErrProfileUpdatedSuccess    = "Perfil actualizado exitosamente"
+	ErrProfileNoFields          = "no se enviaron campos para actualizar"
+	ErrProfileNameEmpty         = "el nombre no puede estar vacío"
+	ErrPasswordChangedSuccess   = "contraseña actualizada, inicia sesión nuevamente"
+	ErrCurrentPasswordIncorrect = "la contraseña actual es incorrecta"
+	ErrNewPasswordSameAsCurrent = "la nueva contraseña debe ser distinta de la actual"
+	ErrSessionsRetrievedSuccess = "Sesiones obtenidas exitosamente"
+
+	// Mensajes de restablecimiento forzado por un administrador
+	ErrPasswordChangeRequired     = "debes cambiar tu contraseña antes de continuar"
+	ErrPasswordChangeLoginSuccess = "inicio de sesión exitoso, debes cambiar tu contraseña para continuar"
+	ErrAdminPasswordResetSuccess  = "Contraseña restablecida, el usuario deberá cambiarla en su próximo inicio de sesión"
+	ErrAdminPasswordResetLinkSent = "Enlace de restablecimiento emitido, el usuario deberá elegir una contraseña nueva"
+	AdminPasswordResetTemporary   = "temporary_password"
+	AdminPasswordResetLink        = "link"
+
+	// Mensajes de roles y permisos
+	ErrRoleNotFound                = "rol no encontrado"
+	ErrRoleAlreadyExists           = "ya existe un rol con ese nombre"
+	ErrRoleInUse                   = "el rol tiene usuarios asignados y no se puede eliminar"
+	ErrSystemRoleProtected         = "los roles del sistema no se pueden eliminar ni cambiar de permisos"
+	ErrRoleNameInvalid             = "el nombre del rol solo puede contener letras mayúsculas, números y guiones bajos"
+	ErrPermissionInvalid           = "permiso inválido: %s"
+	ErrRolesRetrievedSuccess       = "Roles obtenidos exitosamente"
+	ErrRoleRetrievedSuccess        = "Rol obtenido exitosamente"
+	ErrRoleCreatedSuccess          = "Rol creado exitosamente"
+	ErrRoleUpdatedSuccess          = "Rol actualizado exitosamente"
+	ErrRoleDeletedSuccess          = "Rol eliminado exitosamente"
+	ErrPermissionsRetrievedSuccess = "Permisos obtenidos exitosamente"
+	MsgPermissionLookupFailed      = "Failed to resolve role permissions"
+	ErrRoleAssignmentForbidden     = "no puedes asignar ni retirar un rol con permisos que tú no tienes"
+	ErrOwnRoleChangeForbidden      = "no puedes cambiar tu propio rol"
+	ErrUserWriteForbidden          = "no puedes modificar a un usuario con permisos que tú no tienes"
+	ErrOwnRoleUpdateForbidden      = "no puedes modificar tu propio rol"
+	ErrRolePermissionsForbidden    = "no puedes otorgar ni retirar permisos que tú no tienes"
+
+	// Mensajes de suplantación de usuarios
+	ErrImpersonationAdminForbidden  = "no se puede suplantar a un administrador"
+	ErrImpersonationRoleForbidden   = "no puedes suplantar a un usuario con permisos que tú no tienes"
+	ErrImpersonationNested          = "no se puede iniciar una suplantación desde una sesión suplantada"
+	ErrImpersonationActionForbidden = "esta acción no está permitida durante una suplantación"
+	ErrImpersonationStartedSuccess  = "Suplantación iniciada exitosamente"
+
+	// Mensajes de API keys
+	ErrAPIKeyInvalid           = "API key inválida, expirada o revocada"
+	ErrAPIKeyNotAllowed        = "esta ruta no acepta API keys"
+	ErrAPIKeyNotFound          = "API key no encontrada"
+	ErrAPIKeyAlreadyRevoked    = "la API key ya fue revocada"
+	ErrAPIKeyScopeInvalid      = "alcance inválido para una API key: %s"
+	ErrAPIKeyScopeNotGranted   = "no puede delegar permisos que su rol no tiene"
+	ErrAPIKeyExpiresInPast     = "la fecha de expiración debe ser futura"
+	ErrAPIKeyRoleChange        = "una API key no puede cambiar el rol de un usuario"
+	ErrAPIKeyCreatedSuccess    = "API key creada exitosamente. Guárdela ahora: no se volverá a mostrar"
+	ErrAPIKeysRetrievedSuccess = "API keys obtenidas exitosamente"
+	ErrAPIKeyRevokedSuccess    = "API key revocada exitosamente"
+	MsgAPIKeyLookupFailed      = "Failed to authenticate API key"
+
+	// Mensajes de sesiones
+	ErrSessionTerminated        = "la sesión fue terminada"
+	ErrSessionNotFound          = "sesión no encontrada"
+	ErrSessionAlreadyTerminated = "la sesión ya fue terminada"
+	ErrSessionTerminatedSuccess = "Sesión terminada exitosamente"
+	MsgSessionLookupFailed      = "Failed to resolve session state"
+
+	// Mensajes del registro de auditoría
+	ErrAuditEventsRetrievedSuccess = "Eventos de auditoría obtenidos exitosamente"
+
+	// Mensajes de parámetros de consulta
+	ErrQueryDateInvalid = "fecha inválida en %s, usa el formato RFC3339 (ej. 2025-01-31T00:00:00Z)"
+	ErrQueryBoolInvalid = "valor inválido en %s, usa true o false"
+
+	// Mensajes de limitación de peticiones
+	ErrRateLimitExceeded      = "demasiadas peticiones, inténtalo de nuevo más tarde"
+	ErrInvalidRateLimitFormat = "invalid rate limit format, expected <requests>/<duration>"
+
 	// Mensajes de BcryptHasher
 	ErrPasswordTooLong      = "la contraseña no puede exceder 72 caracteres"
 	ErrHashEmpty            = "el hash de la contraseña no puede estar vacío"
 	ErrHashGeneration       = "error al generar hash: %w"
 	ErrPasswordIncorrect    = "contraseña incorrecta"
 	ErrPasswordVerification = "error al verificar contraseña: %w"
+	ErrHashFormatInvalid    = "formato de hash de contraseña no soportado"
+	MsgPasswordRehashFailed = "Failed to rehash password with current algorithm"
+	MsgPasswordRehashed     = "Password hash upgraded to current algorithm"
+	ErrHashParamOutOfRange  = "%s must be an integer between 1 and %d"
+	ErrPasswordHasherConfig = "Invalid password hashing configuration"
+
+	ErrPasswordBreachFileRequired = "PASSWORD_BREACH_FILE is required when PASSWORD_BREACH_CHECK is enabled"
+	ErrPasswordBreachFileInvalid  = "breached password file is not a sorted SHA-1 hash list"
+	ErrFailedLoadBreachFile       = "Failed to open breached password file"
+	MsgPasswordBreachCheckEnabled = "Breached password check enabled"
+	MsgPasswordBreachCheckFailed  = "Breached password lookup failed"
+
+	ErrOIDCConfigRequired      = "OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required when OIDC_ISSUER_URL is set"
+	ErrOIDCConfigInvalid       = "Invalid OIDC configuration"
+	ErrOIDCDiscoveryFailed     = "OIDC discovery failed: %w"
+	ErrOIDCIssuerMismatch      = "OIDC discovery issuer %q does not match %q"
+	ErrOIDCJWKSFailed          = "OIDC JWKS fetch failed: %w"
+	ErrOIDCTokenRequestFailed  = "OIDC token request failed: %w"
+	ErrOIDCTokenRejected       = "OIDC token endpoint returned %d: %s"
+	ErrOIDCIDTokenMissing      = "OIDC token response has no id_token"
+	ErrOIDCSigningKeyUnknown   = "OIDC signing key %q not found"
+	ErrOIDCNonceMismatch       = "OIDC ID token nonce mismatch"
+	ErrOIDCAuthorizedParty     = "OIDC ID token azp does not match client_id"
+	MsgOIDCEnabled             = "OIDC login enabled"
+	MsgOIDCExchangeFailed      = "OIDC code exchange failed"
+	MsgOIDCAuthorizationFailed = "OIDC authorization URL could not be built"
 
 	// Mensajes de servidor
 	ErrInternalServer = "error interno del servidor"
 	ErrNoRowsFound    = "no rows in result set"
 
 	// Mensajes de sistema/main
-	ErrLoadingEnvFile          = "Error loading .env file"
-	ErrRSAKeysNotSet           = "RSA key paths not set in environment variables"
-	ErrFailedLoadRSAKeys       = "Failed to load RSA keys: %v"
-	ErrMigrationFailed         = "Migration failed: %v"
-	ErrServerError             = "Server error: %v"
-	ErrForcedShutdown          = "Forced shutdown: %v"
-	ErrUnitOfWorkFactory       = "Failed to create UnitOfWork factory"
-	MsgShuttingDownServer      = "Shutting down server..."
-	MsgServerStoppedGracefully = "Server stopped gracefully"
+	ErrLoadingEnvFile           = "Error loading .env file"
+	ErrRSAKeysNotSet            = "RSA key paths not set in environment variables"
+	ErrCSRFSecretNotSet         = "CSRF_SECRET must have at least 32 characters when SESSION_COOKIES is enabled"
+	ErrFailedLoadRSAKeys        = "Failed to load RSA keys: %v"
+	ErrFailedReloadRSAKeys      = "Failed to reload RSA keys, keeping current keys"
+	ErrActiveSigningKeyNotFound = "no private key found for active kid %q"
+	ErrRSAKeyPairMismatch       = "RSA public key does not match private key"
+	ErrMigrationFailed          = "Migration failed: %v"
+	ErrServerError              = "Server error: %v"
+	ErrForcedShutdown           = "Forced shutdown: %v"
+	ErrUnitOfWorkFactory        = "Failed to create UnitOfWork factory"
+	MsgShuttingDownServer       = "Shutting down server..."
+	MsgServerStoppedGracefully  = "Server stopped gracefully"
 
 	// Mensajes de base de datos/storage
 	ErrUnsupportedDriver    = "Unsupported database driver: %s"
@@ -92,12 +277,14 @@ const (
 	ErrUnauthorizedAccess      = "acceso no autorizado"
 	ErrTokenSignInSuccess      = "autenticación con token exitosa"
 	ErrUserNotFoundForToken    = "usuario no encontrado para el token proporcionado"
+	ErrCSRFTokenInvalid        = "token CSRF ausente o inválido"
 
 	// Mensajes de logging para el sistema
 	MsgStartingAPIServer        = "Starting APPFE Lima API Server"
 	MsgInitializingDBConnection = "Initializing database connection"
 	MsgLoadingRSAKeys           = "Loading RSA keys"
 	MsgRSAKeysLoadedSuccess     = "RSA keys loaded successfully"
+	MsgRSAKeysReloadRequested   = "SIGHUP received, reloading RSA keys"
 	MsgRunningDBMigrations      = "Running database migrations"
 	MsgDBMigrationsCompleted    = "Database migrations completed successfully"
 	MsgServicesInitialized      = "Services initialized successfully"
@@ -145,6 +332,23 @@ const (
 	MsgMessagingSendingEmail      = "Sending email"
 	MsgMessagingEmailSentSuccess  = "Email sent successfully"
 	MsgMessagingFailedToSendEmail = "Failed to send email"
+	MsgPasswordResetEmailFailed   = "Failed to send password reset email"
+	MsgPasswordResetUnknownEmail  = "Password reset requested for unknown or disabled account"
+	MsgMagicLinkEmailFailed       = "Failed to send magic link email"
+	MsgMagicLinkUnknownEmail      = "Magic link requested for unknown or disabled account"
+	MsgAccountLocked              = "Account locked after repeated failed logins"
+	MsgAccountLockedEmailFailed   = "Failed to send account locked email"
+	MsgLoginIPThrottled           = "Login rejected, too many failed attempts from IP"
+	MsgRateLimitStoreFailed       = "Rate limit store failed, allowing request"
+	MsgInvalidRateLimitConfig     = "Invalid rate limit configuration, using default"
+	MsgEmailVerificationFailed    = "Failed to send email verification"
+	MsgEmailVerificationThrottled = "Email verification resend throttled"
+	MsgWelcomeEmailFailed         = "Failed to send welcome email"
+	MsgRefreshTokenReuseDetected  = "Refresh token reuse detected, revoking token family"
+	MsgTokenRevocationSyncFailed  = "Failed to sync revoked tokens"
+	MsgLoginAttemptPruneFailed    = "Failed to prune old login attempts"
+	MsgInvalidTrustedProxy        = "Invalid trusted proxy, ignoring entry"
+	ErrTokenRevocationInitFailed  = "Failed to load revoked tokens: %v"
 
 	// Mensajes para inicialización de servicios en main.go
 	ErrMessagingServiceInitFailed  = "Failed to initialize email service"
@@ -195,9 +399,63 @@ const (
 	BrevoBulkMessageFormat  = "message %d: %s"
 
 	// Constantes para variables de entorno
-	EnvBrevoAPIKey    = "BREVO_API_KEY"
-	EnvBrevoFromEmail = "BREVO_FROM_EMAIL"
-	EnvBrevoFromName  = "BREVO_FROM_NAME"
+	EnvBrevoAPIKey     = "BREVO_API_KEY"
+	EnvBrevoFromEmail  = "BREVO_FROM_EMAIL"
+	EnvBrevoFromName   = "BREVO_FROM_NAME"
+	EnvFrontendURL     = "FRONTEND_URL"
+	EnvAccessTokenTTL  = "ACCESS_TOKEN_TTL"
+	EnvRefreshTokenTTL = "REFRESH_TOKEN_TTL"
+	EnvJWTKeysDir      = "JWT_KEYS_DIR"
+	EnvJWTActiveKID    = "JWT_ACTIVE_KID"
+
+	EnvLoginMaxFailedAttempts   = "LOGIN_MAX_FAILED_ATTEMPTS"
+	EnvLoginLockoutBase         = "LOGIN_LOCKOUT_BASE"
+	EnvLoginLockoutMax          = "LOGIN_LOCKOUT_MAX"
+	EnvLoginIPMaxFailedAttempts = "LOGIN_IP_MAX_FAILED_ATTEMPTS"
+	EnvLoginIPAttemptWindow     = "LOGIN_IP_ATTEMPT_WINDOW"
+	EnvLoginAttemptRetention    = "LOGIN_ATTEMPT_RETENTION"
+	EnvTrustedProxies           = "TRUSTED_PROXIES"
+
+	EnvRequireAdminTwoFactor = "REQUIRE_ADMIN_2FA"
+	EnvInvitationTTL         = "INVITATION_TTL"
+	EnvImpersonationTTL      = "IMPERSONATION_TTL"
+
+	EnvOIDCIssuerURL            = "OIDC_ISSUER_URL"
+	EnvOIDCClientID             = "OIDC_CLIENT_ID"
+	EnvOIDCClientSecret         = "OIDC_CLIENT_SECRET"
+	EnvOIDCRedirectURL          = "OIDC_REDIRECT_URL"
+	EnvOIDCScopes               = "OIDC_SCOPES"
+	EnvOIDCAutoProvisionDomains = "OIDC_AUTO_PROVISION_DOMAINS"
+
+	EnvSessionCookies     = "SESSION_COOKIES"
+	EnvCookieDomain       = "COOKIE_DOMAIN"
+	EnvCookieSecure       = "COOKIE_SECURE"
+	EnvCookieSameSite     = "COOKIE_SAMESITE"
+	EnvCORSAllowedOrigins = "CORS_ALLOWED_ORIGINS"
+	EnvCSRFSecret         = "CSRF_SECRET"
+
+	EnvPasswordHashAlgorithm = "PASSWORD_HASH_ALGORITHM"
+	EnvBcryptCost            = "BCRYPT_COST"
+	EnvArgon2MemoryKiB       = "ARGON2_MEMORY_KIB"
+	EnvArgon2Iterations      = "ARGON2_ITERATIONS"
+	EnvArgon2Parallelism     = "ARGON2_PARALLELISM"
+
+	EnvPasswordMinLength      = "PASSWORD_MIN_LENGTH"
+	EnvPasswordMaxLength      = "PASSWORD_MAX_LENGTH"
+	EnvPasswordRequireUpper   = "PASSWORD_REQUIRE_UPPERCASE"
+	EnvPasswordRequireLower   = "PASSWORD_REQUIRE_LOWERCASE"
+	EnvPasswordRequireDigit   = "PASSWORD_REQUIRE_DIGIT"
+	EnvPasswordRequireSpecial = "PASSWORD_REQUIRE_SPECIAL"
+	EnvPasswordMaxAge         = "PASSWORD_MAX_AGE"
+	EnvPasswordHistoryDepth   = "PASSWORD_HISTORY_DEPTH"
+	EnvPasswordForbiddenWords = "PASSWORD_FORBIDDEN_WORDS"
+	EnvPasswordBreachCheck    = "PASSWORD_BREACH_CHECK"
+	EnvPasswordBreachFile     = "PASSWORD_BREACH_FILE"
+
+	EnvRateLimitAuth   = "RATE_LIMIT_AUTH"
+	EnvRateLimitAdmin  = "RATE_LIMIT_ADMIN"
+	EnvRateLimitMe     = "RATE_LIMIT_ME"
+	EnvRateLimitPublic = "RATE_LIMIT_PUBLIC"
 
 	// Constantes para campos de logging
 	LogFieldFromEmail  = "from_email"
@@ -219,6 +477,8 @@ const (
 	WelcomeEmailSubject           = "¡Bienvenido a APPFE Lima!"
 	PasswordResetEmailSubject     = "Restablecer Contraseña - APPFE Lima"
 	EmailValidationSubject        = "Verificar Email - APPFE Lima"
+	AccountLockedEmailSubject     = "Cuenta Bloqueada Temporalmente - APPFE Lima"
+	MagicLinkEmailSubject         = "Tu enlace de acceso - APPFE Lima"
 	ErrTemplateNotFound           = "template not found: %s"
 	ErrTemplateParamsRequired     = "template parameters are required"
 )
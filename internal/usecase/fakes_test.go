@@ -0,0 +1,763 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+)
+
+// Los fakes embeben la interfaz que implementan: un método no sobrescrito entra en pánico si un test lo usa,
+// lo que deja claro qué parte del repositorio ejercita cada caso.
+
+type fakeUnitOfWork struct {
+	ui.UnitOfWork
+
+	users           *fakeUserRepository
+	refreshTokens   *fakeRefreshTokenRepository
+	sessions        *fakeSessionRepository
+	revokedTokens   *fakeRevokedTokenRepository
+	passwordHistory *fakePasswordHistoryRepository
+	roles           *fakeRoleRepository
+	userTokens      *fakeUserTokenRepository
+	lockouts        *fakeAccountLockoutRepository
+	loginAttempts   *fakeLoginAttemptRepository
+	twoFactor       *fakeTwoFactorRepository
+	oidcStates      *fakeOIDCLoginStateRepository
+	oidcLinks       *fakeOIDCLinkRepository
+	apiKeys         *fakeAPIKeyRepository
+
+	commits int
+}
+
+func newFakeUnitOfWork() *fakeUnitOfWork {
+	return &fakeUnitOfWork{
+		users:           &fakeUserRepository{users: make(map[string]*domain.User)},
+		refreshTokens:   &fakeRefreshTokenRepository{},
+		sessions:        &fakeSessionRepository{sessions: make(map[string]*domain.Session)},
+		revokedTokens:   &fakeRevokedTokenRepository{},
+		passwordHistory: &fakePasswordHistoryRepository{},
+		roles: &fakeRoleRepository{permissions: map[string][]string{
+			domain.UserRole:  {},
+			domain.AdminRole: domain.Permissions,
+		}},
+		userTokens:    &fakeUserTokenRepository{},
+		lockouts:      &fakeAccountLockoutRepository{},
+		loginAttempts: &fakeLoginAttemptRepository{},
+		twoFactor:     &fakeTwoFactorRepository{},
+		oidcStates:    &fakeOIDCLoginStateRepository{states: make(map[string]*domain.OIDCLoginState)},
+		oidcLinks:     &fakeOIDCLinkRepository{},
+		apiKeys:       &fakeAPIKeyRepository{keys: make(map[string]*domain.APIKey)},
+	}
+}
+
+func (u *fakeUnitOfWork) Commit() error   { u.commits++; return nil }
+func (u *fakeUnitOfWork) Rollback() error { return nil }
+
+func (u *fakeUnitOfWork) UserRepository() ui.UserRepository                 { return u.users }
+func (u *fakeUnitOfWork) RefreshTokenRepository() ui.RefreshTokenRepository { return u.refreshTokens }
+func (u *fakeUnitOfWork) SessionRepository() ui.SessionRepository           { return u.sessions }
+func (u *fakeUnitOfWork) RevokedTokenRepository() ui.RevokedTokenRepository { return u.revokedTokens }
+func (u *fakeUnitOfWork) RoleRepository() ui.RoleRepository                 { return u.roles }
+func (u *fakeUnitOfWork) UserTokenRepository() ui.UserTokenRepository       { return u.userTokens }
+func (u *fakeUnitOfWork) AccountLockoutRepository() ui.AccountLockoutRepository {
+	return u.lockouts
+}
+func (u *fakeUnitOfWork) PasswordHistoryRepository() ui.PasswordHistoryRepository {
+	return u.passwordHistory
+}
+func (u *fakeUnitOfWork) LoginAttemptRepository() ui.LoginAttemptRepository {
+	return u.loginAttempts
+}
+func (u *fakeUnitOfWork) TwoFactorRepository() ui.TwoFactorRepository { return u.twoFactor }
+func (u *fakeUnitOfWork) OIDCLoginStateRepository() ui.OIDCLoginStateRepository {
+	return u.oidcStates
+}
+func (u *fakeUnitOfWork) OIDCLinkRepository() ui.OIDCLinkRepository { return u.oidcLinks }
+func (u *fakeUnitOfWork) APIKeyRepository() ui.APIKeyRepository     { return u.apiKeys }
+
+// fakeUnitOfWorkFactory entrega siempre la misma unidad de trabajo para inspeccionar su estado al terminar
+type fakeUnitOfWorkFactory struct {
+	uow *fakeUnitOfWork
+}
+
+func (f fakeUnitOfWorkFactory) New(context.Context) (ui.UnitOfWork, error) { return f.uow, nil }
+
+type fakeUserRepository struct {
+	ui.UserRepository
+
+	users map[string]*domain.User
+}
+
+func (r *fakeUserRepository) Create(_ context.Context, user *domain.User) error {
+	user.ID = fmt.Sprintf("user-%d", len(r.users)+1)
+	clone := *user
+	r.users[user.ID] = &clone
+	return nil
+}
+
+func (r *fakeUserRepository) FindByEmail(_ context.Context, email string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			clone := *user
+			return &clone, nil
+		}
+	}
+	return nil, errors.New(dto.ErrNoRowsFound)
+}
+
+func (r *fakeUserRepository) GetByID(_ context.Context, id string) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errors.New(dto.ErrNoRowsFound)
+	}
+	clone := *user
+	return &clone, nil
+}
+
+func (r *fakeUserRepository) UpdateByID(_ context.Context, input ui.UpdateUserInput) error {
+	user, ok := r.users[input.GetID()]
+	if !ok {
+		return errors.New(dto.ErrNoRowsFound)
+	}
+
+	for field, value := range input.FieldsToUpdate() {
+		switch field {
+		case "password":
+			password := value.(string)
+			user.Password = &password
+			now := time.Now()
+			user.PasswordChangedAt = &now
+			user.TokenVersion++
+		case "role":
+			user.Role = value.(string)
+			user.TokenVersion++
+		case "status":
+			user.Status = value.(bool)
+		case "must_change_password":
+			user.MustChangePassword = value.(bool)
+		case "email_validated":
+			user.EmailValidated = value.(bool)
+		case "name":
+			user.Name = value.(string)
+		}
+	}
+
+	return nil
+}
+
+func (r *fakeUserRepository) Delete(_ context.Context, id string) error {
+	delete(r.users, id)
+	return nil
+}
+
+type fakeRoleRepository struct {
+	ui.RoleRepository
+
+	permissions map[string][]string
+	lookups     int
+}
+
+func (r *fakeRoleRepository) GetByName(_ context.Context, name string) (*domain.Role, error) {
+	permissions, ok := r.permissions[name]
+	if !ok {
+		return nil, errors.New(dto.ErrNoRowsFound)
+	}
+	return &domain.Role{Name: name, Permissions: permissions}, nil
+}
+
+func (r *fakeRoleRepository) Update(_ context.Context, role *domain.Role) error {
+	r.permissions[role.Name] = role.Permissions
+	return nil
+}
+
+// GetPermissions retorna una lista vacía para roles inexistentes, igual que la consulta real
+func (r *fakeRoleRepository) GetPermissions(_ context.Context, name string) ([]string, error) {
+	r.lookups++
+	return r.permissions[name], nil
+}
+
+type fakeUserTokenRepository struct {
+	ui.UserTokenRepository
+
+	tokens []*domain.UserToken
+}
+
+func (r *fakeUserTokenRepository) Create(_ context.Context, token *domain.UserToken) error {
+	token.ID = fmt.Sprintf("token-%d", len(r.tokens)+1)
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *fakeUserTokenRepository) FindByHash(_ context.Context, purpose, tokenHash string) (*domain.UserToken, error) {
+	for _, token := range r.tokens {
+		if token.Purpose == purpose && token.TokenHash == tokenHash {
+			return token, nil
+		}
+	}
+	return nil, errors.New(dto.ErrNoRowsFound)
+}
+
+func (r *fakeUserTokenRepository) MarkUsed(_ context.Context, id string) error {
+	for _, token := range r.tokens {
+		if token.ID == id {
+			now := time.Now()
+			token.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeUserTokenRepository) InvalidateByUser(_ context.Context, userID, purpose string) error {
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt == nil {
+			now := time.Now()
+			token.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeUserTokenRepository) CountCreatedSince(_ context.Context, userID, purpose string, since time.Time) (int, error) {
+	count := 0
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.Purpose == purpose && !token.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// active retorna los tokens sin usar del propósito indicado
+func (r *fakeUserTokenRepository) active(purpose string) []*domain.UserToken {
+	var active []*domain.UserToken
+	for _, token := range r.tokens {
+		if token.Purpose == purpose && token.UsedAt == nil {
+			active = append(active, token)
+		}
+	}
+	return active
+}
+
+type fakeAccountLockoutRepository struct {
+	ui.AccountLockoutRepository
+
+	lockouts map[string]*domain.AccountLockout
+	resets   []string
+}
+
+func (r *fakeAccountLockoutRepository) Get(_ context.Context, userID string) (*domain.AccountLockout, error) {
+	if lockout, ok := r.lockouts[userID]; ok {
+		clone := *lockout
+		return &clone, nil
+	}
+	return &domain.AccountLockout{UserID: userID}, nil
+}
+
+func (r *fakeAccountLockoutRepository) Save(_ context.Context, lockout *domain.AccountLockout) error {
+	if r.lockouts == nil {
+		r.lockouts = map[string]*domain.AccountLockout{}
+	}
+	clone := *lockout
+	r.lockouts[lockout.UserID] = &clone
+	return nil
+}
+
+func (r *fakeAccountLockoutRepository) Reset(_ context.Context, userID string) error {
+	r.resets = append(r.resets, userID)
+	delete(r.lockouts, userID)
+	return nil
+}
+
+type fakeRefreshTokenRepository struct {
+	ui.RefreshTokenRepository
+
+	tokens          []*domain.RefreshToken
+	revokedUsers    []string
+	revokedFamilies []string
+}
+
+func (r *fakeRefreshTokenRepository) Create(_ context.Context, token *domain.RefreshToken) error {
+	token.ID = fmt.Sprintf("refresh-%d", len(r.tokens)+1)
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) FindByHash(_ context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			clone := *token
+			return &clone, nil
+		}
+	}
+	return nil, errors.New(dto.ErrNoRowsFound)
+}
+
+func (r *fakeRefreshTokenRepository) MarkUsed(_ context.Context, id string) error {
+	for _, token := range r.tokens {
+		if token.ID == id {
+			now := time.Now()
+			token.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) RevokeByUser(_ context.Context, userID string) error {
+	r.revokedUsers = append(r.revokedUsers, userID)
+	r.revoke(func(token *domain.RefreshToken) bool { return token.UserID == userID })
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) RevokeFamily(_ context.Context, familyID string) error {
+	r.revokedFamilies = append(r.revokedFamilies, familyID)
+	r.revoke(func(token *domain.RefreshToken) bool { return token.FamilyID == familyID })
+	return nil
+}
+
+func (r *fakeRefreshTokenRepository) revoke(match func(*domain.RefreshToken) bool) {
+	now := time.Now()
+	for _, token := range r.tokens {
+		if match(token) && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+}
+
+type fakeSessionRepository struct {
+	ui.SessionRepository
+
+	sessions     map[string]*domain.Session
+	revokedUsers []string
+	touches      int
+}
+
+func (r *fakeSessionRepository) Create(_ context.Context, session *domain.Session) error {
+	r.sessions[session.ID] = session
+	return nil
+}
+
+func (r *fakeSessionRepository) FindByID(_ context.Context, id string) (*domain.Session, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, errors.New(dto.ErrNoRowsFound)
+	}
+	clone := *session
+	return &clone, nil
+}
+
+func (r *fakeSessionRepository) ListActiveByUser(_ context.Context, userID string, now time.Time) ([]*domain.Session, error) {
+	var sessions []*domain.Session
+	for _, session := range r.sessions {
+		if session.UserID == userID && !session.IsRevoked() && session.ExpiresAt.After(now) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (r *fakeSessionRepository) Touch(_ context.Context, id string, now time.Time) (bool, error) {
+	r.touches++
+	session, ok := r.sessions[id]
+	if !ok || session.IsRevoked() || !session.ExpiresAt.After(now) {
+		return false, nil
+	}
+	session.LastSeenAt = now
+	return true, nil
+}
+
+func (r *fakeSessionRepository) Extend(_ context.Context, id string, expiresAt, now time.Time) (bool, error) {
+	session, ok := r.sessions[id]
+	if !ok || session.IsRevoked() {
+		return false, nil
+	}
+	session.ExpiresAt = expiresAt
+	session.LastSeenAt = now
+	return true, nil
+}
+
+func (r *fakeSessionRepository) Revoke(_ context.Context, id string, now time.Time) error {
+	r.sessions[id].RevokedAt = &now
+	return nil
+}
+
+func (r *fakeSessionRepository) RevokeByUser(_ context.Context, userID string, now time.Time) error {
+	r.revokedUsers = append(r.revokedUsers, userID)
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+type fakeRevokedTokenRepository struct {
+	ui.RevokedTokenRepository
+
+	tokens []*domain.RevokedToken
+}
+
+func (r *fakeRevokedTokenRepository) Create(_ context.Context, token *domain.RevokedToken) error {
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *fakeRevokedTokenRepository) ListActive(_ context.Context, now time.Time) ([]*domain.RevokedToken, error) {
+	var active []*domain.RevokedToken
+	for _, token := range r.tokens {
+		if token.ExpiresAt.After(now) {
+			active = append(active, token)
+		}
+	}
+	return active, nil
+}
+
+func (r *fakeRevokedTokenRepository) DeleteExpired(_ context.Context, now time.Time) error {
+	r.tokens = slices.DeleteFunc(r.tokens, func(token *domain.RevokedToken) bool {
+		return !token.ExpiresAt.After(now)
+	})
+	return nil
+}
+
+type fakeLoginAttemptRepository struct {
+	ui.LoginAttemptRepository
+
+	attempts []*domain.LoginAttempt
+}
+
+func (r *fakeLoginAttemptRepository) Create(_ context.Context, attempt *domain.LoginAttempt) error {
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+func (r *fakeLoginAttemptRepository) CountFailedByIP(_ context.Context, ip string, since time.Time) (int, error) {
+	failed := 0
+	for _, attempt := range r.attempts {
+		if attempt.IP == ip && !attempt.Success && !attempt.CreatedAt.Before(since) {
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+// fakeTwoFactorRepository guarda la configuración TOTP y los hashes de los códigos de recuperación
+// pendientes; los usuarios sin configuración responden como si no tuvieran 2FA
+type fakeTwoFactorRepository struct {
+	ui.TwoFactorRepository
+
+	factors       map[string]*domain.TwoFactor
+	recoveryCodes map[string][]string
+}
+
+func (r *fakeTwoFactorRepository) Get(_ context.Context, userID string) (*domain.TwoFactor, error) {
+	twoFactor, ok := r.factors[userID]
+	if !ok {
+		return nil, errors.New(dto.ErrNoRowsFound)
+	}
+	clone := *twoFactor
+	return &clone, nil
+}
+
+func (r *fakeTwoFactorRepository) Save(_ context.Context, twoFactor *domain.TwoFactor) error {
+	if r.factors == nil {
+		r.factors = make(map[string]*domain.TwoFactor)
+	}
+	clone := *twoFactor
+	r.factors[twoFactor.UserID] = &clone
+	return nil
+}
+
+func (r *fakeTwoFactorRepository) UseRecoveryCode(_ context.Context, userID, codeHash string) (bool, error) {
+	codes := r.recoveryCodes[userID]
+	i := slices.Index(codes, codeHash)
+	if i < 0 {
+		return false, nil
+	}
+	r.recoveryCodes[userID] = slices.Delete(codes, i, i+1)
+	return true, nil
+}
+
+type fakeOIDCLoginStateRepository struct {
+	ui.OIDCLoginStateRepository
+
+	states map[string]*domain.OIDCLoginState
+}
+
+func (r *fakeOIDCLoginStateRepository) Create(_ context.Context, state *domain.OIDCLoginState) error {
+	r.states[state.StateHash] = state
+	return nil
+}
+
+func (r *fakeOIDCLoginStateRepository) Consume(_ context.Context, stateHash string) (*domain.OIDCLoginState, error) {
+	state, ok := r.states[stateHash]
+	if !ok {
+		return nil, errors.New(dto.ErrNoRowsFound)
+	}
+	delete(r.states, stateHash)
+	return state, nil
+}
+
+func (r *fakeOIDCLoginStateRepository) DeleteExpired(context.Context, time.Time) error { return nil }
+
+type fakeOIDCLinkRepository struct {
+	ui.OIDCLinkRepository
+
+	links []*domain.OIDCLink
+}
+
+func (r *fakeOIDCLinkRepository) Create(_ context.Context, link *domain.OIDCLink) error {
+	r.links = append(r.links, link)
+	return nil
+}
+
+func (r *fakeOIDCLinkRepository) FindBySubject(_ context.Context, issuer, subject string) (*domain.OIDCLink, error) {
+	for _, link := range r.links {
+		if link.Issuer == issuer && link.Subject == subject {
+			return link, nil
+		}
+	}
+	return nil, errors.New(dto.ErrNoRowsFound)
+}
+
+func (r *fakeOIDCLinkRepository) FindByUser(_ context.Context, userID, issuer string) (*domain.OIDCLink, error) {
+	for _, link := range r.links {
+		if link.UserID == userID && link.Issuer == issuer {
+			return link, nil
+		}
+	}
+	return nil, errors.New(dto.ErrNoRowsFound)
+}
+
+// fakeAPIKeyRepository cuenta las búsquedas por hash y los registros de uso para verificar la caché
+type fakeAPIKeyRepository struct {
+	ui.APIKeyRepository
+
+	keys    map[string]*domain.APIKey
+	lookups int
+	touches int
+}
+
+func (r *fakeAPIKeyRepository) Create(_ context.Context, key *domain.APIKey) error {
+	key.ID = fmt.Sprintf("key-%d", len(r.keys)+1)
+	r.keys[key.ID] = key
+	return nil
+}
+
+func (r *fakeAPIKeyRepository) FindByID(_ context.Context, id string) (*domain.APIKey, error) {
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, errors.New(dto.ErrNoRowsFound)
+	}
+	clone := *key
+	return &clone, nil
+}
+
+func (r *fakeAPIKeyRepository) FindByHash(_ context.Context, keyHash string) (*domain.APIKey, error) {
+	r.lookups++
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			clone := *key
+			return &clone, nil
+		}
+	}
+	return nil, errors.New(dto.ErrNoRowsFound)
+}
+
+func (r *fakeAPIKeyRepository) ListByOwner(_ context.Context, ownerID string) ([]*domain.APIKey, error) {
+	var keys []*domain.APIKey
+	for _, key := range r.keys {
+		if key.CreatedBy == ownerID {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *fakeAPIKeyRepository) Revoke(_ context.Context, id string, at time.Time) error {
+	r.keys[id].RevokedAt = &at
+	return nil
+}
+
+func (r *fakeAPIKeyRepository) TouchLastUsed(_ context.Context, id string, now, _ time.Time) error {
+	r.touches++
+	r.keys[id].LastUsedAt = &now
+	return nil
+}
+
+type fakePasswordHistoryRepository struct {
+	ui.PasswordHistoryRepository
+
+	hashes map[string][]string
+}
+
+func (r *fakePasswordHistoryRepository) Add(_ context.Context, userID, passwordHash string) error {
+	if r.hashes == nil {
+		r.hashes = make(map[string][]string)
+	}
+	r.hashes[userID] = append([]string{passwordHash}, r.hashes[userID]...)
+	return nil
+}
+
+func (r *fakePasswordHistoryRepository) ListRecent(_ context.Context, userID string, limit int) ([]string, error) {
+	hashes := r.hashes[userID]
+	return hashes[:min(limit, len(hashes))], nil
+}
+
+func (r *fakePasswordHistoryRepository) Prune(_ context.Context, userID string, keep int) error {
+	hashes := r.hashes[userID]
+	r.hashes[userID] = hashes[:min(keep, len(hashes))]
+	return nil
+}
+
+// fakeTokenGenerator emite tokens correlativos cuyo hash es reconocible en los tests
+type fakeTokenGenerator struct {
+	issued *int
+}
+
+func newFakeTokenGenerator() fakeTokenGenerator { return fakeTokenGenerator{issued: new(int)} }
+
+func (g fakeTokenGenerator) Generate() (string, string, error) {
+	*g.issued++
+	token := fmt.Sprintf("plain-token-%d", *g.issued)
+	return token, g.Hash(token), nil
+}
+
+func (fakeTokenGenerator) Hash(token string) string { return "sha:" + token }
+
+// fakeJWTService guarda los claims de cada token emitido para que ValidateToken los devuelva tal cual
+type fakeJWTService struct {
+	issued map[string]*domain.TokenClaims
+}
+
+func newFakeJWTService() *fakeJWTService {
+	return &fakeJWTService{issued: make(map[string]*domain.TokenClaims)}
+}
+
+func (j *fakeJWTService) GenerateToken(user domain.User, opts domain.TokenOptions) (string, error) {
+	ttl := opts.TTL
+	if ttl == 0 {
+		ttl = time.Hour
+	}
+
+	token := fmt.Sprintf("jwt-%d", len(j.issued)+1)
+	j.issued[token] = &domain.TokenClaims{
+		TokenID:      token,
+		UserID:       user.ID,
+		Email:        user.Email,
+		Role:         user.Role,
+		TokenVersion: user.TokenVersion,
+		TwoFactor:    opts.TwoFactor,
+		ActorID:      opts.ActorID,
+		SessionID:    opts.SessionID,
+		IssuedAt:     time.Now().Truncate(time.Second),
+		ExpiresAt:    time.Now().Add(ttl).Truncate(time.Second),
+	}
+	return token, nil
+}
+
+func (j *fakeJWTService) ValidateToken(token string) (*domain.TokenClaims, error) {
+	claims, ok := j.issued[token]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+const fakeHashPrefix = "hashed:"
+
+// fakeHasher guarda las contraseñas con un prefijo para que los tests puedan reconocer el hash esperado
+type fakeHasher struct {
+	hashErr error
+}
+
+func (h fakeHasher) Hash(password string) (string, error) {
+	if h.hashErr != nil {
+		return "", h.hashErr
+	}
+	return fakeHashPrefix + password, nil
+}
+
+func (fakeHasher) Verify(hashedPassword, password string) error {
+	if hashedPassword != fakeHashPrefix+password {
+		return errors.New("password mismatch")
+	}
+	return nil
+}
+
+func (fakeHasher) NeedsRehash(hashedPassword string) bool {
+	return !strings.HasPrefix(hashedPassword, fakeHashPrefix)
+}
+
+// fakeTemplateService devuelve el enlace como contenido y lo guarda para que los tests lo inspeccionen
+type fakeTemplateService struct {
+	ui.TemplateService
+
+	links       []string
+	lockNotices []string
+}
+
+func (t *fakeTemplateService) RenderMagicLinkEmail(_, loginLink string) (string, error) {
+	t.links = append(t.links, loginLink)
+	return loginLink, nil
+}
+
+func (t *fakeTemplateService) RenderEmailValidation(_, validationLink string) (string, error) {
+	t.links = append(t.links, validationLink)
+	return validationLink, nil
+}
+
+func (t *fakeTemplateService) RenderAccountLockedEmail(_, lockedUntil string) (string, error) {
+	t.lockNotices = append(t.lockNotices, lockedUntil)
+	return lockedUntil, nil
+}
+
+// fakeTwoFactorProvider acepta los códigos de steps con su paso de tiempo, como si estuvieran dentro
+// de la ventana de validación; cualquier otro código queda fuera de ella
+type fakeTwoFactorProvider struct {
+	ui.TwoFactorProvider
+
+	steps map[string]int64
+}
+
+func (p fakeTwoFactorProvider) Validate(_, code string, _ time.Time) (int64, bool) {
+	step, ok := p.steps[code]
+	return step, ok
+}
+
+func (fakeTwoFactorProvider) NormalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+// fakeMessagingService descarta los correos; sendEmailAsync lo invoca desde otra goroutine
+type fakeMessagingService struct{}
+
+func (fakeMessagingService) SendEmail(context.Context, string, string, string) error { return nil }
+
+type fakeAuditService struct {
+	interfaces.AuditService
+
+	events []*domain.AuditEvent
+}
+
+func (a *fakeAuditService) Record(_ context.Context, _ ui.UnitOfWork, event *domain.AuditEvent) error {
+	a.events = append(a.events, event)
+	return nil
+}
+
+// eventTypes devuelve los tipos registrados en orden para comparar con lo esperado
+func (a *fakeAuditService) eventTypes() []string {
+	types := make([]string, len(a.events))
+	for i, event := range a.events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+func stringPtr(value string) *string { return &value }
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+type APIKeyService interface {
+	Create(ctx context.Context, ownerID string, input dto.CreateAPIKeyInput) (*dto.APIKeyCreatedResponse, error)
+	List(ctx context.Context, ownerID string) ([]*domain.APIKey, error)
+	Revoke(ctx context.Context, ownerID, id string) error
+
+	// Authenticate valida la clave presentada y retorna la clave junto a su dueño, registrando su uso.
+	// El resultado se cachea brevemente, por lo que una revocación en otra instancia tarda hasta el TTL.
+	Authenticate(ctx context.Context, key string) (*domain.APIKey, *domain.User, error)
+}
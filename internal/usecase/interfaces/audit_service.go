@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+)
+
+type AuditService interface {
+	// Record guarda el evento en la transacción del cambio que describe, de modo que ambos se confirman juntos
+	Record(ctx context.Context, uow ui.UnitOfWork, event *domain.AuditEvent) error
+	List(ctx context.Context, filter domain.AuditFilter, pagination *domain.Pagination) (*domain.PaginatedResult[*domain.AuditEvent], error)
+}
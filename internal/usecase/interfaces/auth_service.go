@@ -1,8 +1,25 @@
 package interfaces
 
-import "github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+import (
+	"context"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
 
 type AuthService interface {
-	Login(input dto.AuthLoginInput) (*dto.AuthLoginResponse, error)
-	SignInWithToken(input dto.AuthTokenSignInInput) (*dto.AuthLoginResponse, error)
+	Login(ctx context.Context, input dto.AuthLoginInput) (*dto.AuthLoginResponse, error)
+	VerifyTwoFactor(ctx context.Context, input dto.AuthTwoFactorLoginInput) (*dto.AuthLoginResponse, error)
+	SignInWithToken(ctx context.Context, input dto.AuthTokenSignInInput) (*dto.AuthLoginResponse, error)
+	Refresh(ctx context.Context, input dto.AuthRefreshInput) (*dto.AuthLoginResponse, error)
+	Logout(ctx context.Context, input dto.AuthLogoutInput) error
+	ForgotPassword(ctx context.Context, input dto.AuthForgotPasswordInput) error
+	ResetPassword(ctx context.Context, input dto.AuthResetPasswordInput) error
+	AcceptInvitation(ctx context.Context, input dto.AuthAcceptInvitationInput) error
+	VerifyEmail(ctx context.Context, input dto.AuthVerifyEmailInput) error
+	ResendVerificationEmail(ctx context.Context, input dto.AuthResendVerificationInput) error
+	RequestMagicLink(ctx context.Context, input dto.AuthMagicLinkInput) error
+	ExchangeMagicLink(ctx context.Context, input dto.AuthMagicLinkExchangeInput) (*dto.AuthLoginResponse, error)
+	StartOIDCLogin(ctx context.Context) (*dto.OIDCAuthorizationResponse, error)
+	CompleteOIDCLogin(ctx context.Context, input dto.AuthOIDCCallbackInput) (*dto.AuthLoginResponse, error)
+	LinkOIDCIdentity(ctx context.Context, userID string, input dto.AuthOIDCCallbackInput) error
 }
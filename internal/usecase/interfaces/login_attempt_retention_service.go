@@ -0,0 +1,10 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+type LoginAttemptRetentionService interface {
+	Start(ctx context.Context, pruneInterval time.Duration) error
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import "context"
+
+type PermissionService interface {
+	HasPermission(ctx context.Context, role, permission string) (bool, error)
+	// IsAdmin indica si el rol tiene alguno de los domain.AdminPermissions
+	IsAdmin(ctx context.Context, role string) (bool, error)
+	// Invalidate descarta los permisos cacheados de un rol tras modificarlo
+	Invalidate(role string)
+}
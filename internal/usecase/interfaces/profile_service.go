@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+// ProfileService agrupa las operaciones que un usuario autenticado realiza sobre su propia cuenta
+type ProfileService interface {
+	Get(ctx context.Context, userID string) (*domain.User, error)
+	Update(ctx context.Context, userID string, input dto.UpdateProfileInput) (*domain.User, error)
+	ChangePassword(ctx context.Context, userID string, input dto.ChangePasswordInput) error
+	ListSessions(ctx context.Context, userID string) ([]*domain.Session, error)
+}
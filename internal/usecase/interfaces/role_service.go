@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+type RoleService interface {
+	GetAll(ctx context.Context) ([]*domain.Role, error)
+	GetByName(ctx context.Context, name string) (*domain.Role, error)
+	Create(ctx context.Context, input dto.CreateRoleInput) (*domain.Role, error)
+	Update(ctx context.Context, name string, input dto.UpdateRoleInput) (*domain.Role, error)
+	Delete(ctx context.Context, name string) error
+}
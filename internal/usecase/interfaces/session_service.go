@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+type SessionService interface {
+	// IsActive indica si la sesión no fue terminada y registra la actividad como last_seen_at
+	IsActive(ctx context.Context, sessionID string) (bool, error)
+	ListByUser(ctx context.Context, userID string) ([]*domain.Session, error)
+	// Terminate cierra una sesión del usuario; sus tokens dejan de aceptarse
+	Terminate(ctx context.Context, userID, sessionID string) error
+}
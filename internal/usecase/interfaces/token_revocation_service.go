@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+type TokenRevocationService interface {
+	Revoke(ctx context.Context, claims *domain.TokenClaims) error
+	IsRevoked(tokenID string) bool
+	Start(ctx context.Context, syncInterval time.Duration) error
+}
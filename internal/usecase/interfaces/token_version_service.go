@@ -0,0 +1,7 @@
+package interfaces
+
+import "context"
+
+type TokenVersionService interface {
+	IsCurrent(ctx context.Context, userID string, tokenVersion int) (bool, error)
+}
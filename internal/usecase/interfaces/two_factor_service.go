@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+type TwoFactorService interface {
+	Enroll(ctx context.Context, userID string) (*dto.TwoFactorEnrollResponse, error)
+	Confirm(ctx context.Context, userID string, input dto.TwoFactorCodeInput) (*dto.TwoFactorRecoveryCodesResponse, error)
+	Disable(ctx context.Context, userID string, input dto.TwoFactorCodeInput) error
+	RegenerateRecoveryCodes(ctx context.Context, userID string, input dto.TwoFactorCodeInput) (*dto.TwoFactorRecoveryCodesResponse, error)
+}
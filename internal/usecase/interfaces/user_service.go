@@ -4,15 +4,19 @@ import (
 	"context"
 
 	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
-	"github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
 )
 
 type UserService interface {
-	Create(ctx context.Context, user *domain.User) error
-	UpdateByID(ctx context.Context, input interfaces.UpdateUserInput) error
-	GetAll(ctx context.Context, pagination *domain.Pagination) (*domain.PaginatedResult[*domain.User], error)
+	Create(ctx context.Context, user *domain.User) (*dto.UserInvitation, error)
+	ResendInvitation(ctx context.Context, id string) (*dto.UserInvitation, error)
+	UpdateByID(ctx context.Context, input *dto.UpdateUserInput) error
+	GetAll(ctx context.Context, filter domain.UserFilter, pagination *domain.Pagination) (*domain.PaginatedResult[*domain.User], error)
 	GetByID(ctx context.Context, id string) (*domain.User, error)
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
 	Delete(ctx context.Context, id string) error
+	Unlock(ctx context.Context, id string) error
+	ForcePasswordReset(ctx context.Context, id string, input dto.AdminPasswordResetInput) (*dto.AdminPasswordResetResponse, error)
+	Impersonate(ctx context.Context, actorID, id string) (*dto.ImpersonationResponse, error)
 	CreateInitialAdmin(ctx context.Context) error
 }
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/pkg/logger"
+)
+
+const DefaultLoginAttemptPruneInterval = time.Hour
+
+// loginAttemptRetentionService elimina periódicamente los intentos de login más antiguos que la
+// retención configurada. La retención nunca baja de la ventana del límite por IP para no alterar su conteo.
+type loginAttemptRetentionService struct {
+	uowFactory ui.UnitOfWorkFactory
+	retention  time.Duration
+}
+
+func NewLoginAttemptRetentionService(uowFactory ui.UnitOfWorkFactory, config AuthConfig) interfaces.LoginAttemptRetentionService {
+	return &loginAttemptRetentionService{
+		uowFactory: uowFactory,
+		retention:  max(config.LoginAttemptRetention, config.IPAttemptWindow),
+	}
+}
+
+// Start elimina los intentos vencidos y lanza la limpieza periódica hasta que ctx se cancele; un error en
+// la primera limpieza se retorna sin detener la periódica
+func (s *loginAttemptRetentionService) Start(ctx context.Context, pruneInterval time.Duration) error {
+	err := s.prune(ctx)
+
+	go func() {
+		ticker := time.NewTicker(pruneInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.prune(ctx); err != nil {
+					logger.Warn(ctx, dto.MsgLoginAttemptPruneFailed, logger.Error("error", err))
+				}
+			}
+		}
+	}()
+
+	return err
+}
+
+func (s *loginAttemptRetentionService) prune(ctx context.Context) error {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return err
+	}
+	defer uow.Rollback()
+
+	if err := uow.LoginAttemptRepository().DeleteBefore(ctx, time.Now().Add(-s.retention)); err != nil {
+		return err
+	}
+
+	return uow.Commit()
+}
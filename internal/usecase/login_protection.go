@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/pkg/logger"
+)
+
+// emailDateLayout es el formato de fecha mostrado en los correos (bloqueo, vencimiento de invitaciones)
+const emailDateLayout = "02/01/2006 15:04"
+
+// dummyPasswordValue se hashea una sola vez para igualar el tiempo de respuesta cuando el correo no existe
+const dummyPasswordValue = "appfe-dummy-password"
+
+// checkIPThrottle rechaza el intento si la IP acumula demasiados fallos dentro de la ventana configurada
+func (s *AuthService) checkIPThrottle(ctx context.Context, repo interfaces.LoginAttemptRepository, ip string, now time.Time) error {
+	if ip == "" {
+		return nil
+	}
+
+	failed, err := repo.CountFailedByIP(ctx, ip, now.Add(-s.config.IPAttemptWindow))
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if failed >= s.config.IPMaxFailedAttempts {
+		logger.Warn(ctx, dto.MsgLoginIPThrottled, logger.String("ip", ip))
+		return errors.New(dto.ErrTooManyLoginAttempts)
+	}
+
+	return nil
+}
+
+// verifyPassword compara la contraseña con el hash del usuario. Si el usuario no existe o no tiene
+// contraseña se verifica contra un hash ficticio para no revelar la diferencia por tiempo de respuesta.
+func (s *AuthService) verifyPassword(user *domain.User, password string) bool {
+	if user == nil || user.Password == nil {
+		s.dummyHashOnce.Do(func() {
+			s.dummyHash, _ = s.passwordHasher.Hash(dummyPasswordValue)
+		})
+		if s.dummyHash != "" {
+			_ = s.passwordHasher.Verify(s.dummyHash, password)
+		}
+		return false
+	}
+
+	return s.passwordHasher.Verify(*user.Password, password) == nil
+}
+
+// registerFailedLogin suma un fallo a la cuenta y la bloquea al alcanzar el umbral.
+// Retorna la fecha de desbloqueo cuando este fallo provocó un bloqueo nuevo.
+func (s *AuthService) registerFailedLogin(ctx context.Context, repo interfaces.AccountLockoutRepository, lockout *domain.AccountLockout, now time.Time) (*time.Time, error) {
+	lockout.FailedCount++
+
+	var lockedUntil *time.Time
+	if lockout.FailedCount >= s.config.MaxFailedLoginAttempts {
+		until := now.Add(s.config.lockoutDuration(lockout.LockoutCount))
+		lockout.LockedUntil = &until
+		lockout.LockoutCount++
+		lockout.FailedCount = 0
+		lockedUntil = &until
+
+		logger.Warn(ctx, dto.MsgAccountLocked,
+			logger.String("user_id", lockout.UserID),
+			logger.Int("lockout_count", lockout.LockoutCount),
+			logger.String("locked_until", until.Format(time.RFC3339)),
+		)
+	}
+
+	if err := repo.Save(ctx, lockout); err != nil {
+		return nil, err
+	}
+
+	return lockedUntil, nil
+}
+
+// rejectLogin registra el intento fallido, confirma la transacción y retorna el error indicado
+func (s *AuthService) rejectLogin(ctx context.Context, uow interfaces.UnitOfWork, input dto.AuthLoginInput, userID *string, message string) error {
+	if err := s.recordLoginAttempt(ctx, uow.LoginAttemptRepository(), input, userID, false); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := s.audit.Record(ctx, uow, &domain.AuditEvent{
+		Type:       domain.AuditLoginFailed,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userID,
+		Details: map[string]any{
+			domain.AuditDetailEmail:  input.Email,
+			domain.AuditDetailReason: message,
+		},
+	}); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	return errors.New(message)
+}
+
+func (s *AuthService) recordLoginAttempt(ctx context.Context, repo interfaces.LoginAttemptRepository, input dto.AuthLoginInput, userID *string, success bool) error {
+	return repo.Create(ctx, &domain.LoginAttempt{
+		UserID:    userID,
+		Email:     input.Email,
+		IP:        input.IP,
+		Success:   success,
+		CreatedAt: s.now(),
+	})
+}
+
+// notifyAccountLocked avisa al usuario por correo que su cuenta fue bloqueada
+func (s *AuthService) notifyAccountLocked(ctx context.Context, user *domain.User, lockedUntil time.Time) {
+	if s.messagingService == nil || s.templateService == nil {
+		return
+	}
+
+	htmlContent, err := s.templateService.RenderAccountLockedEmail(user.Name, lockedUntil.Format(emailDateLayout))
+	if err != nil {
+		logger.LogError(ctx, dto.MsgAccountLockedEmailFailed, logger.Error("error", err))
+		return
+	}
+
+	sendEmailAsync(s.messagingService, user.Email, dto.AccountLockedEmailSubject, htmlContent, dto.MsgAccountLockedEmailFailed)
+}
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+const (
+	testLoginIP      = "203.0.113.10"
+	testOtherLoginIP = "198.51.100.20"
+	testLockoutBase  = 10 * time.Minute
+	testIPWindow     = 15 * time.Minute
+)
+
+// loginStep es un intento de login hecho después de adelantar el reloj
+type loginStep struct {
+	advance  time.Duration
+	email    string
+	password string
+	ip       string
+	wantErr  string
+}
+
+func (s loginStep) after(d time.Duration) loginStep {
+	s.advance = d
+	return s
+}
+
+func (s loginStep) from(ip string) loginStep {
+	s.ip = ip
+	return s
+}
+
+func TestAuthService_LoginProtection(t *testing.T) {
+	success := loginStep{email: "ana@appfe.com", password: testCurrentPassword}
+	wrongPassword := loginStep{email: "ana@appfe.com", password: "Incorrecta#1", wantErr: dto.ErrInvalidCredentials}
+	unknownEmail := loginStep{email: "nadie@appfe.com", password: testCurrentPassword, wantErr: dto.ErrInvalidCredentials}
+	locked := loginStep{email: "ana@appfe.com", password: testCurrentPassword, wantErr: dto.ErrAccountLocked}
+	throttled := loginStep{email: "ana@appfe.com", password: testCurrentPassword, wantErr: dto.ErrTooManyLoginAttempts}
+
+	repeat := func(step loginStep, n int) []loginStep {
+		steps := make([]loginStep, n)
+		for i := range steps {
+			steps[i] = step
+		}
+		return steps
+	}
+	join := func(groups ...[]loginStep) []loginStep {
+		steps := []loginStep{}
+		for _, group := range groups {
+			steps = append(steps, group...)
+		}
+		return steps
+	}
+
+	tests := []struct {
+		name             string
+		steps            []loginStep
+		wantFailedCount  int
+		wantLockoutCount int
+		wantLockedFor    time.Duration
+		wantNotices      int
+		wantResets       int
+	}{
+		{
+			name:            "failures below the threshold",
+			steps:           repeat(wrongPassword, 2),
+			wantFailedCount: 2,
+		},
+		{
+			name:       "success resets the failure counter",
+			steps:      join(repeat(wrongPassword, 2), []loginStep{success}, repeat(wrongPassword, 2), []loginStep{success}),
+			wantResets: 2,
+		},
+		{
+			name:             "threshold locks the account",
+			steps:            join(repeat(wrongPassword, 3), []loginStep{locked}),
+			wantLockoutCount: 1,
+			wantLockedFor:    testLockoutBase,
+			wantNotices:      1,
+		},
+		{
+			name:             "wrong password while locked stays generic and does not count",
+			steps:            join(repeat(wrongPassword, 3), repeat(wrongPassword, 2)),
+			wantLockoutCount: 1,
+			wantLockedFor:    testLockoutBase,
+			wantNotices:      1,
+		},
+		{
+			name:             "lock is still active just before it expires",
+			steps:            join(repeat(wrongPassword, 3), []loginStep{locked.after(testLockoutBase - time.Second)}),
+			wantLockoutCount: 1,
+			wantLockedFor:    time.Second,
+			wantNotices:      1,
+		},
+		{
+			name:        "success after the lock expires unlocks the account",
+			steps:       join(repeat(wrongPassword, 3), []loginStep{success.after(testLockoutBase)}),
+			wantNotices: 1,
+			wantResets:  1,
+		},
+		{
+			name:             "consecutive locks double their duration",
+			steps:            join(repeat(wrongPassword, 3), []loginStep{wrongPassword.after(testLockoutBase)}, repeat(wrongPassword, 2), []loginStep{locked.after(testLockoutBase)}),
+			wantLockoutCount: 2,
+			wantLockedFor:    testLockoutBase,
+			wantNotices:      2,
+		},
+		{
+			name: "backoff is capped at the maximum duration",
+			steps: join(
+				repeat(wrongPassword, 3),
+				[]loginStep{wrongPassword.after(testLockoutBase)}, repeat(wrongPassword, 2),
+				[]loginStep{wrongPassword.after(2 * testLockoutBase)}, repeat(wrongPassword, 2),
+			),
+			wantLockoutCount: 3,
+			wantLockedFor:    3 * testLockoutBase,
+			wantNotices:      3,
+		},
+		{
+			name:  "ip throttle rejects even the right password",
+			steps: join(repeat(unknownEmail, 10), []loginStep{throttled, success.from(testOtherLoginIP)}),
+		},
+		{
+			name:  "ip throttle window slides",
+			steps: join(repeat(unknownEmail, 10), []loginStep{throttled.after(testIPWindow - time.Second), success.after(2 * time.Second)}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, templates, service := newTestAuthService(t)
+			uow.users.users["user-1"].Password = stringPtr(fakeHashPrefix + testCurrentPassword)
+
+			service.config.MaxFailedLoginAttempts = 3
+			service.config.LockoutBaseDuration = testLockoutBase
+			service.config.LockoutMaxDuration = 3 * testLockoutBase
+			service.config.IPMaxFailedAttempts = 10
+			service.config.IPAttemptWindow = testIPWindow
+
+			clock := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+			service.now = func() time.Time { return clock }
+
+			for i, step := range tt.steps {
+				clock = clock.Add(step.advance)
+				ip := step.ip
+				if ip == "" {
+					ip = testLoginIP
+				}
+
+				response, err := service.Login(context.Background(), dto.AuthLoginInput{Email: step.email, Password: step.password, IP: ip})
+
+				if step.wantErr != "" {
+					if err == nil || err.Error() != step.wantErr {
+						t.Fatalf("step %d: Login() error = %v, want %q", i, err, step.wantErr)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("step %d: Login() error = %v", i, err)
+				}
+				if response.Token == "" || response.RefreshToken == "" {
+					t.Fatalf("step %d: Login() = %+v, want the session tokens", i, response)
+				}
+			}
+
+			lockout := &domain.AccountLockout{}
+			if saved, ok := uow.lockouts.lockouts["user-1"]; ok {
+				lockout = saved
+			}
+			if lockout.FailedCount != tt.wantFailedCount || lockout.LockoutCount != tt.wantLockoutCount {
+				t.Errorf("lockout = %d failures and %d locks, want %d and %d", lockout.FailedCount, lockout.LockoutCount, tt.wantFailedCount, tt.wantLockoutCount)
+			}
+
+			var lockedFor time.Duration
+			if lockout.IsLocked(clock) {
+				lockedFor = lockout.LockedUntil.Sub(clock)
+			}
+			if lockedFor != tt.wantLockedFor {
+				t.Errorf("locked for %v, want %v", lockedFor, tt.wantLockedFor)
+			}
+			if len(templates.lockNotices) != tt.wantNotices || len(uow.lockouts.resets) != tt.wantResets {
+				t.Errorf("lock notices = %d, resets = %d, want %d and %d", len(templates.lockNotices), len(uow.lockouts.resets), tt.wantNotices, tt.wantResets)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/pkg/logger"
+)
+
+// RequestMagicLink envía un enlace de inicio de sesión de un solo uso. Al igual que ForgotPassword,
+// no revela si el correo está registrado: para cuentas inexistentes o deshabilitadas retorna nil sin enviar nada.
+func (s *AuthService) RequestMagicLink(ctx context.Context, input dto.AuthMagicLinkInput) error {
+	if s.messagingService == nil || s.templateService == nil {
+		return errors.New(dto.ErrEmailServiceUnavailable)
+	}
+
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	user, err := uow.UserRepository().FindByEmail(ctx, input.Email)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			logger.Info(ctx, dto.MsgMagicLinkUnknownEmail, logger.String("email", input.Email))
+			return nil
+		}
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if !user.Status {
+		logger.Info(ctx, dto.MsgMagicLinkUnknownEmail, logger.String("email", input.Email))
+		return nil
+	}
+
+	plainToken, err := issueUserToken(ctx, uow.UserTokenRepository(), s.tokenGenerator, user.ID, domain.TokenPurposeMagicLink, domain.MagicLinkTokenTTL)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	content, err := s.templateService.RenderMagicLinkEmail(user.Name, s.config.buildFrontendLink(dto.MagicLinkPath, plainToken))
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	sendEmailAsync(s.messagingService, user.Email, dto.MagicLinkEmailSubject, content, dto.MsgMagicLinkEmailFailed)
+
+	return nil
+}
+
+// ExchangeMagicLink consume el enlace y abre la sesión con las mismas reglas que Login: correo validado,
+// cuenta activa, bloqueo por intentos fallidos y segundo paso si la cuenta tiene 2FA.
+func (s *AuthService) ExchangeMagicLink(ctx context.Context, input dto.AuthMagicLinkExchangeInput) (*dto.AuthLoginResponse, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	magicLink, err := consumeUserToken(ctx, uow.UserTokenRepository(), s.tokenGenerator, domain.TokenPurposeMagicLink, input.Token)
+	if err != nil {
+		if errors.Is(err, errUserTokenInvalid) {
+			return nil, errors.New(dto.ErrMagicLinkTokenInvalid)
+		}
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	user, err := uow.UserRepository().GetByID(ctx, magicLink.UserID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	loginInput := dto.AuthLoginInput{Email: user.Email, IP: input.IP}
+
+	lockout, err := uow.AccountLockoutRepository().Get(ctx, user.ID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if lockout.IsLocked(time.Now()) {
+		return nil, s.rejectLogin(ctx, uow, loginInput, &user.ID, dto.ErrAccountLocked)
+	}
+
+	if !user.EmailValidated {
+		return nil, errors.New(dto.ErrEmailNotValidated)
+	}
+
+	if !user.Status {
+		return nil, errors.New(dto.ErrAccountDisabled)
+	}
+
+	twoFactorEnabled, err := isTwoFactorEnabled(ctx, uow.TwoFactorRepository(), user.ID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if twoFactorEnabled {
+		return s.startTwoFactorChallenge(ctx, uow, user)
+	}
+
+	return s.completeLogin(ctx, uow, user, lockout, loginInput, domain.TokenOptions{})
+}
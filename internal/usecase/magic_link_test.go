@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+// newTestAuthService crea el servicio con un usuario activo y con el correo validado
+func newTestAuthService(t *testing.T) (*fakeUnitOfWork, *fakeTemplateService, *AuthService) {
+	t.Helper()
+
+	uow := newFakeUnitOfWork()
+	uow.users.users["user-1"] = &domain.User{ID: "user-1", Name: "Ana Torres", Email: "ana@appfe.com", Role: domain.UserRole, Status: true, EmailValidated: true}
+
+	hasher := fakeHasher{}
+	templates := &fakeTemplateService{}
+	config := AuthConfig{FrontendURL: "https://portal.appfe.test", AccessTokenTTL: 15 * time.Minute, RefreshTokenTTL: 24 * time.Hour}
+	factory := fakeUnitOfWorkFactory{uow: uow}
+	service := NewAuthService(factory, hasher, newFakeJWTService(), newFakeTokenGenerator(),
+		fakeMessagingService{}, templates, NewTokenRevocationService(factory), nil, nil, newTestPasswordPolicy(hasher), &fakeAuditService{}, config)
+
+	return uow, templates, service
+}
+
+func TestAuthService_RequestMagicLink(t *testing.T) {
+	tests := []struct {
+		name      string
+		email     string
+		disabled  bool
+		wantToken bool
+	}{
+		{name: "active account", email: "ana@appfe.com", wantToken: true},
+		{name: "unknown email", email: "nadie@appfe.com"},
+		{name: "disabled account", email: "ana@appfe.com", disabled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, templates, service := newTestAuthService(t)
+			uow.users.users["user-1"].Status = !tt.disabled
+
+			if err := service.RequestMagicLink(context.Background(), dto.AuthMagicLinkInput{Email: tt.email}); err != nil {
+				t.Fatalf("RequestMagicLink() error = %v", err)
+			}
+
+			active := uow.userTokens.active(domain.TokenPurposeMagicLink)
+			if !tt.wantToken {
+				if len(active) != 0 || len(templates.links) != 0 {
+					t.Errorf("issued %d tokens and rendered %v, want none", len(active), templates.links)
+				}
+				return
+			}
+
+			if len(active) != 1 {
+				t.Fatalf("active magic links = %d, want 1", len(active))
+			}
+			if ttl := active[0].ExpiresAt.Sub(active[0].CreatedAt); ttl != domain.MagicLinkTokenTTL {
+				t.Errorf("token TTL = %v, want %v", ttl, domain.MagicLinkTokenTTL)
+			}
+			if len(templates.links) != 1 || !strings.HasSuffix(templates.links[0], "plain-token-1") {
+				t.Errorf("rendered links = %v, want one ending with the plain token", templates.links)
+			}
+		})
+	}
+}
+
+func TestAuthService_RequestMagicLinkReplacesPrevious(t *testing.T) {
+	uow, _, service := newTestAuthService(t)
+
+	for range 2 {
+		if err := service.RequestMagicLink(context.Background(), dto.AuthMagicLinkInput{Email: "ana@appfe.com"}); err != nil {
+			t.Fatalf("RequestMagicLink() error = %v", err)
+		}
+	}
+
+	active := uow.userTokens.active(domain.TokenPurposeMagicLink)
+	if len(active) != 1 || active[0].TokenHash != "sha:plain-token-2" {
+		t.Fatalf("active magic links = %v, want only the latest", active)
+	}
+
+	if _, err := service.ExchangeMagicLink(context.Background(), dto.AuthMagicLinkExchangeInput{Token: "plain-token-1"}); err == nil || err.Error() != dto.ErrMagicLinkTokenInvalid {
+		t.Errorf("ExchangeMagicLink() with the replaced link error = %v, want %q", err, dto.ErrMagicLinkTokenInvalid)
+	}
+}
+
+func TestAuthService_ExchangeMagicLink(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		prepare func(uow *fakeUnitOfWork)
+		wantErr string
+	}{
+		{name: "valid link", token: "plain-token-1"},
+		{name: "unknown token", token: "plain-token-9", wantErr: dto.ErrMagicLinkTokenInvalid},
+		{
+			name:  "expired link",
+			token: "plain-token-1",
+			prepare: func(uow *fakeUnitOfWork) {
+				uow.userTokens.tokens[0].ExpiresAt = time.Now().Add(-time.Minute)
+			},
+			wantErr: dto.ErrMagicLinkTokenInvalid,
+		},
+		{
+			name:  "token issued for another purpose",
+			token: "plain-token-1",
+			prepare: func(uow *fakeUnitOfWork) {
+				uow.userTokens.tokens[0].Purpose = domain.TokenPurposePasswordReset
+			},
+			wantErr: dto.ErrMagicLinkTokenInvalid,
+		},
+		{
+			name:    "account disabled after the request",
+			token:   "plain-token-1",
+			prepare: func(uow *fakeUnitOfWork) { uow.users.users["user-1"].Status = false },
+			wantErr: dto.ErrAccountDisabled,
+		},
+		{
+			name:    "email not validated",
+			token:   "plain-token-1",
+			prepare: func(uow *fakeUnitOfWork) { uow.users.users["user-1"].EmailValidated = false },
+			wantErr: dto.ErrEmailNotValidated,
+		},
+		{
+			name:  "locked account",
+			token: "plain-token-1",
+			prepare: func(uow *fakeUnitOfWork) {
+				lockedUntil := time.Now().Add(time.Hour)
+				uow.lockouts.lockouts = map[string]*domain.AccountLockout{"user-1": {UserID: "user-1", LockedUntil: &lockedUntil}}
+			},
+			wantErr: dto.ErrAccountLocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, _, service := newTestAuthService(t)
+			if err := service.RequestMagicLink(context.Background(), dto.AuthMagicLinkInput{Email: "ana@appfe.com"}); err != nil {
+				t.Fatalf("RequestMagicLink() error = %v", err)
+			}
+			if tt.prepare != nil {
+				tt.prepare(uow)
+			}
+
+			response, err := service.ExchangeMagicLink(context.Background(), dto.AuthMagicLinkExchangeInput{Token: tt.token})
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ExchangeMagicLink() error = %v, want %q", err, tt.wantErr)
+				}
+				if len(uow.sessions.sessions) != 0 || len(uow.refreshTokens.tokens) != 0 {
+					t.Errorf("rejected link opened %d sessions and %d refresh tokens", len(uow.sessions.sessions), len(uow.refreshTokens.tokens))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExchangeMagicLink() error = %v", err)
+			}
+			if response.Token == "" || response.RefreshToken == "" || response.User.ID != "user-1" {
+				t.Errorf("response = %+v, want tokens for user-1", response)
+			}
+			if len(uow.sessions.sessions) != 1 {
+				t.Errorf("sessions = %d, want 1", len(uow.sessions.sessions))
+			}
+
+			// El enlace es de un solo uso
+			if _, err := service.ExchangeMagicLink(context.Background(), dto.AuthMagicLinkExchangeInput{Token: tt.token}); err == nil || err.Error() != dto.ErrMagicLinkTokenInvalid {
+				t.Errorf("second ExchangeMagicLink() error = %v, want %q", err, dto.ErrMagicLinkTokenInvalid)
+			}
+		})
+	}
+}
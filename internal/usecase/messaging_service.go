@@ -73,3 +73,17 @@ func (s *messagingService) SendEmail(ctx context.Context, to, subject, htmlConte
 
 	return nil
 }
+
+// sendEmailAsync envía un correo en segundo plano para no bloquear la respuesta.
+// Los errores de envío solo se registran, nunca se propagan al flujo que lo invoca.
+func sendEmailAsync(messagingService interfaces.MessagingService, to, subject, htmlContent, failureMsg string) {
+	go func() {
+		ctx := context.Background()
+		if err := messagingService.SendEmail(ctx, to, subject, htmlContent); err != nil {
+			logger.LogError(ctx, failureMsg,
+				logger.String("to", to),
+				logger.Error("error", err),
+			)
+		}
+	}()
+}
@@ -30,6 +30,58 @@ func (s *MigrationService) Migrate(ctx context.Context) error {
 		return err
 	}
 
+	if err := uow.RoleRepository().Migrate(ctx); err != nil {
+		return err
+	}
+
+	if err := uow.UserTokenRepository().Migrate(ctx); err != nil {
+		return err
+	}
+
+	if err := uow.RefreshTokenRepository().Migrate(ctx); err != nil {
+		return err
+	}
+
+	if err := uow.RevokedTokenRepository().Migrate(ctx); err != nil {
+		return err
+	}
+
+	if err := uow.LoginAttemptRepository().Migrate(ctx); err != nil {
+		return err
+	}
+
+	if err := uow.AccountLockoutRepository().Migrate(ctx); err != nil {
+		return err
+	}
+
+	if err := uow.TwoFactorRepository().Migrate(ctx); err != nil {
+		return err
+	}
+
+	if err := uow.PasswordHistoryRepository().Migrate(ctx); err != nil {
+		return err
+	}
+
+	if err := uow.AuditEventRepository().Migrate(ctx); err != nil {
+		return err
+	}
+
+	if err := uow.OIDCLoginStateRepository().Migrate(ctx); err != nil {
+		return err
+	}
+
+	if err := uow.OIDCLinkRepository().Migrate(ctx); err != nil {
+		return err
+	}
+
+	if err := uow.APIKeyRepository().Migrate(ctx); err != nil {
+		return err
+	}
+
+	if err := uow.SessionRepository().Migrate(ctx); err != nil {
+		return err
+	}
+
 	if err := uow.Commit(); err != nil {
 		return err
 	}
@@ -0,0 +1,390 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/pkg/logger"
+)
+
+// oidcProvisionMethod identifica en la auditoría las cuentas creadas desde el proveedor OIDC
+const oidcProvisionMethod = "oidc"
+
+// StartOIDCLogin inicia el flujo authorization code + PKCE: guarda state, nonce y code_verifier y
+// retorna la URL del proveedor a la que el portal debe redirigir al usuario
+func (s *AuthService) StartOIDCLogin(ctx context.Context) (*dto.OIDCAuthorizationResponse, error) {
+	if s.oidc == nil {
+		return nil, errors.New(dto.ErrOIDCUnavailable)
+	}
+
+	state, stateHash, err := s.tokenGenerator.Generate()
+	if err != nil {
+		return nil, errors.New(dto.ErrTokenGenerationFailed)
+	}
+
+	nonce, _, err := s.tokenGenerator.Generate()
+	if err != nil {
+		return nil, errors.New(dto.ErrTokenGenerationFailed)
+	}
+
+	codeVerifier, _, err := s.tokenGenerator.Generate()
+	if err != nil {
+		return nil, errors.New(dto.ErrTokenGenerationFailed)
+	}
+
+	authorizationURL, err := s.oidc.AuthorizationURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		logger.LogError(ctx, dto.MsgOIDCAuthorizationFailed, logger.Error("error", err))
+		return nil, errors.New(dto.ErrOIDCLoginFailed)
+	}
+
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	now := time.Now()
+	repo := uow.OIDCLoginStateRepository()
+
+	if err := repo.DeleteExpired(ctx, now); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	loginState := &domain.OIDCLoginState{
+		StateHash:    stateHash,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    now.Add(domain.OIDCLoginStateTTL),
+		CreatedAt:    now,
+	}
+
+	if err := repo.Create(ctx, loginState); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	return &dto.OIDCAuthorizationResponse{AuthorizationURL: authorizationURL, ExpiresAt: loginState.ExpiresAt, State: state}, nil
+}
+
+// CompleteOIDCLogin canjea el código recibido en el callback, reconoce al usuario por su identidad en el
+// proveedor (issuer, sub) o, la primera vez, por su correo verificado, y abre la sesión con las mismas
+// reglas que Login. Las cuentas con permisos de administración solo entran si vincularon la identidad antes.
+func (s *AuthService) CompleteOIDCLogin(ctx context.Context, input dto.AuthOIDCCallbackInput) (*dto.AuthLoginResponse, error) {
+	if s.oidc == nil {
+		return nil, errors.New(dto.ErrOIDCUnavailable)
+	}
+
+	identity, err := s.exchangeOIDCCode(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	user, err := s.resolveOIDCUser(ctx, uow, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	loginInput := dto.AuthLoginInput{Email: user.Email, IP: input.IP}
+
+	lockout, err := uow.AccountLockoutRepository().Get(ctx, user.ID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if lockout.IsLocked(time.Now()) {
+		return nil, s.rejectLogin(ctx, uow, loginInput, &user.ID, dto.ErrAccountLocked)
+	}
+
+	if !user.EmailValidated {
+		return nil, errors.New(dto.ErrEmailNotValidated)
+	}
+
+	if !user.Status {
+		return nil, errors.New(dto.ErrAccountDisabled)
+	}
+
+	twoFactorEnabled, err := isTwoFactorEnabled(ctx, uow.TwoFactorRepository(), user.ID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if twoFactorEnabled {
+		return s.startTwoFactorChallenge(ctx, uow, user)
+	}
+
+	return s.completeLogin(ctx, uow, user, lockout, loginInput, domain.TokenOptions{})
+}
+
+// LinkOIDCIdentity vincula la identidad del proveedor a la cuenta con la sesión iniciada. Es la única forma
+// de habilitar el inicio de sesión con el proveedor para las cuentas con permisos de administración.
+func (s *AuthService) LinkOIDCIdentity(ctx context.Context, userID string, input dto.AuthOIDCCallbackInput) error {
+	if s.oidc == nil {
+		return errors.New(dto.ErrOIDCUnavailable)
+	}
+
+	identity, err := s.exchangeOIDCCode(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	links := uow.OIDCLinkRepository()
+
+	link, err := links.FindBySubject(ctx, identity.Issuer, identity.Subject)
+	if err == nil {
+		if link.UserID != userID {
+			return errors.New(dto.ErrOIDCIdentityInUse)
+		}
+		return nil
+	}
+	if err.Error() != dto.ErrNoRowsFound {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := s.ensureNoOtherOIDCLink(ctx, links, userID, identity.Issuer); err != nil {
+		return err
+	}
+
+	if err := s.createOIDCLink(ctx, uow, userID, identity, domain.OIDCLinkSession); err != nil {
+		return err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	return nil
+}
+
+// exchangeOIDCCode comprueba que el callback venga del navegador que inició el flujo, consume el state y
+// canjea el código. La petición al proveedor se hace fuera de toda transacción para no retenerla durante
+// la llamada de red.
+func (s *AuthService) exchangeOIDCCode(ctx context.Context, input dto.AuthOIDCCallbackInput) (*domain.OIDCIdentity, error) {
+	if input.BrowserState == "" || subtle.ConstantTimeCompare([]byte(input.BrowserState), []byte(input.State)) != 1 {
+		return nil, errors.New(dto.ErrOIDCStateInvalid)
+	}
+
+	loginState, err := s.consumeOIDCState(ctx, input.State)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := s.oidc.Exchange(ctx, input.Code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		logger.Warn(ctx, dto.MsgOIDCExchangeFailed, logger.Error("error", err))
+		return nil, errors.New(dto.ErrOIDCLoginFailed)
+	}
+
+	return identity, nil
+}
+
+// resolveOIDCUser busca la cuenta vinculada a la identidad; sin vínculo, la asocia a la cuenta con el mismo
+// correo verificado o crea una nueva si el dominio lo permite
+func (s *AuthService) resolveOIDCUser(ctx context.Context, uow interfaces.UnitOfWork, identity *domain.OIDCIdentity) (*domain.User, error) {
+	links := uow.OIDCLinkRepository()
+
+	link, err := links.FindBySubject(ctx, identity.Issuer, identity.Subject)
+	if err == nil {
+		user, err := uow.UserRepository().GetByID(ctx, link.UserID)
+		if err != nil {
+			return nil, errors.New(dto.ErrInternalServer)
+		}
+
+		// Un vínculo creado por correo no basta si la cuenta obtuvo después permisos de administración
+		if !link.IsExplicit() {
+			if err := s.rejectAdminOIDCLogin(ctx, uow, user); err != nil {
+				return nil, err
+			}
+		}
+		return user, nil
+	}
+	if err.Error() != dto.ErrNoRowsFound {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, errors.New(dto.ErrOIDCEmailNotVerified)
+	}
+
+	user, err := uow.UserRepository().FindByEmail(ctx, identity.Email)
+	if err != nil {
+		if err.Error() != dto.ErrNoRowsFound {
+			return nil, errors.New(dto.ErrInternalServer)
+		}
+		return s.provisionOIDCUser(ctx, uow, identity)
+	}
+
+	if err := s.ensureNoOtherOIDCLink(ctx, links, user.ID, identity.Issuer); err != nil {
+		return nil, err
+	}
+
+	if err := s.rejectAdminOIDCLogin(ctx, uow, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.createOIDCLink(ctx, uow, user.ID, identity, domain.OIDCLinkEmail); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// rejectAdminOIDCLogin impide que quien controle el correo en el proveedor obtenga por esa vía una cuenta
+// con permisos de administración; esas cuentas deben vincular la identidad desde una sesión iniciada
+func (s *AuthService) rejectAdminOIDCLogin(ctx context.Context, uow interfaces.UnitOfWork, user *domain.User) error {
+	permissions, err := uow.RoleRepository().GetPermissions(ctx, user.Role)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if domain.HasAdminPermission(permissions) {
+		return errors.New(dto.ErrOIDCLinkRequired)
+	}
+
+	return nil
+}
+
+// ensureNoOtherOIDCLink rechaza vincular una cuenta que ya tiene otra identidad del mismo proveedor, por
+// ejemplo si el correo pasó a otra persona en el proveedor
+func (s *AuthService) ensureNoOtherOIDCLink(ctx context.Context, links interfaces.OIDCLinkRepository, userID, issuer string) error {
+	if _, err := links.FindByUser(ctx, userID, issuer); err == nil {
+		return errors.New(dto.ErrOIDCLinkMismatch)
+	} else if err.Error() != dto.ErrNoRowsFound {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	return nil
+}
+
+func (s *AuthService) createOIDCLink(ctx context.Context, uow interfaces.UnitOfWork, userID string, identity *domain.OIDCIdentity, method string) error {
+	link := &domain.OIDCLink{
+		UserID:    userID,
+		Issuer:    identity.Issuer,
+		Subject:   identity.Subject,
+		Method:    method,
+		CreatedAt: time.Now(),
+	}
+
+	if err := uow.OIDCLinkRepository().Create(ctx, link); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := s.audit.Record(ctx, uow, &domain.AuditEvent{
+		Type:       domain.AuditOIDCLinked,
+		ActorID:    &userID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &userID,
+		Details: map[string]any{
+			domain.AuditDetailMethod:  method,
+			domain.AuditDetailIssuer:  identity.Issuer,
+			domain.AuditDetailSubject: identity.Subject,
+		},
+	}); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	return nil
+}
+
+// consumeOIDCState elimina el state en su propia transacción, así queda usado aunque el canje falle
+func (s *AuthService) consumeOIDCState(ctx context.Context, state string) (*domain.OIDCLoginState, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	loginState, err := uow.OIDCLoginStateRepository().Consume(ctx, s.tokenGenerator.Hash(state))
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return nil, errors.New(dto.ErrOIDCStateInvalid)
+		}
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if loginState.IsExpired(time.Now()) {
+		return nil, errors.New(dto.ErrOIDCStateInvalid)
+	}
+
+	return loginState, nil
+}
+
+// provisionOIDCUser crea una cuenta USER_ROLE sin contraseña, ya vinculada a la identidad, si el dominio
+// del correo está permitido
+func (s *AuthService) provisionOIDCUser(ctx context.Context, uow interfaces.UnitOfWork, identity *domain.OIDCIdentity) (*domain.User, error) {
+	local, emailDomain, _ := strings.Cut(identity.Email, "@")
+	if !slices.Contains(s.config.OIDCAutoProvisionDomains, strings.ToLower(emailDomain)) {
+		return nil, errors.New(dto.ErrOIDCAccountNotFound)
+	}
+
+	name := strings.TrimSpace(identity.Name)
+	if name == "" {
+		name = local
+	}
+
+	user := &domain.User{
+		Name:           strings.ToUpper(name),
+		Email:          identity.Email,
+		Role:           domain.UserRole,
+		Status:         true,
+		EmailValidated: true,
+		CreatedAt:      time.Now(),
+	}
+
+	if err := uow.UserRepository().Create(ctx, user); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.OIDCLinkRepository().Create(ctx, &domain.OIDCLink{
+		UserID:    user.ID,
+		Issuer:    identity.Issuer,
+		Subject:   identity.Subject,
+		Method:    domain.OIDCLinkProvisioned,
+		CreatedAt: user.CreatedAt,
+	}); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := s.audit.Record(ctx, uow, &domain.AuditEvent{
+		Type:       domain.AuditUserProvisioned,
+		ActorID:    &user.ID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &user.ID,
+		Details: map[string]any{
+			domain.AuditDetailMethod:  oidcProvisionMethod,
+			domain.AuditDetailEmail:   user.Email,
+			domain.AuditDetailIssuer:  identity.Issuer,
+			domain.AuditDetailSubject: identity.Subject,
+		},
+	}); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+const testOIDCIssuer = "https://idp.appfe.test"
+
+type fakeOIDCProvider struct {
+	identity *domain.OIDCIdentity
+	err      error
+}
+
+func (p *fakeOIDCProvider) AuthorizationURL(_ context.Context, state, _, _ string) (string, error) {
+	return testOIDCIssuer + "/authorize?state=" + state, nil
+}
+
+func (p *fakeOIDCProvider) Exchange(context.Context, string, string, string) (*domain.OIDCIdentity, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	identity := *p.identity
+	return &identity, nil
+}
+
+// newTestOIDCAuthService agrega al servicio de newTestAuthService un administrador y el proveedor OIDC
+func newTestOIDCAuthService(t *testing.T, provider *fakeOIDCProvider) (*fakeUnitOfWork, *fakeAuditService, *AuthService) {
+	t.Helper()
+
+	uow, _, service := newTestAuthService(t)
+	uow.users.users["admin-1"] = &domain.User{ID: "admin-1", Email: "admin@appfe.com", Role: domain.AdminRole, Status: true, EmailValidated: true}
+	service.oidc = provider
+	service.config.OIDCAutoProvisionDomains = []string{"socios.appfe.com"}
+
+	return uow, service.audit.(*fakeAuditService), service
+}
+
+// startOIDCFlow inicia el flujo y devuelve el callback que enviaría el navegador que lo inició
+func startOIDCFlow(t *testing.T, service *AuthService) dto.AuthOIDCCallbackInput {
+	t.Helper()
+
+	response, err := service.StartOIDCLogin(context.Background())
+	if err != nil {
+		t.Fatalf("StartOIDCLogin() error = %v", err)
+	}
+
+	return dto.AuthOIDCCallbackInput{Code: "code-1", State: response.State, BrowserState: response.State}
+}
+
+func oidcIdentity(subject, email string, verified bool) *domain.OIDCIdentity {
+	return &domain.OIDCIdentity{Issuer: testOIDCIssuer, Subject: subject, Email: email, EmailVerified: verified}
+}
+
+func TestAuthService_CompleteOIDCLogin(t *testing.T) {
+	tests := []struct {
+		name        string
+		identity    *domain.OIDCIdentity
+		providerErr error
+		links       []*domain.OIDCLink
+		input       func(dto.AuthOIDCCallbackInput) dto.AuthOIDCCallbackInput
+		wantErr     string
+		wantUser    string
+		wantLink    string
+	}{
+		{name: "links by verified email", identity: oidcIdentity("sub-1", "ana@appfe.com", true), wantUser: "user-1", wantLink: domain.OIDCLinkEmail},
+		{
+			name:     "linked identity survives an email change",
+			identity: oidcIdentity("sub-1", "ana.nueva@otro.com", false),
+			links:    []*domain.OIDCLink{{UserID: "user-1", Issuer: testOIDCIssuer, Subject: "sub-1", Method: domain.OIDCLinkEmail}},
+			wantUser: "user-1",
+		},
+		{name: "provisions an allowed domain", identity: oidcIdentity("sub-9", "nuevo@socios.appfe.com", true), wantUser: "user-3", wantLink: domain.OIDCLinkProvisioned},
+		{name: "unknown email outside allowed domains", identity: oidcIdentity("sub-9", "nadie@gmail.com", true), wantErr: dto.ErrOIDCAccountNotFound},
+		{name: "unverified email", identity: oidcIdentity("sub-1", "ana@appfe.com", false), wantErr: dto.ErrOIDCEmailNotVerified},
+		{name: "admin by email needs an explicit link", identity: oidcIdentity("sub-2", "admin@appfe.com", true), wantErr: dto.ErrOIDCLinkRequired},
+		{
+			name:     "admin with an explicit link",
+			identity: oidcIdentity("sub-2", "admin@appfe.com", true),
+			links:    []*domain.OIDCLink{{UserID: "admin-1", Issuer: testOIDCIssuer, Subject: "sub-2", Method: domain.OIDCLinkSession}},
+			wantUser: "admin-1",
+		},
+		{
+			name:     "email link of an account promoted to admin",
+			identity: oidcIdentity("sub-2", "admin@appfe.com", true),
+			links:    []*domain.OIDCLink{{UserID: "admin-1", Issuer: testOIDCIssuer, Subject: "sub-2", Method: domain.OIDCLinkEmail}},
+			wantErr:  dto.ErrOIDCLinkRequired,
+		},
+		{
+			name:     "account linked to another subject",
+			identity: oidcIdentity("sub-new", "ana@appfe.com", true),
+			links:    []*domain.OIDCLink{{UserID: "user-1", Issuer: testOIDCIssuer, Subject: "sub-old", Method: domain.OIDCLinkEmail}},
+			wantErr:  dto.ErrOIDCLinkMismatch,
+		},
+		{
+			name:     "same subject from another issuer is a different identity",
+			identity: &domain.OIDCIdentity{Issuer: "https://otro-idp.test", Subject: "sub-1", Email: "nadie@gmail.com", EmailVerified: true},
+			links:    []*domain.OIDCLink{{UserID: "user-1", Issuer: testOIDCIssuer, Subject: "sub-1", Method: domain.OIDCLinkEmail}},
+			wantErr:  dto.ErrOIDCAccountNotFound,
+		},
+		{
+			name:     "state from another browser",
+			identity: oidcIdentity("sub-1", "ana@appfe.com", true),
+			input: func(in dto.AuthOIDCCallbackInput) dto.AuthOIDCCallbackInput {
+				in.BrowserState = "otro-state"
+				return in
+			},
+			wantErr: dto.ErrOIDCStateInvalid,
+		},
+		{
+			name:     "missing state cookie",
+			identity: oidcIdentity("sub-1", "ana@appfe.com", true),
+			input: func(in dto.AuthOIDCCallbackInput) dto.AuthOIDCCallbackInput {
+				in.BrowserState = ""
+				return in
+			},
+			wantErr: dto.ErrOIDCStateInvalid,
+		},
+		{name: "provider rejects the code", providerErr: errors.New("invalid_grant"), wantErr: dto.ErrOIDCLoginFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, audit, service := newTestOIDCAuthService(t, &fakeOIDCProvider{identity: tt.identity, err: tt.providerErr})
+			uow.oidcLinks.links = tt.links
+
+			input := startOIDCFlow(t, service)
+			if tt.input != nil {
+				input = tt.input(input)
+			}
+
+			response, err := service.CompleteOIDCLogin(context.Background(), input)
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("CompleteOIDCLogin() error = %v, want %q", err, tt.wantErr)
+				}
+				if len(uow.oidcLinks.links) != len(tt.links) || len(uow.sessions.sessions) != 0 {
+					t.Errorf("rejected login created %d links and %d sessions", len(uow.oidcLinks.links)-len(tt.links), len(uow.sessions.sessions))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CompleteOIDCLogin() error = %v", err)
+			}
+			if response.User.ID != tt.wantUser || response.Token == "" {
+				t.Errorf("response user = %q, token = %q, want a session for %q", response.User.ID, response.Token, tt.wantUser)
+			}
+
+			if tt.wantLink == "" {
+				if len(uow.oidcLinks.links) != len(tt.links) {
+					t.Errorf("links = %d, want the existing %d", len(uow.oidcLinks.links), len(tt.links))
+				}
+				return
+			}
+			link, err := uow.oidcLinks.FindBySubject(context.Background(), tt.identity.Issuer, tt.identity.Subject)
+			if err != nil || link.UserID != tt.wantUser || link.Method != tt.wantLink {
+				t.Errorf("link = %+v, err = %v, want %s link to %q", link, err, tt.wantLink, tt.wantUser)
+			}
+			if tt.wantLink == domain.OIDCLinkEmail && !slices.Contains(audit.eventTypes(), domain.AuditOIDCLinked) {
+				t.Errorf("audit events = %v, want %q", audit.eventTypes(), domain.AuditOIDCLinked)
+			}
+		})
+	}
+}
+
+func TestAuthService_CompleteOIDCLoginConsumesState(t *testing.T) {
+	uow, _, service := newTestOIDCAuthService(t, &fakeOIDCProvider{identity: oidcIdentity("sub-1", "ana@appfe.com", true)})
+
+	input := startOIDCFlow(t, service)
+	if _, err := service.CompleteOIDCLogin(context.Background(), input); err != nil {
+		t.Fatalf("CompleteOIDCLogin() error = %v", err)
+	}
+
+	if _, err := service.CompleteOIDCLogin(context.Background(), input); err == nil || err.Error() != dto.ErrOIDCStateInvalid {
+		t.Errorf("reused state error = %v, want %q", err, dto.ErrOIDCStateInvalid)
+	}
+
+	expired := startOIDCFlow(t, service)
+	for _, state := range uow.oidcStates.states {
+		state.ExpiresAt = time.Now().Add(-time.Second)
+	}
+	if _, err := service.CompleteOIDCLogin(context.Background(), expired); err == nil || err.Error() != dto.ErrOIDCStateInvalid {
+		t.Errorf("expired state error = %v, want %q", err, dto.ErrOIDCStateInvalid)
+	}
+}
+
+func TestAuthService_LinkOIDCIdentity(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    string
+		links     []*domain.OIDCLink
+		wantErr   string
+		wantLinks int
+	}{
+		{name: "admin links from a session", userID: "admin-1", wantLinks: 1},
+		{
+			name:      "identity already linked to the same account",
+			userID:    "admin-1",
+			links:     []*domain.OIDCLink{{UserID: "admin-1", Issuer: testOIDCIssuer, Subject: "sub-2", Method: domain.OIDCLinkSession}},
+			wantLinks: 1,
+		},
+		{
+			name:    "identity linked to another account",
+			userID:  "admin-1",
+			links:   []*domain.OIDCLink{{UserID: "user-1", Issuer: testOIDCIssuer, Subject: "sub-2", Method: domain.OIDCLinkEmail}},
+			wantErr: dto.ErrOIDCIdentityInUse,
+		},
+		{
+			name:    "account linked to another identity",
+			userID:  "admin-1",
+			links:   []*domain.OIDCLink{{UserID: "admin-1", Issuer: testOIDCIssuer, Subject: "sub-old", Method: domain.OIDCLinkSession}},
+			wantErr: dto.ErrOIDCLinkMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, audit, service := newTestOIDCAuthService(t, &fakeOIDCProvider{identity: oidcIdentity("sub-2", "otro@gmail.com", false)})
+			uow.oidcLinks.links = tt.links
+
+			err := service.LinkOIDCIdentity(context.Background(), tt.userID, startOIDCFlow(t, service))
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("LinkOIDCIdentity() error = %v, want %q", err, tt.wantErr)
+				}
+				if len(uow.oidcLinks.links) != len(tt.links) {
+					t.Errorf("links = %d, want %d", len(uow.oidcLinks.links), len(tt.links))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LinkOIDCIdentity() error = %v", err)
+			}
+
+			link, err := uow.oidcLinks.FindBySubject(context.Background(), testOIDCIssuer, "sub-2")
+			if err != nil || link.UserID != tt.userID || !link.IsExplicit() || len(uow.oidcLinks.links) != tt.wantLinks {
+				t.Errorf("link = %+v, err = %v, links = %d, want one explicit link to %q", link, err, len(uow.oidcLinks.links), tt.userID)
+			}
+			if tt.links == nil && !slices.Equal(audit.eventTypes(), []string{domain.AuditOIDCLinked}) {
+				t.Errorf("audit events = %v, want %q", audit.eventTypes(), domain.AuditOIDCLinked)
+			}
+		})
+	}
+}
+
+func TestAuthService_LinkOIDCIdentityRequiresBrowserState(t *testing.T) {
+	uow, _, service := newTestOIDCAuthService(t, &fakeOIDCProvider{identity: oidcIdentity("sub-2", "admin@appfe.com", true)})
+
+	input := startOIDCFlow(t, service)
+	input.BrowserState = "otro-state"
+
+	if err := service.LinkOIDCIdentity(context.Background(), "admin-1", input); err == nil || err.Error() != dto.ErrOIDCStateInvalid {
+		t.Fatalf("LinkOIDCIdentity() error = %v, want %q", err, dto.ErrOIDCStateInvalid)
+	}
+	if len(uow.oidcLinks.links) != 0 {
+		t.Errorf("links = %d, want 0", len(uow.oidcLinks.links))
+	}
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/pkg/logger"
+)
+
+// PasswordPolicyConfig agrupa las reglas que debe cumplir toda contraseña nueva
+type PasswordPolicyConfig struct {
+	MinLength int
+	MaxLength int
+
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSpecial   bool
+
+	// MaxAge es la vigencia de una contraseña desde su último cambio; 0 deshabilita el vencimiento
+	MaxAge time.Duration
+
+	// HistoryDepth es la cantidad de contraseñas anteriores que no pueden reutilizarse; 0 deshabilita el historial
+	HistoryDepth int
+
+	// ForbiddenWords son palabras que no pueden aparecer en la contraseña, sin distinguir mayúsculas
+	ForbiddenWords []string
+}
+
+const (
+	DefaultPasswordMinLength    = 8
+	DefaultPasswordMaxLength    = 128
+	DefaultPasswordHistoryDepth = 5
+
+	// minPersonalInfoLength evita rechazar contraseñas por fragmentos demasiado cortos del nombre o correo
+	minPersonalInfoLength = 3
+
+	// temporaryPasswordMinLength es la longitud mínima de las contraseñas temporales generadas por un admin
+	temporaryPasswordMinLength = 16
+)
+
+var DefaultPasswordForbiddenWords = []string{"password", "contraseña", "123456", "qwerty", "appfe"}
+
+var (
+	passwordUppercaseRegex = regexp.MustCompile(`\p{Lu}`)
+	passwordLowercaseRegex = regexp.MustCompile(`\p{Ll}`)
+	passwordDigitRegex     = regexp.MustCompile(`[0-9]`)
+	passwordSpecialRegex   = regexp.MustCompile(`[^\p{L}\p{N}\s]`)
+	personalInfoSplitRegex = regexp.MustCompile(`[\s._+\-]+`)
+)
+
+// NewPasswordPolicyConfigFromEnv crea la política de contraseñas desde variables de entorno
+func NewPasswordPolicyConfigFromEnv() PasswordPolicyConfig {
+	config := PasswordPolicyConfig{
+		MinLength: intFromEnv(dto.EnvPasswordMinLength, DefaultPasswordMinLength),
+		MaxLength: intFromEnv(dto.EnvPasswordMaxLength, DefaultPasswordMaxLength),
+
+		RequireUppercase: boolFromEnvOr(dto.EnvPasswordRequireUpper, true),
+		RequireLowercase: boolFromEnvOr(dto.EnvPasswordRequireLower, true),
+		RequireDigit:     boolFromEnvOr(dto.EnvPasswordRequireDigit, true),
+		RequireSpecial:   boolFromEnvOr(dto.EnvPasswordRequireSpecial, true),
+
+		MaxAge:         durationFromEnv(dto.EnvPasswordMaxAge, 0),
+		HistoryDepth:   DefaultPasswordHistoryDepth,
+		ForbiddenWords: DefaultPasswordForbiddenWords,
+	}
+
+	// A diferencia de intFromEnv, aquí 0 es válido y deshabilita el historial
+	if n, err := strconv.Atoi(os.Getenv(dto.EnvPasswordHistoryDepth)); err == nil && n >= 0 {
+		config.HistoryDepth = n
+	}
+
+	if value, ok := os.LookupEnv(dto.EnvPasswordForbiddenWords); ok {
+		config.ForbiddenWords = splitLowerList(value)
+	}
+
+	if config.MaxLength < config.MinLength {
+		config.MaxLength = config.MinLength
+	}
+
+	return config
+}
+
+// PasswordPolicy valida las contraseñas nuevas y mantiene el historial que impide reutilizarlas
+type PasswordPolicy struct {
+	config   PasswordPolicyConfig
+	hasher   interfaces.PasswordHasher
+	breaches interfaces.PasswordBreachChecker
+}
+
+// NewPasswordPolicy crea la política; breaches es opcional y, si es nil, no se consultan contraseñas filtradas
+func NewPasswordPolicy(config PasswordPolicyConfig, hasher interfaces.PasswordHasher, breaches interfaces.PasswordBreachChecker) *PasswordPolicy {
+	return &PasswordPolicy{config: config, hasher: hasher, breaches: breaches}
+}
+
+// Validate aplica las reglas que no dependen del historial; user puede ser nil cuando aún no existe
+func (p *PasswordPolicy) Validate(ctx context.Context, password string, user *domain.User) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.config.MinLength {
+		return dto.NewPasswordPolicyError(dto.ErrPasswordMinLength, p.config.MinLength)
+	}
+
+	if length > p.config.MaxLength {
+		return dto.NewPasswordPolicyError(dto.ErrPasswordMaxLength, p.config.MaxLength)
+	}
+
+	if p.config.RequireUppercase && !passwordUppercaseRegex.MatchString(password) {
+		return dto.NewPasswordPolicyError(dto.ErrPasswordUppercase)
+	}
+
+	if p.config.RequireLowercase && !passwordLowercaseRegex.MatchString(password) {
+		return dto.NewPasswordPolicyError(dto.ErrPasswordLowercase)
+	}
+
+	if p.config.RequireDigit && !passwordDigitRegex.MatchString(password) {
+		return dto.NewPasswordPolicyError(dto.ErrPasswordDigit)
+	}
+
+	if p.config.RequireSpecial && !passwordSpecialRegex.MatchString(password) {
+		return dto.NewPasswordPolicyError(dto.ErrPasswordSpecial)
+	}
+
+	lower := strings.ToLower(password)
+
+	for _, word := range p.config.ForbiddenWords {
+		if strings.Contains(lower, word) {
+			return dto.NewPasswordPolicyError(dto.ErrPasswordForbiddenWord)
+		}
+	}
+
+	for _, fragment := range personalInfoFragments(user) {
+		if strings.Contains(lower, fragment) {
+			return dto.NewPasswordPolicyError(dto.ErrPasswordPersonalInfo)
+		}
+	}
+
+	// La consulta al corpus va al final por ser la regla más costosa
+	if p.breaches != nil {
+		breached, err := p.breaches.IsBreached(password)
+		if err != nil {
+			logger.LogError(ctx, dto.MsgPasswordBreachCheckFailed, logger.Error("error", err))
+			return errors.New(dto.ErrInternalServer)
+		}
+		if breached {
+			return dto.NewPasswordPolicyError(dto.ErrPasswordBreached)
+		}
+	}
+
+	return nil
+}
+
+// personalInfoFragments devuelve las partes del nombre y del correo que no pueden aparecer en la contraseña
+func personalInfoFragments(user *domain.User) []string {
+	if user == nil {
+		return nil
+	}
+
+	sources := []string{user.Name}
+	if local, _, ok := strings.Cut(user.Email, "@"); ok {
+		sources = append(sources, local)
+	}
+
+	var fragments []string
+	for _, source := range sources {
+		for _, part := range personalInfoSplitRegex.Split(strings.ToLower(source), -1) {
+			if utf8.RuneCountInString(part) >= minPersonalInfoLength {
+				fragments = append(fragments, part)
+			}
+		}
+	}
+
+	return fragments
+}
+
+// Apply valida la contraseña nueva de un usuario existente, rechaza las reutilizadas y devuelve su hash
+// ya registrado en el historial. Debe ejecutarse dentro de la misma transacción que guarda el hash.
+func (p *PasswordPolicy) Apply(ctx context.Context, history interfaces.PasswordHistoryRepository, user *domain.User, password string) (string, error) {
+	if err := p.Validate(ctx, password, user); err != nil {
+		return "", err
+	}
+
+	if err := p.checkReuse(ctx, history, user, password); err != nil {
+		return "", err
+	}
+
+	hashed, err := p.hasher.Hash(password)
+	if err != nil {
+		return "", errors.New(dto.ErrInternalServer)
+	}
+
+	if err := p.Record(ctx, history, user.ID, hashed); err != nil {
+		return "", err
+	}
+
+	return hashed, nil
+}
+
+// Record guarda un hash en el historial y descarta los que exceden la profundidad configurada
+func (p *PasswordPolicy) Record(ctx context.Context, history interfaces.PasswordHistoryRepository, userID, hashed string) error {
+	if p.config.HistoryDepth == 0 {
+		return nil
+	}
+
+	if err := history.Add(ctx, userID, hashed); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := history.Prune(ctx, userID, p.config.HistoryDepth); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	return nil
+}
+
+func (p *PasswordPolicy) checkReuse(ctx context.Context, history interfaces.PasswordHistoryRepository, user *domain.User, password string) error {
+	if p.config.HistoryDepth == 0 {
+		return nil
+	}
+
+	hashes, err := history.ListRecent(ctx, user.ID, p.config.HistoryDepth)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	// La contraseña actual cuenta aunque sea anterior al historial (usuarios previos a esta tabla)
+	if user.Password != nil {
+		hashes = append(hashes, *user.Password)
+	}
+
+	seen := make(map[string]bool, len(hashes))
+	for _, hash := range hashes {
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+
+		if p.hasher.Verify(hash, password) == nil {
+			return dto.NewPasswordPolicyError(dto.ErrPasswordReused, p.config.HistoryDepth)
+		}
+	}
+
+	return nil
+}
+
+// IsExpired indica si la contraseña del usuario superó MaxAge. Sin fecha de cambio registrada no vence: la
+// migración la completa para las contraseñas existentes y cada cambio posterior la actualiza.
+func (p *PasswordPolicy) IsExpired(user *domain.User, now time.Time) bool {
+	if p.config.MaxAge == 0 || user.Password == nil || user.PasswordChangedAt == nil {
+		return false
+	}
+
+	return now.Sub(*user.PasswordChangedAt) > p.config.MaxAge
+}
+
+// TemporaryPasswordLength es la longitud de las contraseñas temporales, dentro de los límites de la política
+func (p *PasswordPolicy) TemporaryPasswordLength() int {
+	return min(max(temporaryPasswordMinLength, p.config.MinLength), p.config.MaxLength)
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+// fakeBreachChecker marca como filtradas las contraseñas del conjunto breached
+type fakeBreachChecker struct {
+	breached map[string]bool
+	err      error
+}
+
+func (c fakeBreachChecker) IsBreached(password string) (bool, error) {
+	if c.err != nil {
+		return false, c.err
+	}
+	return c.breached[password], nil
+}
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	user := &domain.User{Name: "Ana Torres", Email: "ana.torres@appfe.com"}
+
+	tests := []struct {
+		name          string
+		password      string
+		user          *domain.User
+		breaches      *fakeBreachChecker
+		wantErr       string
+		wantPolicyErr bool
+	}{
+		{name: "valid", password: testNewPassword, user: user},
+		{name: "valid without user", password: testNewPassword},
+		{name: "too short", password: "Ab#1", wantErr: fmt.Sprintf(dto.ErrPasswordMinLength, 8), wantPolicyErr: true},
+		{name: "too long", password: "Ab#1" + strings.Repeat("x", 125), wantErr: fmt.Sprintf(dto.ErrPasswordMaxLength, 128), wantPolicyErr: true},
+		{name: "missing uppercase", password: "nueva#2025xy", wantErr: dto.ErrPasswordUppercase, wantPolicyErr: true},
+		{name: "missing lowercase", password: "NUEVA#2025XY", wantErr: dto.ErrPasswordLowercase, wantPolicyErr: true},
+		{name: "missing digit", password: "Nueva#nueva", wantErr: dto.ErrPasswordDigit, wantPolicyErr: true},
+		{name: "missing special", password: "Nueva2025xy", wantErr: dto.ErrPasswordSpecial, wantPolicyErr: true},
+		{name: "forbidden word ignores case", password: "QWERTY#2025a", wantErr: dto.ErrPasswordForbiddenWord, wantPolicyErr: true},
+		{name: "contains name", password: "Torres#2025x", user: user, wantErr: dto.ErrPasswordPersonalInfo, wantPolicyErr: true},
+		{name: "contains email local part", password: "Xana#2025xy", user: user, wantErr: dto.ErrPasswordPersonalInfo, wantPolicyErr: true},
+		{name: "breached", password: testNewPassword, breaches: &fakeBreachChecker{breached: map[string]bool{testNewPassword: true}}, wantErr: dto.ErrPasswordBreached, wantPolicyErr: true},
+		{name: "not breached", password: testNewPassword, breaches: &fakeBreachChecker{breached: map[string]bool{}}},
+		{name: "breach check failure is not exposed", password: testNewPassword, breaches: &fakeBreachChecker{err: errors.New("corpus unavailable")}, wantErr: dto.ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newTestPasswordPolicy(fakeHasher{})
+			if tt.breaches != nil {
+				policy = NewPasswordPolicy(policy.config, fakeHasher{}, tt.breaches)
+			}
+
+			err := policy.Validate(context.Background(), tt.password, tt.user)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+			if dto.IsPasswordPolicyError(err) != tt.wantPolicyErr {
+				t.Errorf("Validate() policy error = %v, want %v", dto.IsPasswordPolicyError(err), tt.wantPolicyErr)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicy_ApplyHistory(t *testing.T) {
+	reused := fmt.Sprintf(dto.ErrPasswordReused, 3)
+
+	tests := []struct {
+		name         string
+		historyDepth int
+		history      []string
+		password     string
+		wantErr      string
+		wantHistory  int
+	}{
+		{name: "new password is recorded", historyDepth: 3, password: testNewPassword, wantHistory: 1},
+		{name: "reuse of current password", historyDepth: 3, password: testCurrentPassword, wantErr: reused},
+		{name: "reuse from history", historyDepth: 3, history: []string{fakeHashPrefix + "Vieja#2020ab", fakeHashPrefix + testNewPassword}, password: testNewPassword, wantErr: reused},
+		{name: "history beyond depth is allowed", historyDepth: 3, history: []string{"h1", "h2", "h3", fakeHashPrefix + testNewPassword}, password: testNewPassword, wantHistory: 3},
+		{name: "depth zero disables history", historyDepth: 0, password: testCurrentPassword},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := &fakePasswordHistoryRepository{hashes: map[string][]string{"user-1": tt.history}}
+			user := &domain.User{ID: "user-1", Name: "Ana Torres", Email: "ana@appfe.com", Password: stringPtr(fakeHashPrefix + testCurrentPassword)}
+
+			policy := newTestPasswordPolicy(fakeHasher{})
+			policy.config.HistoryDepth = tt.historyDepth
+
+			hashed, err := policy.Apply(context.Background(), history, user, tt.password)
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr || !dto.IsPasswordPolicyError(err) {
+					t.Fatalf("Apply() error = %v, want policy error %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if hashed != fakeHashPrefix+tt.password {
+				t.Errorf("Apply() hash = %q, want %q", hashed, fakeHashPrefix+tt.password)
+			}
+			if got := len(history.hashes["user-1"]); got != tt.wantHistory {
+				t.Errorf("history length = %d, want %d", got, tt.wantHistory)
+			}
+			if tt.wantHistory > 0 && history.hashes["user-1"][0] != hashed {
+				t.Errorf("latest history entry = %q, want %q", history.hashes["user-1"][0], hashed)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicy_IsExpired(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-24 * time.Hour)
+	old := now.Add(-100 * 24 * time.Hour)
+
+	tests := []struct {
+		name      string
+		maxAge    time.Duration
+		password  *string
+		changedAt *time.Time
+		want      bool
+	}{
+		{name: "expiration disabled", maxAge: 0, password: stringPtr("hash"), changedAt: &old},
+		{name: "without password", maxAge: 90 * 24 * time.Hour, changedAt: &old},
+		{name: "without change date", maxAge: 90 * 24 * time.Hour, password: stringPtr("hash")},
+		{name: "recently changed", maxAge: 90 * 24 * time.Hour, password: stringPtr("hash"), changedAt: &recent},
+		{name: "older than max age", maxAge: 90 * 24 * time.Hour, password: stringPtr("hash"), changedAt: &old, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := NewPasswordPolicy(PasswordPolicyConfig{MaxAge: tt.maxAge}, fakeHasher{}, nil)
+			user := &domain.User{Password: tt.password, PasswordChangedAt: tt.changedAt, CreatedAt: old}
+
+			if got := policy.IsExpired(user, now); got != tt.want {
+				t.Errorf("IsExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+)
+
+const DefaultPermissionCacheTTL = 30 * time.Second
+
+type permissionEntry struct {
+	permissions map[string]bool
+	fetchedAt   time.Time
+}
+
+// permissionService resuelve los permisos del rol presente en el token. Los permisos no viajan en el JWT
+// para que un cambio en un rol se aplique a todos sus usuarios en como máximo el TTL de la caché;
+// los cambios hechos desde esta instancia se aplican de inmediato mediante Invalidate.
+type permissionService struct {
+	uowFactory ui.UnitOfWorkFactory
+	ttl        time.Duration
+
+	mu      sync.Mutex
+	entries map[string]permissionEntry
+}
+
+func NewPermissionService(uowFactory ui.UnitOfWorkFactory, ttl time.Duration) interfaces.PermissionService {
+	return &permissionService{
+		uowFactory: uowFactory,
+		ttl:        ttl,
+		entries:    make(map[string]permissionEntry),
+	}
+}
+
+func (s *permissionService) HasPermission(ctx context.Context, role, permission string) (bool, error) {
+	entry, err := s.entry(ctx, role)
+	if err != nil {
+		return false, err
+	}
+
+	return entry.permissions[permission], nil
+}
+
+func (s *permissionService) IsAdmin(ctx context.Context, role string) (bool, error) {
+	entry, err := s.entry(ctx, role)
+	if err != nil {
+		return false, err
+	}
+
+	for _, permission := range domain.AdminPermissions {
+		if entry.permissions[permission] {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// entry retorna los permisos cacheados del rol, consultándolos de nuevo si vencieron
+func (s *permissionService) entry(ctx context.Context, role string) (permissionEntry, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	entry, ok := s.entries[role]
+	s.mu.Unlock()
+
+	if !ok || now.Sub(entry.fetchedAt) > s.ttl {
+		var err error
+		entry, err = s.fetch(ctx, role)
+		if err != nil {
+			return permissionEntry{}, err
+		}
+
+		s.mu.Lock()
+		s.entries[role] = entry
+		s.mu.Unlock()
+	}
+
+	return entry, nil
+}
+
+func (s *permissionService) Invalidate(role string) {
+	s.mu.Lock()
+	delete(s.entries, role)
+	s.mu.Unlock()
+}
+
+func (s *permissionService) fetch(ctx context.Context, role string) (permissionEntry, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return permissionEntry{}, err
+	}
+	defer uow.Rollback()
+
+	permissions, err := uow.RoleRepository().GetPermissions(ctx, role)
+	if err != nil {
+		return permissionEntry{}, err
+	}
+
+	entry := permissionEntry{permissions: make(map[string]bool, len(permissions)), fetchedAt: time.Now()}
+	for _, permission := range permissions {
+		entry.permissions[permission] = true
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+)
+
+func newTestPermissionService(ttl time.Duration) (*fakeUnitOfWork, *permissionService) {
+	uow := newFakeUnitOfWork()
+	uow.roles.permissions["SUPPORT_ROLE"] = []string{domain.PermissionUsersRead, domain.PermissionUsersWrite}
+	uow.roles.permissions["AUDITOR_ROLE"] = []string{domain.PermissionAuditRead}
+
+	return uow, NewPermissionService(fakeUnitOfWorkFactory{uow: uow}, ttl).(*permissionService)
+}
+
+func TestPermissionService_HasPermission(t *testing.T) {
+	_, service := newTestPermissionService(time.Minute)
+
+	tests := []struct {
+		name       string
+		role       string
+		permission string
+		want       bool
+	}{
+		{name: "admin", role: domain.AdminRole, permission: domain.PermissionRolesManage, want: true},
+		{name: "custom role granted", role: "SUPPORT_ROLE", permission: domain.PermissionUsersWrite, want: true},
+		{name: "custom role denied", role: "SUPPORT_ROLE", permission: domain.PermissionUsersImpersonate},
+		{name: "user role", role: domain.UserRole, permission: domain.PermissionUsersRead},
+		{name: "unknown role", role: "DELETED_ROLE", permission: domain.PermissionUsersRead},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.HasPermission(context.Background(), tt.role, tt.permission)
+			if err != nil {
+				t.Fatalf("HasPermission() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HasPermission(%s, %s) = %v, want %v", tt.role, tt.permission, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissionService_IsAdmin(t *testing.T) {
+	_, service := newTestPermissionService(time.Minute)
+
+	tests := []struct {
+		role string
+		want bool
+	}{
+		{role: domain.AdminRole, want: true},
+		{role: "SUPPORT_ROLE", want: true},
+		{role: "AUDITOR_ROLE"},
+		{role: domain.UserRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			got, err := service.IsAdmin(context.Background(), tt.role)
+			if err != nil {
+				t.Fatalf("IsAdmin() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsAdmin(%s) = %v, want %v", tt.role, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissionService_Cache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("hits within ttl", func(t *testing.T) {
+		uow, service := newTestPermissionService(time.Minute)
+
+		for range 3 {
+			if _, err := service.HasPermission(ctx, "SUPPORT_ROLE", domain.PermissionUsersRead); err != nil {
+				t.Fatalf("HasPermission() error = %v", err)
+			}
+		}
+		if _, err := service.IsAdmin(ctx, "SUPPORT_ROLE"); err != nil {
+			t.Fatalf("IsAdmin() error = %v", err)
+		}
+
+		if uow.roles.lookups != 1 {
+			t.Errorf("lookups = %d, want 1", uow.roles.lookups)
+		}
+	})
+
+	t.Run("invalidate applies role changes immediately", func(t *testing.T) {
+		uow, service := newTestPermissionService(time.Minute)
+
+		if ok, _ := service.HasPermission(ctx, "SUPPORT_ROLE", domain.PermissionAuditRead); ok {
+			t.Fatal("HasPermission() = true before the role change")
+		}
+
+		uow.roles.permissions["SUPPORT_ROLE"] = append(uow.roles.permissions["SUPPORT_ROLE"], domain.PermissionAuditRead)
+		service.Invalidate("SUPPORT_ROLE")
+
+		if ok, _ := service.HasPermission(ctx, "SUPPORT_ROLE", domain.PermissionAuditRead); !ok {
+			t.Error("HasPermission() = false after Invalidate")
+		}
+		if uow.roles.lookups != 2 {
+			t.Errorf("lookups = %d, want 2", uow.roles.lookups)
+		}
+	})
+
+	t.Run("expired entries are fetched again", func(t *testing.T) {
+		uow, service := newTestPermissionService(time.Millisecond)
+
+		if _, err := service.HasPermission(ctx, "SUPPORT_ROLE", domain.PermissionUsersRead); err != nil {
+			t.Fatalf("HasPermission() error = %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+		if _, err := service.HasPermission(ctx, "SUPPORT_ROLE", domain.PermissionUsersRead); err != nil {
+			t.Fatalf("HasPermission() error = %v", err)
+		}
+
+		if uow.roles.lookups != 2 {
+			t.Errorf("lookups = %d, want 2", uow.roles.lookups)
+		}
+	})
+}
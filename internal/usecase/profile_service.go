@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	usecaseInterfaces "github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+)
+
+type profileService struct {
+	uowFactory     interfaces.UnitOfWorkFactory
+	passwordHasher interfaces.PasswordHasher
+	passwordPolicy *PasswordPolicy
+	audit          usecaseInterfaces.AuditService
+}
+
+func NewProfileService(
+	uowFactory interfaces.UnitOfWorkFactory,
+	passwordHasher interfaces.PasswordHasher,
+	passwordPolicy *PasswordPolicy,
+	audit usecaseInterfaces.AuditService,
+) usecaseInterfaces.ProfileService {
+	return &profileService{
+		uowFactory:     uowFactory,
+		passwordHasher: passwordHasher,
+		passwordPolicy: passwordPolicy,
+		audit:          audit,
+	}
+}
+
+func (s *profileService) Get(ctx context.Context, userID string) (*domain.User, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	user, err := uow.UserRepository().GetByID(ctx, userID)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return nil, errors.New(dto.ErrUserNotFound)
+		}
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	user.Password = nil
+	return user, nil
+}
+
+// Update modifica solo el nombre y la imagen; rol, estado y correo quedan reservados a los administradores
+func (s *profileService) Update(ctx context.Context, userID string, input dto.UpdateProfileInput) (*domain.User, error) {
+	if input.Name != nil {
+		// Igual que al crear el usuario: el nombre se guarda recortado y en mayúsculas
+		name := strings.ToUpper(strings.TrimSpace(*input.Name))
+		if name == "" {
+			return nil, errors.New(dto.ErrProfileNameEmpty)
+		}
+		input.Name = &name
+	}
+
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	userRepo := uow.UserRepository()
+
+	update := &dto.UpdateUserInput{ID: userID, Name: input.Name, Img: input.Img}
+	if err := userRepo.UpdateByID(ctx, update); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	user, err := userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	user.Password = nil
+	return user, nil
+}
+
+// ChangePassword reemplaza la contraseña tras verificar la actual y levanta el cambio obligatorio. El cambio
+// incrementa token_version, por lo que todas las sesiones abiertas, incluida la actual, deben iniciar sesión de nuevo.
+func (s *profileService) ChangePassword(ctx context.Context, userID string, input dto.ChangePasswordInput) error {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	userRepo := uow.UserRepository()
+
+	user, err := userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if user.Password == nil || s.passwordHasher.Verify(*user.Password, input.CurrentPassword) != nil {
+		return errors.New(dto.ErrCurrentPasswordIncorrect)
+	}
+
+	hashed, err := s.passwordPolicy.Apply(ctx, uow.PasswordHistoryRepository(), user, input.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	mustChange := false
+	if err := userRepo.UpdateByID(ctx, &dto.UpdateUserInput{ID: userID, Password: &hashed, MustChangePassword: &mustChange}); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.RefreshTokenRepository().RevokeByUser(ctx, userID); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.SessionRepository().RevokeByUser(ctx, userID, time.Now()); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := s.audit.Record(ctx, uow, &domain.AuditEvent{
+		Type:       domain.AuditPasswordChanged,
+		ActorID:    &user.ID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &user.ID,
+	}); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	return uow.Commit()
+}
+
+// ListSessions retorna las sesiones vigentes del usuario, las mismas que un administrador ve y puede terminar
+func (s *profileService) ListSessions(ctx context.Context, userID string) ([]*domain.Session, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	sessions, err := uow.SessionRepository().ListActiveByUser(ctx, userID, time.Now())
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+const (
+	testCurrentPassword = "Actual#2024x"
+	testNewPassword     = "Nueva#2025xy"
+)
+
+func newTestPasswordPolicy(hasher fakeHasher) *PasswordPolicy {
+	return NewPasswordPolicy(PasswordPolicyConfig{
+		MinLength:        8,
+		MaxLength:        128,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSpecial:   true,
+		HistoryDepth:     3,
+		ForbiddenWords:   DefaultPasswordForbiddenWords,
+	}, hasher, nil)
+}
+
+func TestProfileService_ChangePassword(t *testing.T) {
+	tests := []struct {
+		name            string
+		currentPassword string
+		newPassword     string
+		history         []string
+		hashErr         error
+		wantErr         string
+		wantPolicyErr   bool
+	}{
+		{name: "success", currentPassword: testCurrentPassword, newPassword: testNewPassword},
+		{name: "wrong current password", currentPassword: "Otra#2024xx", newPassword: testNewPassword, wantErr: dto.ErrCurrentPasswordIncorrect},
+		{name: "policy violation", currentPassword: testCurrentPassword, newPassword: "corta", wantPolicyErr: true},
+		{name: "same as current password", currentPassword: testCurrentPassword, newPassword: testCurrentPassword, wantErr: fmt.Sprintf(dto.ErrPasswordReused, 3), wantPolicyErr: true},
+		{name: "reused from history", currentPassword: testCurrentPassword, newPassword: testNewPassword, history: []string{fakeHashPrefix + testNewPassword}, wantErr: fmt.Sprintf(dto.ErrPasswordReused, 3), wantPolicyErr: true},
+		{name: "hasher failure is not exposed", currentPassword: testCurrentPassword, newPassword: testNewPassword, hashErr: errors.New("argon2: out of memory"), wantErr: dto.ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow := newFakeUnitOfWork()
+			uow.users.users["user-1"] = &domain.User{
+				ID:                 "user-1",
+				Name:               "Ana Torres",
+				Email:              "ana@appfe.com",
+				Password:           stringPtr(fakeHashPrefix + testCurrentPassword),
+				Status:             true,
+				MustChangePassword: true,
+			}
+			if tt.history != nil {
+				uow.passwordHistory.hashes = map[string][]string{"user-1": tt.history}
+			}
+
+			hasher := fakeHasher{hashErr: tt.hashErr}
+			audit := &fakeAuditService{}
+			service := NewProfileService(fakeUnitOfWorkFactory{uow: uow}, hasher, newTestPasswordPolicy(hasher), audit)
+
+			err := service.ChangePassword(context.Background(), "user-1", dto.ChangePasswordInput{
+				CurrentPassword: tt.currentPassword,
+				NewPassword:     tt.newPassword,
+			})
+
+			user := uow.users.users["user-1"]
+			if tt.wantErr != "" || tt.wantPolicyErr {
+				if err == nil {
+					t.Fatal("ChangePassword() error = nil, want error")
+				}
+				if tt.wantPolicyErr != dto.IsPasswordPolicyError(err) {
+					t.Errorf("ChangePassword() policy error = %v, want %v", dto.IsPasswordPolicyError(err), tt.wantPolicyErr)
+				}
+				if tt.wantErr != "" && err.Error() != tt.wantErr {
+					t.Errorf("ChangePassword() error = %q, want %q", err.Error(), tt.wantErr)
+				}
+				if uow.commits != 0 {
+					t.Error("ChangePassword() committed a failed change")
+				}
+				if *user.Password != fakeHashPrefix+testCurrentPassword {
+					t.Error("ChangePassword() replaced the password on failure")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ChangePassword() error = %v", err)
+			}
+			if *user.Password != fakeHashPrefix+tt.newPassword {
+				t.Errorf("password = %q, want the new hash", *user.Password)
+			}
+			if user.MustChangePassword {
+				t.Error("MustChangePassword still set after the change")
+			}
+			if !reflect.DeepEqual(uow.refreshTokens.revokedUsers, []string{"user-1"}) {
+				t.Errorf("refresh tokens revoked for %v, want [user-1]", uow.refreshTokens.revokedUsers)
+			}
+			if !reflect.DeepEqual(uow.sessions.revokedUsers, []string{"user-1"}) {
+				t.Errorf("sessions revoked for %v, want [user-1]", uow.sessions.revokedUsers)
+			}
+			if got := uow.passwordHistory.hashes["user-1"]; len(got) != 1 || got[0] != fakeHashPrefix+tt.newPassword {
+				t.Errorf("password history = %v, want the new hash", got)
+			}
+			if got := audit.eventTypes(); !reflect.DeepEqual(got, []string{domain.AuditPasswordChanged}) {
+				t.Errorf("audit events = %v, want [%s]", got, domain.AuditPasswordChanged)
+			}
+			if uow.commits != 1 {
+				t.Errorf("commits = %d, want 1", uow.commits)
+			}
+		})
+	}
+}
+
+func TestProfileService_Update(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantName string
+		wantErr  string
+	}{
+		{name: "name is trimmed and uppercased", input: "  ana maría torres  ", wantName: "ANA MARÍA TORRES"},
+		{name: "blank name", input: "   ", wantName: "ANA TORRES", wantErr: dto.ErrProfileNameEmpty},
+		{name: "empty name", input: "", wantName: "ANA TORRES", wantErr: dto.ErrProfileNameEmpty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow := newFakeUnitOfWork()
+			uow.users.users["user-1"] = &domain.User{ID: "user-1", Name: "ANA TORRES", Email: "ana@appfe.com", Status: true}
+
+			hasher := fakeHasher{}
+			service := NewProfileService(fakeUnitOfWorkFactory{uow: uow}, hasher, newTestPasswordPolicy(hasher), &fakeAuditService{})
+
+			user, err := service.Update(context.Background(), "user-1", dto.UpdateProfileInput{Name: stringPtr(tt.input)})
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Update() error = %v, want %q", err, tt.wantErr)
+				}
+				if uow.commits != 0 {
+					t.Error("Update() committed a rejected name")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Update() error = %v", err)
+				}
+				if user.Name != tt.wantName {
+					t.Errorf("Update() name = %q, want %q", user.Name, tt.wantName)
+				}
+			}
+
+			if got := uow.users.users["user-1"].Name; got != tt.wantName {
+				t.Errorf("stored name = %q, want %q", got, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestProfileService_ListSessions(t *testing.T) {
+	now := time.Now()
+	revokedAt := now.Add(-time.Minute)
+
+	uow := newFakeUnitOfWork()
+	uow.sessions.sessions = map[string]*domain.Session{
+		"active":      {ID: "active", UserID: "user-1", ExpiresAt: now.Add(time.Hour)},
+		"terminated":  {ID: "terminated", UserID: "user-1", ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt},
+		"expired":     {ID: "expired", UserID: "user-1", ExpiresAt: now.Add(-time.Hour)},
+		"other-owner": {ID: "other-owner", UserID: "user-2", ExpiresAt: now.Add(time.Hour)},
+	}
+
+	hasher := fakeHasher{}
+	service := NewProfileService(fakeUnitOfWorkFactory{uow: uow}, hasher, newTestPasswordPolicy(hasher), &fakeAuditService{})
+
+	sessions, err := service.ListSessions(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "active" {
+		t.Errorf("ListSessions() = %v, want only the active session", sessions)
+	}
+}
@@ -0,0 +1,339 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+)
+
+type roleService struct {
+	uowFactory  ui.UnitOfWorkFactory
+	permissions interfaces.PermissionService
+	audit       interfaces.AuditService
+}
+
+func NewRoleService(uowFactory ui.UnitOfWorkFactory, permissions interfaces.PermissionService, audit interfaces.AuditService) interfaces.RoleService {
+	return &roleService{
+		uowFactory:  uowFactory,
+		permissions: permissions,
+		audit:       audit,
+	}
+}
+
+func (s *roleService) GetAll(ctx context.Context) ([]*domain.Role, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	roles, err := uow.RoleRepository().GetAll(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	return roles, nil
+}
+
+func (s *roleService) GetByName(ctx context.Context, name string) (*domain.Role, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	return s.findRole(ctx, uow.RoleRepository(), name)
+}
+
+func (s *roleService) Create(ctx context.Context, input dto.CreateRoleInput) (*domain.Role, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	roleRepo := uow.RoleRepository()
+
+	if _, err := roleRepo.GetByName(ctx, input.Name); err == nil {
+		return nil, errors.New(dto.ErrRoleAlreadyExists)
+	} else if err.Error() != dto.ErrNoRowsFound {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	role := &domain.Role{
+		Name:        input.Name,
+		Description: input.Description,
+		Permissions: uniquePermissions(input.Permissions),
+	}
+
+	if err := roleRepo.Create(ctx, role); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := s.recordRoleEvent(ctx, uow, domain.AuditRoleCreated, role); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	return role, nil
+}
+
+// Update cambia la descripción y/o reemplaza los permisos. Los permisos de ADMIN_ROLE no se pueden
+// modificar para que siempre exista un rol capaz de administrar los demás. Nadie puede modificar su propio
+// rol, y el actor debe tener los permisos actuales del rol y los nuevos para no escalar privilegios.
+func (s *roleService) Update(ctx context.Context, name string, input dto.UpdateRoleInput) (*domain.Role, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	roleRepo := uow.RoleRepository()
+
+	role, err := s.findRole(ctx, roleRepo, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Permissions != nil && role.Name == domain.AdminRole {
+		return nil, errors.New(dto.ErrSystemRoleProtected)
+	}
+
+	var permissions []string
+	if input.Permissions != nil {
+		permissions = uniquePermissions(*input.Permissions)
+	}
+
+	if err := authorizeRoleUpdate(ctx, uow, role, permissions); err != nil {
+		return nil, err
+	}
+
+	if input.Description != nil {
+		role.Description = *input.Description
+	}
+	if input.Permissions != nil {
+		role.Permissions = permissions
+	}
+
+	if err := roleRepo.Update(ctx, role); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := s.recordRoleEvent(ctx, uow, domain.AuditRoleUpdated, role); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	s.permissions.Invalidate(role.Name)
+
+	return role, nil
+}
+
+// Delete elimina un rol personalizado sin usuarios asignados
+func (s *roleService) Delete(ctx context.Context, name string) error {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	roleRepo := uow.RoleRepository()
+
+	role, err := s.findRole(ctx, roleRepo, name)
+	if err != nil {
+		return err
+	}
+
+	if role.IsSystem {
+		return errors.New(dto.ErrSystemRoleProtected)
+	}
+
+	users, err := roleRepo.CountUsers(ctx, role.Name)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+	if users > 0 {
+		return errors.New(dto.ErrRoleInUse)
+	}
+
+	if err := roleRepo.Delete(ctx, role.Name); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := s.recordRoleEvent(ctx, uow, domain.AuditRoleDeleted, role); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	s.permissions.Invalidate(role.Name)
+
+	return nil
+}
+
+// recordRoleEvent registra el cambio de un rol junto con sus permisos resultantes
+func (s *roleService) recordRoleEvent(ctx context.Context, uow ui.UnitOfWork, eventType string, role *domain.Role) error {
+	return s.audit.Record(ctx, uow, &domain.AuditEvent{
+		Type:       eventType,
+		TargetType: domain.AuditTargetRole,
+		TargetID:   &role.Name,
+		Details:    map[string]any{domain.AuditDetailPermissions: role.Permissions},
+	})
+}
+
+func (s *roleService) findRole(ctx context.Context, repo ui.RoleRepository, name string) (*domain.Role, error) {
+	role, err := repo.GetByName(ctx, name)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return nil, errors.New(dto.ErrRoleNotFound)
+		}
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	return role, nil
+}
+
+// authorizeRoleAssignment impide escalar privilegios al asignar roles: el actor de la petición debe tener todos
+// los permisos de cada rol indicado y no puede cambiar el suyo. targetUserID es vacío al crear un usuario.
+func authorizeRoleAssignment(ctx context.Context, uow ui.UnitOfWork, targetUserID string, roles ...string) error {
+	actorID := domain.RequestMetadataFromContext(ctx).ActorID
+	if actorID == "" {
+		return errors.New(dto.ErrRoleAssignmentForbidden)
+	}
+
+	if actorID == targetUserID {
+		return errors.New(dto.ErrOwnRoleChangeForbidden)
+	}
+
+	actor, err := uow.UserRepository().GetByID(ctx, actorID)
+	if err != nil {
+		return err
+	}
+
+	covered, err := rolesCoveredBy(ctx, uow.RoleRepository(), actor.Role, roles...)
+	if err != nil {
+		return err
+	}
+	if !covered {
+		return errors.New(dto.ErrRoleAssignmentForbidden)
+	}
+
+	return nil
+}
+
+// authorizeUserWrite impide que un actor modifique, elimine o cierre las sesiones de un usuario con más
+// privilegios: el rol de quien hace la petición debe tener todos los permisos del rol de target. En una
+// suplantación se compara el rol del usuario suplantado, que es con el que se autorizó la petición.
+func authorizeUserWrite(ctx context.Context, uow ui.UnitOfWork, target *domain.User) error {
+	actorID := domain.RequestMetadataFromContext(ctx).UserID()
+	if actorID == "" {
+		return errors.New(dto.ErrUserWriteForbidden)
+	}
+
+	actor, err := uow.UserRepository().GetByID(ctx, actorID)
+	if err != nil {
+		return err
+	}
+
+	covered, err := rolesCoveredBy(ctx, uow.RoleRepository(), actor.Role, target.Role)
+	if err != nil {
+		return err
+	}
+	if !covered {
+		return errors.New(dto.ErrUserWriteForbidden)
+	}
+
+	return nil
+}
+
+// authorizeRoleUpdate impide que el actor edite su propio rol o que otorgue o retire permisos que su rol
+// no tiene: debe tener todos los permisos actuales de role y los de permissions
+func authorizeRoleUpdate(ctx context.Context, uow ui.UnitOfWork, role *domain.Role, permissions []string) error {
+	actorID := domain.RequestMetadataFromContext(ctx).UserID()
+	if actorID == "" {
+		return errors.New(dto.ErrRolePermissionsForbidden)
+	}
+
+	actor, err := uow.UserRepository().GetByID(ctx, actorID)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if actor.Role == role.Name {
+		return errors.New(dto.ErrOwnRoleUpdateForbidden)
+	}
+
+	granted, err := uow.RoleRepository().GetPermissions(ctx, actor.Role)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if !domain.ContainsAllPermissions(granted, role.Permissions) || !domain.ContainsAllPermissions(granted, permissions) {
+		return errors.New(dto.ErrRolePermissionsForbidden)
+	}
+
+	return nil
+}
+
+// rolesCoveredBy indica si grantedRole tiene todos los permisos de cada uno de roles
+func rolesCoveredBy(ctx context.Context, repo ui.RoleRepository, grantedRole string, roles ...string) (bool, error) {
+	granted, err := repo.GetPermissions(ctx, grantedRole)
+	if err != nil {
+		return false, err
+	}
+
+	for _, role := range roles {
+		required, err := repo.GetPermissions(ctx, role)
+		if err != nil {
+			return false, err
+		}
+		if !domain.ContainsAllPermissions(granted, required) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// resolveRole valida que el rol exista en la base de datos; un rol vacío se resuelve como USER_ROLE
+func resolveRole(ctx context.Context, repo ui.RoleRepository, role string) (string, error) {
+	if role == "" {
+		return domain.UserRole, nil
+	}
+
+	if err := ensureRoleExists(ctx, repo, role); err != nil {
+		return "", err
+	}
+
+	return role, nil
+}
+
+func ensureRoleExists(ctx context.Context, repo ui.RoleRepository, role string) error {
+	if _, err := repo.GetByName(ctx, role); err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return errors.New(domain.ErrInvalidRole)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func uniquePermissions(permissions []string) []string {
+	unique := slices.Clone(permissions)
+	slices.Sort(unique)
+	return slices.Compact(unique)
+}
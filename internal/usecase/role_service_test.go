@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+const (
+	roleManagerRole = "ROLE_MANAGER_ROLE"
+	helpdeskRole    = "HELPDESK_ROLE"
+	auditorRole     = "AUDITOR_ROLE"
+)
+
+// newTestRoleService crea el servicio con un administrador y un gestor de roles sin permisos de auditoría
+// ni de suplantación
+func newTestRoleService(t *testing.T) (*fakeUnitOfWork, *fakeAuditService, *roleService) {
+	t.Helper()
+
+	uow := newFakeUnitOfWork()
+	uow.roles.permissions[roleManagerRole] = []string{domain.PermissionUsersRead, domain.PermissionUsersWrite, domain.PermissionRolesManage}
+	uow.roles.permissions[helpdeskRole] = []string{domain.PermissionUsersRead}
+	uow.roles.permissions[auditorRole] = []string{domain.PermissionAuditRead}
+	uow.users.users["admin-1"] = &domain.User{ID: "admin-1", Role: domain.AdminRole, Status: true}
+	uow.users.users["manager-1"] = &domain.User{ID: "manager-1", Role: roleManagerRole, Status: true}
+
+	factory := fakeUnitOfWorkFactory{uow: uow}
+	audit := &fakeAuditService{}
+	service := NewRoleService(factory, NewPermissionService(factory, time.Minute), audit)
+
+	return uow, audit, service.(*roleService)
+}
+
+func TestRoleService_Update(t *testing.T) {
+	description := "Mesa de ayuda"
+
+	tests := []struct {
+		name            string
+		actorID         string
+		role            string
+		permissions     []string
+		description     *string
+		wantErr         string
+		wantPermissions []string
+	}{
+		{name: "admin grants any permission", actorID: "admin-1", role: roleManagerRole, permissions: []string{domain.PermissionUsersImpersonate}, wantPermissions: []string{domain.PermissionUsersImpersonate}},
+		{name: "grant permissions the actor has", actorID: "manager-1", role: helpdeskRole, permissions: []string{domain.PermissionUsersRead, domain.PermissionUsersWrite}, wantPermissions: []string{domain.PermissionUsersRead, domain.PermissionUsersWrite}},
+		{name: "description only", actorID: "manager-1", role: helpdeskRole, description: &description, wantPermissions: []string{domain.PermissionUsersRead}},
+		{name: "grant a permission the actor lacks", actorID: "manager-1", role: helpdeskRole, permissions: []string{domain.PermissionUsersRead, domain.PermissionUsersImpersonate}, wantErr: dto.ErrRolePermissionsForbidden},
+		{name: "remove a permission the actor lacks", actorID: "manager-1", role: auditorRole, permissions: []string{}, wantErr: dto.ErrRolePermissionsForbidden},
+		{name: "edit a role with more permissions", actorID: "manager-1", role: auditorRole, description: &description, wantErr: dto.ErrRolePermissionsForbidden},
+		{name: "own role permissions", actorID: "manager-1", role: roleManagerRole, permissions: []string{domain.PermissionUsersRead, domain.PermissionUsersWrite, domain.PermissionRolesManage, domain.PermissionUsersImpersonate}, wantErr: dto.ErrOwnRoleUpdateForbidden},
+		{name: "own role description", actorID: "manager-1", role: roleManagerRole, description: &description, wantErr: dto.ErrOwnRoleUpdateForbidden},
+		{name: "admin role permissions", actorID: "admin-1", role: domain.AdminRole, permissions: []string{}, wantErr: dto.ErrSystemRoleProtected},
+		{name: "no actor", role: helpdeskRole, permissions: []string{domain.PermissionUsersRead}, wantErr: dto.ErrRolePermissionsForbidden},
+		{name: "unknown role", actorID: "admin-1", role: "DELETED_ROLE", permissions: []string{}, wantErr: dto.ErrRoleNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, audit, service := newTestRoleService(t)
+			before := slices.Clone(uow.roles.permissions[tt.role])
+
+			ctx := context.Background()
+			if tt.actorID != "" {
+				ctx = domain.WithActor(ctx, tt.actorID)
+			}
+
+			input := dto.UpdateRoleInput{Description: tt.description}
+			if tt.permissions != nil {
+				input.Permissions = &tt.permissions
+			}
+
+			role, err := service.Update(ctx, tt.role, input)
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Update() error = %v, want %q", err, tt.wantErr)
+				}
+				if got := uow.roles.permissions[tt.role]; !slices.Equal(got, before) {
+					t.Errorf("permissions = %v, want them unchanged (%v)", got, before)
+				}
+				if uow.commits != 0 || len(audit.events) != 0 {
+					t.Errorf("rejected update committed %d times and recorded %v", uow.commits, audit.eventTypes())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+
+			if got := uow.roles.permissions[tt.role]; !slices.Equal(got, tt.wantPermissions) || !slices.Equal(role.Permissions, tt.wantPermissions) {
+				t.Errorf("permissions = %v, returned %v, want %v", got, role.Permissions, tt.wantPermissions)
+			}
+			if got := audit.eventTypes(); !slices.Equal(got, []string{domain.AuditRoleUpdated}) {
+				t.Errorf("audit events = %v, want [%s]", got, domain.AuditRoleUpdated)
+			}
+		})
+	}
+}
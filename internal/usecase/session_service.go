@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+)
+
+const DefaultSessionCacheTTL = 30 * time.Second
+
+// sessionService resuelve si la sesión de un token sigue activa. El estado se cachea para no consultar
+// Postgres en cada request, y cada consulta actualiza last_seen_at, que por lo tanto tiene la precisión
+// del TTL. Una sesión terminada desde otra instancia deja de aceptarse en como máximo el TTL.
+//
+// Terminate descarta la entrada de inmediato. Los cambios y restablecimientos de contraseña cierran las
+// sesiones con SessionRepository.RevokeByUser sin pasar por aquí, pero también incrementan token_version,
+// que el middleware verifica antes que la sesión, así que sus tokens dejan de aceptarse en el TTL de esa caché.
+type sessionService struct {
+	uowFactory ui.UnitOfWorkFactory
+	audit      interfaces.AuditService
+	// cache guarda si cada sesión sigue activa
+	cache *ttlCache[string, bool]
+}
+
+func NewSessionService(uowFactory ui.UnitOfWorkFactory, audit interfaces.AuditService, ttl time.Duration) interfaces.SessionService {
+	return &sessionService{
+		uowFactory: uowFactory,
+		audit:      audit,
+		cache:      newTTLCache[string, bool](ttl),
+	}
+}
+
+func (s *sessionService) IsActive(ctx context.Context, sessionID string) (bool, error) {
+	now := time.Now()
+
+	active, ok := s.cache.Get(sessionID, now)
+	if !ok {
+		var err error
+		active, err = s.touch(ctx, sessionID, now)
+		if err != nil {
+			return false, err
+		}
+
+		s.cache.Set(sessionID, active, now)
+	}
+
+	return active, nil
+}
+
+func (s *sessionService) ListByUser(ctx context.Context, userID string) ([]*domain.Session, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	if _, err := uow.UserRepository().GetByID(ctx, userID); err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return nil, errors.New(dto.ErrUserNotFound)
+		}
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	sessions, err := uow.SessionRepository().ListActiveByUser(ctx, userID, time.Now())
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	return sessions, nil
+}
+
+func (s *sessionService) Terminate(ctx context.Context, userID, sessionID string) error {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	user, err := uow.UserRepository().GetByID(ctx, userID)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return errors.New(dto.ErrUserNotFound)
+		}
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := authorizeUserWrite(ctx, uow, user); err != nil {
+		if err.Error() == dto.ErrUserWriteForbidden {
+			return err
+		}
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	session, err := uow.SessionRepository().FindByID(ctx, sessionID)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return errors.New(dto.ErrSessionNotFound)
+		}
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	// El ID de la ruta debe corresponder al dueño para no terminar sesiones de otro usuario por error
+	if session.UserID != userID {
+		return errors.New(dto.ErrSessionNotFound)
+	}
+
+	if session.IsRevoked() {
+		return errors.New(dto.ErrSessionAlreadyTerminated)
+	}
+
+	if err := uow.SessionRepository().Revoke(ctx, session.ID, time.Now()); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.RefreshTokenRepository().RevokeFamily(ctx, session.ID); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := s.audit.Record(ctx, uow, &domain.AuditEvent{
+		Type:       domain.AuditSessionTerminated,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &session.UserID,
+		Details:    map[string]any{domain.AuditDetailSession: session.ID},
+	}); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	s.cache.Delete(session.ID)
+
+	return nil
+}
+
+func (s *sessionService) touch(ctx context.Context, sessionID string, now time.Time) (bool, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer uow.Rollback()
+
+	active, err := uow.SessionRepository().Touch(ctx, sessionID, now)
+	if err != nil {
+		return false, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return false, err
+	}
+
+	return active, nil
+}
+
+// createSession registra una sesión con la IP y el user agent de la petición en curso
+func createSession(ctx context.Context, repo ui.SessionRepository, id, userID string, twoFactor bool, expiresAt time.Time) error {
+	meta := domain.RequestMetadataFromContext(ctx)
+	now := time.Now()
+
+	return repo.Create(ctx, &domain.Session{
+		ID:         id,
+		UserID:     userID,
+		IP:         meta.IP,
+		UserAgent:  meta.UserAgent,
+		TwoFactor:  twoFactor,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  expiresAt,
+	})
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+// newTestSessionService crea el servicio con un administrador, una sesión activa de user-1 y otra de user-2
+func newTestSessionService(t *testing.T) (*fakeUnitOfWork, *fakeAuditService, *sessionService) {
+	t.Helper()
+
+	now := time.Now()
+	uow := newFakeUnitOfWork()
+	uow.users.users["admin-1"] = &domain.User{ID: "admin-1", Email: "admin@appfe.com", Role: domain.AdminRole, Status: true}
+	uow.users.users["user-1"] = &domain.User{ID: "user-1", Email: "ana@appfe.com", Role: domain.UserRole, Status: true}
+	uow.sessions.sessions["session-1"] = &domain.Session{ID: "session-1", UserID: "user-1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	uow.sessions.sessions["session-2"] = &domain.Session{ID: "session-2", UserID: "user-2", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	audit := &fakeAuditService{}
+	service := NewSessionService(fakeUnitOfWorkFactory{uow: uow}, audit, time.Minute)
+
+	return uow, audit, service.(*sessionService)
+}
+
+func TestSessionService_Terminate(t *testing.T) {
+	tests := []struct {
+		name       string
+		sessionID  string
+		revoked    bool
+		targetRole string
+		wantErr    string
+	}{
+		{name: "own session", sessionID: "session-1"},
+		{name: "user with more permissions than the actor", sessionID: "session-1", targetRole: domain.AdminRole, wantErr: dto.ErrUserWriteForbidden},
+		{name: "session of another user", sessionID: "session-2", wantErr: dto.ErrSessionNotFound},
+		{name: "unknown session", sessionID: "session-9", wantErr: dto.ErrSessionNotFound},
+		{name: "already terminated", sessionID: "session-1", revoked: true, wantErr: dto.ErrSessionAlreadyTerminated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, audit, service := newTestSessionService(t)
+			uow.roles.permissions[supportRole] = []string{domain.PermissionUsersRead, domain.PermissionUsersWrite}
+			uow.users.users["support-1"] = &domain.User{ID: "support-1", Role: supportRole, Status: true}
+			if tt.targetRole != "" {
+				uow.users.users["user-1"].Role = tt.targetRole
+			}
+			ctx := domain.WithActor(context.Background(), "support-1")
+			if tt.revoked {
+				revokedAt := time.Now().Add(-time.Minute)
+				uow.sessions.sessions["session-1"].RevokedAt = &revokedAt
+			}
+
+			// El estado queda cacheado antes de terminar la sesión
+			if _, err := service.IsActive(ctx, tt.sessionID); err != nil {
+				t.Fatalf("IsActive() error = %v", err)
+			}
+
+			err := service.Terminate(ctx, "user-1", tt.sessionID)
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Terminate() error = %v, want %q", err, tt.wantErr)
+				}
+				if len(uow.refreshTokens.revokedFamilies) != 0 || len(audit.events) != 0 {
+					t.Errorf("rejected termination revoked %v and recorded %v", uow.refreshTokens.revokedFamilies, audit.eventTypes())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Terminate() error = %v", err)
+			}
+
+			if !uow.sessions.sessions[tt.sessionID].IsRevoked() {
+				t.Error("session was not revoked")
+			}
+			if !slices.Equal(uow.refreshTokens.revokedFamilies, []string{tt.sessionID}) {
+				t.Errorf("revoked refresh families = %v, want [%s]", uow.refreshTokens.revokedFamilies, tt.sessionID)
+			}
+			if !slices.Equal(audit.eventTypes(), []string{domain.AuditSessionTerminated}) {
+				t.Errorf("audit events = %v, want %q", audit.eventTypes(), domain.AuditSessionTerminated)
+			}
+
+			active, err := service.IsActive(ctx, tt.sessionID)
+			if err != nil || active {
+				t.Errorf("IsActive() after Terminate = %v, %v, want the cached entry evicted", active, err)
+			}
+		})
+	}
+}
+
+func TestSessionService_IsActiveCache(t *testing.T) {
+	uow, _, service := newTestSessionService(t)
+	ctx := context.Background()
+
+	for range 3 {
+		active, err := service.IsActive(ctx, "session-1")
+		if err != nil || !active {
+			t.Fatalf("IsActive() = %v, %v, want true", active, err)
+		}
+	}
+	if uow.sessions.touches != 1 {
+		t.Errorf("touches = %d, want 1 for cached requests", uow.sessions.touches)
+	}
+
+	// Una terminación hecha por otra instancia se ve recién al vencer la entrada
+	revokedAt := time.Now()
+	uow.sessions.sessions["session-1"].RevokedAt = &revokedAt
+
+	if active, _ := service.IsActive(ctx, "session-1"); !active {
+		t.Error("IsActive() = false before the cached entry expired")
+	}
+
+	service.cache.Set("session-1", true, time.Now().Add(-2*time.Minute))
+
+	if active, _ := service.IsActive(ctx, "session-1"); active {
+		t.Error("IsActive() = true after the cached entry expired")
+	}
+	if uow.sessions.touches != 2 {
+		t.Errorf("touches = %d, want the expired entry refetched", uow.sessions.touches)
+	}
+
+	if active, _ := service.IsActive(ctx, "session-9"); active {
+		t.Error("IsActive() = true for an unknown session")
+	}
+}
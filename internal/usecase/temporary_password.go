@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// Clases de caracteres de la contraseña temporal; se omiten caracteres ambiguos (0/O, 1/l/I)
+var temporaryPasswordClasses = []string{
+	"ABCDEFGHJKLMNPQRSTUVWXYZ",
+	"abcdefghijkmnopqrstuvwxyz",
+	"23456789",
+	"!@#$%&*?-_",
+}
+
+// generateTemporaryPassword genera una contraseña aleatoria que cumple las reglas de complejidad:
+// incluye al menos un carácter de cada clase y el resto se toma del conjunto completo
+func generateTemporaryPassword(length int) (string, error) {
+	all := ""
+	for _, class := range temporaryPasswordClasses {
+		all += class
+	}
+
+	password := make([]byte, 0, length)
+	for _, class := range temporaryPasswordClasses {
+		c, err := randomChar(class)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	for len(password) < length {
+		c, err := randomChar(all)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	// Mezclar para que las clases obligatorias no queden siempre al inicio
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+func randomChar(charset string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+	if err != nil {
+		return 0, err
+	}
+	return charset[n.Int64()], nil
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGenerateTemporaryPassword(t *testing.T) {
+	allowed := strings.Join(temporaryPasswordClasses, "")
+
+	for _, length := range []int{len(temporaryPasswordClasses), temporaryPasswordMinLength, 64} {
+		password, err := generateTemporaryPassword(length)
+		if err != nil {
+			t.Fatalf("generateTemporaryPassword(%d) error = %v", length, err)
+		}
+
+		if len(password) != length {
+			t.Errorf("len = %d, want %d", len(password), length)
+		}
+
+		for _, class := range temporaryPasswordClasses {
+			if !strings.ContainsAny(password, class) {
+				t.Errorf("%q has no character from %q", password, class)
+			}
+		}
+
+		for _, c := range password {
+			if !strings.ContainsRune(allowed, c) {
+				t.Errorf("%q contains %q, outside the allowed characters", password, c)
+			}
+		}
+	}
+}
+
+func TestGenerateTemporaryPassword_IsRandom(t *testing.T) {
+	seen := make(map[string]bool)
+	for range 20 {
+		password, err := generateTemporaryPassword(temporaryPasswordMinLength)
+		if err != nil {
+			t.Fatalf("generateTemporaryPassword() error = %v", err)
+		}
+		if seen[password] {
+			t.Fatalf("generateTemporaryPassword() repeated %q", password)
+		}
+		seen[password] = true
+	}
+}
+
+func TestGenerateTemporaryPassword_SatisfiesPolicy(t *testing.T) {
+	// Sin palabras prohibidas: pueden aparecer por azar y solo interesan las reglas de composición
+	policy := NewPasswordPolicy(PasswordPolicyConfig{
+		MinLength:        24,
+		MaxLength:        128,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSpecial:   true,
+	}, fakeHasher{}, nil)
+
+	for range 20 {
+		password, err := generateTemporaryPassword(policy.TemporaryPasswordLength())
+		if err != nil {
+			t.Fatalf("generateTemporaryPassword() error = %v", err)
+		}
+		if err := policy.Validate(context.Background(), password, nil); err != nil {
+			t.Errorf("Validate(%q) error = %v", password, err)
+		}
+	}
+}
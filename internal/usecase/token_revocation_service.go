@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/pkg/logger"
+)
+
+const DefaultTokenRevocationSyncInterval = 30 * time.Second
+
+// tokenRevocationService mantiene la lista de JWT revocados en Postgres y una copia en memoria
+// que el middleware consulta en cada request. La copia se resincroniza periódicamente para
+// recoger revocaciones hechas por otras instancias y descartar las entradas ya expiradas.
+type tokenRevocationService struct {
+	uowFactory ui.UnitOfWorkFactory
+
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+
+	// now es el reloj de la lista; los tests lo reemplazan para simular el vencimiento de los tokens
+	now func() time.Time
+}
+
+func NewTokenRevocationService(uowFactory ui.UnitOfWorkFactory) interfaces.TokenRevocationService {
+	return &tokenRevocationService{
+		uowFactory: uowFactory,
+		revoked:    make(map[string]time.Time),
+		now:        time.Now,
+	}
+}
+
+func (s *tokenRevocationService) Revoke(ctx context.Context, claims *domain.TokenClaims) error {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return err
+	}
+	defer uow.Rollback()
+
+	revokedToken := &domain.RevokedToken{
+		TokenID:   claims.TokenID,
+		UserID:    claims.UserID,
+		ExpiresAt: claims.ExpiresAt,
+		RevokedAt: s.now(),
+	}
+
+	if err := uow.RevokedTokenRepository().Create(ctx, revokedToken); err != nil {
+		return err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.revoked[claims.TokenID] = claims.ExpiresAt
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *tokenRevocationService) IsRevoked(tokenID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.revoked[tokenID]
+	return ok
+}
+
+// Start carga la lista de revocados y lanza la sincronización periódica hasta que ctx se cancele
+func (s *tokenRevocationService) Start(ctx context.Context, syncInterval time.Duration) error {
+	if err := s.sync(ctx); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(syncInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.sync(ctx); err != nil {
+					logger.Warn(ctx, dto.MsgTokenRevocationSyncFailed, logger.Error("error", err))
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// sync elimina de Postgres las revocaciones expiradas y refresca la copia en memoria con las vigentes
+func (s *tokenRevocationService) sync(ctx context.Context) error {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return err
+	}
+	defer uow.Rollback()
+
+	now := s.now()
+	repo := uow.RevokedTokenRepository()
+
+	if err := repo.DeleteExpired(ctx, now); err != nil {
+		return err
+	}
+
+	active, err := repo.ListActive(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return err
+	}
+
+	revoked := make(map[string]time.Time, len(active))
+	for _, t := range active {
+		revoked[t.TokenID] = t.ExpiresAt
+	}
+
+	s.mu.Lock()
+	// Conservar las revocaciones locales aún vigentes que pudieron registrarse durante la consulta
+	for tokenID, expiresAt := range s.revoked {
+		if expiresAt.After(now) {
+			revoked[tokenID] = expiresAt
+		}
+	}
+	s.revoked = revoked
+	s.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+func TestTokenRevocationService_LogoutAndSync(t *testing.T) {
+	tests := []struct {
+		name        string
+		advance     time.Duration
+		wantRevoked bool
+		wantStored  int
+	}{
+		{name: "denylisted right after logout", wantRevoked: true, wantStored: 1},
+		{name: "kept until exp", advance: 59 * time.Second, wantRevoked: true, wantStored: 1},
+		{name: "pruned at exp", advance: time.Minute},
+		{name: "pruned after exp", advance: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, _, service := newTestAuthService(t)
+			ctx := context.Background()
+
+			clock := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+			revocation := service.revocation.(*tokenRevocationService)
+			revocation.now = func() time.Time { return clock }
+
+			// Otra instancia comparte la base y solo conoce la revocación al sincronizar
+			other := NewTokenRevocationService(fakeUnitOfWorkFactory{uow: uow}).(*tokenRevocationService)
+			other.now = revocation.now
+
+			input := dto.AuthLogoutInput{TokenID: "access-1", UserID: "user-1", ExpiresAt: clock.Add(time.Minute)}
+			if err := service.Logout(ctx, input); err != nil {
+				t.Fatalf("Logout() error = %v", err)
+			}
+			if !revocation.IsRevoked("access-1") {
+				t.Fatal("the logged out jti is not denylisted")
+			}
+			if other.IsRevoked("access-1") {
+				t.Fatal("the other instance knew the jti before syncing")
+			}
+
+			clock = clock.Add(tt.advance)
+			for _, instance := range []*tokenRevocationService{revocation, other} {
+				if err := instance.sync(ctx); err != nil {
+					t.Fatalf("sync() error = %v", err)
+				}
+				if got := instance.IsRevoked("access-1"); got != tt.wantRevoked {
+					t.Errorf("IsRevoked() = %v, want %v", got, tt.wantRevoked)
+				}
+			}
+			if got := len(uow.revokedTokens.tokens); got != tt.wantStored {
+				t.Errorf("stored revocations = %d, want %d", got, tt.wantStored)
+			}
+			if revocation.IsRevoked("access-2") {
+				t.Error("a jti that was never revoked is denylisted")
+			}
+		})
+	}
+}
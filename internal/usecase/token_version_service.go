@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+)
+
+const DefaultTokenVersionCacheTTL = 10 * time.Second
+
+type tokenVersionEntry struct {
+	version int
+	found   bool
+}
+
+// tokenVersionService compara la versión de seguridad embebida en un JWT con la actual del usuario.
+// Las versiones se cachean brevemente para no consultar Postgres en cada request; un cambio de rol,
+// desactivación o contraseña tarda como máximo el TTL de la caché en invalidar los tokens existentes.
+type tokenVersionService struct {
+	uowFactory ui.UnitOfWorkFactory
+	cache      *ttlCache[string, tokenVersionEntry]
+}
+
+func NewTokenVersionService(uowFactory ui.UnitOfWorkFactory, ttl time.Duration) interfaces.TokenVersionService {
+	return &tokenVersionService{
+		uowFactory: uowFactory,
+		cache:      newTTLCache[string, tokenVersionEntry](ttl),
+	}
+}
+
+func (s *tokenVersionService) IsCurrent(ctx context.Context, userID string, tokenVersion int) (bool, error) {
+	now := time.Now()
+
+	entry, ok := s.cache.Get(userID, now)
+	if !ok {
+		var err error
+		entry, err = s.fetch(ctx, userID)
+		if err != nil {
+			return false, err
+		}
+
+		s.cache.Set(userID, entry, now)
+	}
+
+	return entry.found && entry.version == tokenVersion, nil
+}
+
+func (s *tokenVersionService) fetch(ctx context.Context, userID string) (tokenVersionEntry, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return tokenVersionEntry{}, err
+	}
+	defer uow.Rollback()
+
+	version, err := uow.UserRepository().GetTokenVersion(ctx, userID)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return tokenVersionEntry{}, nil
+		}
+		return tokenVersionEntry{}, err
+	}
+
+	return tokenVersionEntry{version: version, found: true}, nil
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+)
+
+// ttlCachePruneThreshold es el tamaño mínimo de la caché a partir del cual se descartan entradas vencidas
+const ttlCachePruneThreshold = 1024
+
+type ttlCacheEntry[V any] struct {
+	value     V
+	fetchedAt time.Time
+}
+
+// ttlCache guarda en memoria valores consultados a Postgres durante ttl. Las claves provienen de los tokens
+// de cada request, así que la caché crece con cada clave distinta: al llegar a pruneAt se descartan las
+// entradas vencidas y el siguiente umbral se fija al doble de las que siguen vigentes, de modo que el
+// recorrido completo se amortiza entre las escrituras.
+type ttlCache[K comparable, V any] struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[K]ttlCacheEntry[V]
+	pruneAt int
+}
+
+func newTTLCache[K comparable, V any](ttl time.Duration) *ttlCache[K, V] {
+	return &ttlCache[K, V]{
+		ttl:     ttl,
+		entries: make(map[K]ttlCacheEntry[V]),
+		pruneAt: ttlCachePruneThreshold,
+	}
+}
+
+// Get retorna el valor de key si se consultó hace como máximo ttl
+func (c *ttlCache[K, V]) Get(key K, now time.Time) (V, bool) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if !ok || now.Sub(entry.fetchedAt) > c.ttl {
+		var zero V
+		return zero, false
+	}
+
+	return entry.value, true
+}
+
+// Set guarda value como consultado en fetchedAt
+func (c *ttlCache[K, V]) Set(key K, value V, fetchedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= c.pruneAt {
+		c.prune(fetchedAt)
+	}
+	c.entries[key] = ttlCacheEntry[V]{value: value, fetchedAt: fetchedAt}
+}
+
+// Delete descarta la entrada de key para que la siguiente lectura vuelva a consultarla
+func (c *ttlCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
+
+// prune debe llamarse con el mutex tomado
+func (c *ttlCache[K, V]) prune(now time.Time) {
+	for key, entry := range c.entries {
+		if now.Sub(entry.fetchedAt) > c.ttl {
+			delete(c.entries, key)
+		}
+	}
+
+	c.pruneAt = max(2*len(c.entries), ttlCachePruneThreshold)
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTTLCache_Get(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		fetchedAt time.Time
+		deleted   bool
+		wantOK    bool
+	}{
+		{name: "fresh entry", fetchedAt: now, wantOK: true},
+		{name: "entry at the ttl", fetchedAt: now.Add(-time.Minute), wantOK: true},
+		{name: "expired entry", fetchedAt: now.Add(-time.Minute - time.Second)},
+		{name: "deleted entry", fetchedAt: now, deleted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newTTLCache[string, int](time.Minute)
+			cache.Set("key", 7, tt.fetchedAt)
+			if tt.deleted {
+				cache.Delete("key")
+			}
+
+			value, ok := cache.Get("key", now)
+			if ok != tt.wantOK || (ok && value != 7) {
+				t.Errorf("Get() = %d, %v, want 7, %v", value, ok, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestTTLCache_Prune(t *testing.T) {
+	now := time.Now()
+	stale := now.Add(-2 * time.Minute)
+
+	tests := []struct {
+		name        string
+		stale       int
+		fresh       int
+		wantEntries int
+		wantPruneAt int
+	}{
+		{name: "below the threshold nothing is pruned", stale: ttlCachePruneThreshold - 1, wantEntries: ttlCachePruneThreshold, wantPruneAt: ttlCachePruneThreshold},
+		{name: "stale entries are dropped at the threshold", stale: ttlCachePruneThreshold, wantEntries: 1, wantPruneAt: ttlCachePruneThreshold},
+		{name: "fresh entries double the next threshold", stale: 24, fresh: ttlCachePruneThreshold - 24, wantEntries: ttlCachePruneThreshold - 23, wantPruneAt: 2 * (ttlCachePruneThreshold - 24)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newTTLCache[string, bool](time.Minute)
+			for i := range tt.stale {
+				cache.Set(fmt.Sprintf("stale-%d", i), true, stale)
+			}
+			for i := range tt.fresh {
+				cache.Set(fmt.Sprintf("fresh-%d", i), true, now)
+			}
+
+			cache.Set("new", true, now)
+
+			if len(cache.entries) != tt.wantEntries || cache.pruneAt != tt.wantPruneAt {
+				t.Errorf("entries = %d, pruneAt = %d, want %d and %d", len(cache.entries), cache.pruneAt, tt.wantEntries, tt.wantPruneAt)
+			}
+		})
+	}
+}
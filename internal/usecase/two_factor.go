@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+func isTwoFactorEnabled(ctx context.Context, repo interfaces.TwoFactorRepository, userID string) (bool, error) {
+	twoFactor, err := repo.Get(ctx, userID)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return twoFactor.Enabled, nil
+}
+
+// verifyTwoFactorCode acepta un código TOTP no usado previamente o, si allowRecovery es true,
+// un código de recuperación pendiente. Los cambios quedan en la transacción del llamador.
+func verifyTwoFactorCode(ctx context.Context, repo interfaces.TwoFactorRepository, provider interfaces.TwoFactorProvider, generator interfaces.TokenGenerator, twoFactor *domain.TwoFactor, code string, allowRecovery bool) (bool, error) {
+	if step, ok := provider.Validate(twoFactor.Secret, code, time.Now()); ok {
+		if step <= twoFactor.LastUsedStep {
+			return false, nil
+		}
+
+		twoFactor.LastUsedStep = step
+		if err := repo.Save(ctx, twoFactor); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if !allowRecovery {
+		return false, nil
+	}
+
+	return repo.UseRecoveryCode(ctx, twoFactor.UserID, generator.Hash(provider.NormalizeRecoveryCode(code)))
+}
+
+// issueRecoveryCodes reemplaza los códigos de recuperación del usuario y retorna los nuevos en texto plano
+func issueRecoveryCodes(ctx context.Context, repo interfaces.TwoFactorRepository, provider interfaces.TwoFactorProvider, generator interfaces.TokenGenerator, userID string) ([]string, error) {
+	codes, err := provider.GenerateRecoveryCodes(domain.RecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = generator.Hash(provider.NormalizeRecoveryCode(code))
+	}
+
+	if err := repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// VerifyTwoFactor completa el segundo paso del inicio de sesión. El desafío es de un solo uso:
+// un código incorrecto cuenta como intento fallido y obliga a iniciar sesión de nuevo.
+func (s *AuthService) VerifyTwoFactor(ctx context.Context, input dto.AuthTwoFactorLoginInput) (*dto.AuthLoginResponse, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	challenge, err := consumeUserToken(ctx, uow.UserTokenRepository(), s.tokenGenerator, domain.TokenPurposeTwoFactorChallenge, input.ChallengeToken)
+	if err != nil {
+		if errors.Is(err, errUserTokenInvalid) {
+			return nil, errors.New(dto.ErrTwoFactorChallengeInvalid)
+		}
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	user, err := uow.UserRepository().GetByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if !user.Status {
+		return nil, errors.New(dto.ErrAccountDisabled)
+	}
+
+	loginInput := dto.AuthLoginInput{Email: user.Email, IP: input.IP}
+	now := time.Now()
+
+	lockoutRepo := uow.AccountLockoutRepository()
+
+	lockout, err := lockoutRepo.Get(ctx, user.ID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if lockout.IsLocked(now) {
+		return nil, s.rejectLogin(ctx, uow, loginInput, &user.ID, dto.ErrAccountLocked)
+	}
+
+	twoFactorRepo := uow.TwoFactorRepository()
+
+	twoFactor, err := twoFactorRepo.Get(ctx, user.ID)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return nil, errors.New(dto.ErrTwoFactorChallengeInvalid)
+		}
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if !twoFactor.Enabled {
+		return nil, errors.New(dto.ErrTwoFactorChallengeInvalid)
+	}
+
+	valid, err := verifyTwoFactorCode(ctx, twoFactorRepo, s.twoFactor, s.tokenGenerator, twoFactor, input.Code, true)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if !valid {
+		lockedUntil, err := s.registerFailedLogin(ctx, lockoutRepo, lockout, now)
+		if err != nil {
+			return nil, errors.New(dto.ErrInternalServer)
+		}
+
+		rejectErr := s.rejectLogin(ctx, uow, loginInput, &user.ID, dto.ErrTwoFactorCodeInvalid)
+		if lockedUntil != nil && rejectErr.Error() == dto.ErrTwoFactorCodeInvalid {
+			s.notifyAccountLocked(ctx, user, *lockedUntil)
+		}
+		return nil, rejectErr
+	}
+
+	return s.completeLogin(ctx, uow, user, lockout, loginInput, domain.TokenOptions{TwoFactor: true})
+}
@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+	usecaseInterfaces "github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+)
+
+type twoFactorService struct {
+	uowFactory     interfaces.UnitOfWorkFactory
+	provider       interfaces.TwoFactorProvider
+	tokenGenerator interfaces.TokenGenerator
+	config         AuthConfig
+}
+
+func NewTwoFactorService(
+	uowFactory interfaces.UnitOfWorkFactory,
+	provider interfaces.TwoFactorProvider,
+	tokenGenerator interfaces.TokenGenerator,
+	config AuthConfig,
+) usecaseInterfaces.TwoFactorService {
+	return &twoFactorService{
+		uowFactory:     uowFactory,
+		provider:       provider,
+		tokenGenerator: tokenGenerator,
+		config:         config,
+	}
+}
+
+// Enroll genera un secreto nuevo pendiente de confirmación. Repetir la inscripción reemplaza el secreto anterior.
+func (s *twoFactorService) Enroll(ctx context.Context, userID string) (*dto.TwoFactorEnrollResponse, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	user, err := uow.UserRepository().GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	enabled, err := isTwoFactorEnabled(ctx, uow.TwoFactorRepository(), userID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	if enabled {
+		return nil, errors.New(dto.ErrTwoFactorAlreadyEnabled)
+	}
+
+	secret, err := s.provider.GenerateSecret()
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.TwoFactorRepository().Save(ctx, &domain.TwoFactor{UserID: userID, Secret: secret}); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	return &dto.TwoFactorEnrollResponse{
+		Secret:     secret,
+		OTPAuthURI: s.provider.ProvisioningURI(secret, user.Email),
+	}, nil
+}
+
+// Confirm activa 2FA con el primer código válido y emite los códigos de recuperación
+func (s *twoFactorService) Confirm(ctx context.Context, userID string, input dto.TwoFactorCodeInput) (*dto.TwoFactorRecoveryCodesResponse, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	repo := uow.TwoFactorRepository()
+
+	twoFactor, err := repo.Get(ctx, userID)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return nil, errors.New(dto.ErrTwoFactorNotEnrolled)
+		}
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if twoFactor.Enabled {
+		return nil, errors.New(dto.ErrTwoFactorAlreadyEnabled)
+	}
+
+	valid, err := verifyTwoFactorCode(ctx, repo, s.provider, s.tokenGenerator, twoFactor, input.Code, false)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	if !valid {
+		return nil, errors.New(dto.ErrTwoFactorCodeInvalid)
+	}
+
+	now := time.Now()
+	twoFactor.Enabled = true
+	twoFactor.ConfirmedAt = &now
+
+	if err := repo.Save(ctx, twoFactor); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	codes, err := issueRecoveryCodes(ctx, repo, s.provider, s.tokenGenerator, userID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	return &dto.TwoFactorRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable desactiva 2FA tras verificar un código TOTP o de recuperación.
+// Si la política lo exige, los roles con permisos de administrador no pueden desactivarlo.
+func (s *twoFactorService) Disable(ctx context.Context, userID string, input dto.TwoFactorCodeInput) error {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	user, err := uow.UserRepository().GetByID(ctx, userID)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	if s.config.RequireAdminTwoFactor {
+		permissions, err := uow.RoleRepository().GetPermissions(ctx, user.Role)
+		if err != nil {
+			return errors.New(dto.ErrInternalServer)
+		}
+		if domain.HasAdminPermission(permissions) {
+			return errors.New(dto.ErrTwoFactorRequiredForAdmins)
+		}
+	}
+
+	repo := uow.TwoFactorRepository()
+
+	twoFactor, err := s.enabledTwoFactor(ctx, repo, userID)
+	if err != nil {
+		return err
+	}
+
+	valid, err := verifyTwoFactorCode(ctx, repo, s.provider, s.tokenGenerator, twoFactor, input.Code, true)
+	if err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+	if !valid {
+		return errors.New(dto.ErrTwoFactorCodeInvalid)
+	}
+
+	if err := repo.Delete(ctx, userID); err != nil {
+		return errors.New(dto.ErrInternalServer)
+	}
+
+	return uow.Commit()
+}
+
+// RegenerateRecoveryCodes invalida los códigos de recuperación vigentes y emite otros nuevos
+func (s *twoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID string, input dto.TwoFactorCodeInput) (*dto.TwoFactorRecoveryCodesResponse, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	defer uow.Rollback()
+
+	repo := uow.TwoFactorRepository()
+
+	twoFactor, err := s.enabledTwoFactor(ctx, repo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	valid, err := verifyTwoFactorCode(ctx, repo, s.provider, s.tokenGenerator, twoFactor, input.Code, false)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+	if !valid {
+		return nil, errors.New(dto.ErrTwoFactorCodeInvalid)
+	}
+
+	codes, err := issueRecoveryCodes(ctx, repo, s.provider, s.tokenGenerator, userID)
+	if err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	return &dto.TwoFactorRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *twoFactorService) enabledTwoFactor(ctx context.Context, repo interfaces.TwoFactorRepository, userID string) (*domain.TwoFactor, error) {
+	twoFactor, err := repo.Get(ctx, userID)
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return nil, errors.New(dto.ErrTwoFactorNotEnabled)
+		}
+		return nil, errors.New(dto.ErrInternalServer)
+	}
+
+	if !twoFactor.Enabled {
+		return nil, errors.New(dto.ErrTwoFactorNotEnabled)
+	}
+
+	return twoFactor, nil
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+const (
+	testTOTPCurrentCode  = "100100"
+	testTOTPPreviousCode = "099099"
+	testTOTPStaleCode    = "098098"
+	testRecoveryCode     = "ABCD-EFGH"
+)
+
+// newTestTwoFactorAuthService crea el servicio con 2FA activo para user-1. El proveedor acepta el código
+// del paso actual (100) y el del anterior (99); testTOTPStaleCode quedó fuera de la ventana.
+func newTestTwoFactorAuthService(t *testing.T, lastUsedStep int64) (*fakeUnitOfWork, *AuthService) {
+	t.Helper()
+
+	uow, _, service := newTestAuthService(t)
+	service.config.MaxFailedLoginAttempts = DefaultMaxFailedLoginAttempts
+	service.twoFactor = fakeTwoFactorProvider{steps: map[string]int64{testTOTPCurrentCode: 100, testTOTPPreviousCode: 99}}
+	uow.twoFactor.factors = map[string]*domain.TwoFactor{"user-1": {UserID: "user-1", Secret: "secret", Enabled: true, LastUsedStep: lastUsedStep}}
+	uow.twoFactor.recoveryCodes = map[string][]string{"user-1": {"sha:abcdefgh"}}
+
+	return uow, service
+}
+
+func TestAuthService_VerifyTwoFactorChallenge(t *testing.T) {
+	for _, tt := range userTokenCases(domain.TokenPurposeTwoFactorChallenge, dto.ErrTwoFactorChallengeInvalid) {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, service := newTestTwoFactorAuthService(t, 98)
+			// Cada intento usa un código nuevo para que el rechazo del segundo se deba solo al desafío
+			codes := []string{testTOTPPreviousCode, testTOTPCurrentCode}
+
+			var response *dto.AuthLoginResponse
+			err := presentUserToken(t, uow, tt, func(token string) error {
+				var err error
+				response, err = service.VerifyTwoFactor(context.Background(), dto.AuthTwoFactorLoginInput{ChallengeToken: token, Code: codes[0]})
+				codes = codes[1:]
+				return err
+			})
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("VerifyTwoFactor() error = %v, want %q", err, tt.wantErr)
+				}
+				if uow.commits != 0 {
+					t.Errorf("rejected challenge committed %d times", uow.commits)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyTwoFactor() error = %v", err)
+			}
+			if session, ok := uow.sessions.sessions[response.SessionID]; !ok || !session.TwoFactor {
+				t.Errorf("session = %+v, want a two-factor session", session)
+			}
+		})
+	}
+}
+
+func TestAuthService_VerifyTwoFactorCode(t *testing.T) {
+	lockedUntil := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name         string
+		lastUsedStep int64
+		code         string
+		disabled     bool
+		locked       bool
+		wantErr      string
+		wantStep     int64
+		wantFailures int
+		wantRecovery int
+	}{
+		{name: "code of the current step", lastUsedStep: 98, code: testTOTPCurrentCode, wantStep: 100, wantRecovery: 1},
+		{name: "code of the previous step within the window", lastUsedStep: 98, code: testTOTPPreviousCode, wantStep: 99, wantRecovery: 1},
+		{name: "replayed code", lastUsedStep: 100, code: testTOTPCurrentCode, wantErr: dto.ErrTwoFactorCodeInvalid, wantStep: 100, wantFailures: 1, wantRecovery: 1},
+		{name: "earlier code after a later one was used", lastUsedStep: 100, code: testTOTPPreviousCode, wantErr: dto.ErrTwoFactorCodeInvalid, wantStep: 100, wantFailures: 1, wantRecovery: 1},
+		{name: "code outside the window", lastUsedStep: 98, code: testTOTPStaleCode, wantErr: dto.ErrTwoFactorCodeInvalid, wantStep: 98, wantFailures: 1, wantRecovery: 1},
+		{name: "recovery code", lastUsedStep: 98, code: testRecoveryCode, wantStep: 98},
+		{name: "recovery code typed loosely", lastUsedStep: 98, code: " abcd-efgh ", wantStep: 98},
+		{name: "two-factor disabled after the challenge", lastUsedStep: 98, code: testTOTPCurrentCode, disabled: true, wantErr: dto.ErrTwoFactorChallengeInvalid, wantStep: 98, wantRecovery: 1},
+		{name: "locked account", lastUsedStep: 98, code: testTOTPCurrentCode, locked: true, wantErr: dto.ErrAccountLocked, wantStep: 98, wantRecovery: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, service := newTestTwoFactorAuthService(t, tt.lastUsedStep)
+			uow.twoFactor.factors["user-1"].Enabled = !tt.disabled
+			if tt.locked {
+				uow.lockouts.lockouts = map[string]*domain.AccountLockout{"user-1": {UserID: "user-1", LockoutCount: 1, LockedUntil: &lockedUntil}}
+			}
+
+			challenge := userTokenCase{purpose: domain.TokenPurposeTwoFactorChallenge, expiresIn: domain.TwoFactorChallengeTTL}
+			err := presentUserToken(t, uow, challenge, func(token string) error {
+				_, err := service.VerifyTwoFactor(context.Background(), dto.AuthTwoFactorLoginInput{ChallengeToken: token, Code: tt.code})
+				return err
+			})
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("VerifyTwoFactor() error = %v, want %q", err, tt.wantErr)
+				}
+				if len(uow.sessions.sessions) != 0 {
+					t.Error("a rejected code opened a session")
+				}
+			} else if err != nil {
+				t.Fatalf("VerifyTwoFactor() error = %v", err)
+			}
+
+			if got := uow.twoFactor.factors["user-1"].LastUsedStep; got != tt.wantStep {
+				t.Errorf("last used step = %d, want %d", got, tt.wantStep)
+			}
+			if got := len(uow.twoFactor.recoveryCodes["user-1"]); got != tt.wantRecovery {
+				t.Errorf("pending recovery codes = %d, want %d", got, tt.wantRecovery)
+			}
+
+			var failures int
+			if lockout, ok := uow.lockouts.lockouts["user-1"]; ok {
+				failures = lockout.FailedCount
+			}
+			if failures != tt.wantFailures {
+				t.Errorf("failed attempts = %d, want %d", failures, tt.wantFailures)
+			}
+			if tt.wantErr == "" && len(uow.lockouts.resets) != 0 {
+				t.Errorf("lockout resets = %v, want none for a clean account", uow.lockouts.resets)
+			}
+		})
+	}
+}
+
+// Un código aceptado no sirve para un segundo inicio de sesión aunque siga dentro de la ventana
+func TestAuthService_VerifyTwoFactorReplayAcrossLogins(t *testing.T) {
+	uow, service := newTestTwoFactorAuthService(t, 98)
+
+	for i, wantErr := range []string{"", dto.ErrTwoFactorCodeInvalid} {
+		challenge, err := issueUserToken(context.Background(), uow.userTokens, service.tokenGenerator, "user-1", domain.TokenPurposeTwoFactorChallenge, domain.TwoFactorChallengeTTL)
+		if err != nil {
+			t.Fatalf("issueUserToken() error = %v", err)
+		}
+
+		_, err = service.VerifyTwoFactor(context.Background(), dto.AuthTwoFactorLoginInput{ChallengeToken: challenge, Code: testTOTPCurrentCode})
+
+		if wantErr == "" {
+			if err != nil {
+				t.Fatalf("login %d: VerifyTwoFactor() error = %v", i, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != wantErr {
+			t.Fatalf("login %d: VerifyTwoFactor() error = %v, want %q", i, err, wantErr)
+		}
+	}
+}
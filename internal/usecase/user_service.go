@@ -3,7 +3,9 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -11,102 +13,243 @@ import (
 	ui "github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
 	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
 	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/pkg/logger"
+	"github.com/google/uuid"
 )
 
 type userService struct {
 	uowFactory       ui.UnitOfWorkFactory
 	hasher           ui.PasswordHasher
+	passwordPolicy   *PasswordPolicy
+	audit            interfaces.AuditService
 	messagingService ui.MessagingService
 	templateService  ui.TemplateService
+	tokenGenerator   ui.TokenGenerator
+	jwtService       ui.JWTService
+	config           AuthConfig
 }
 
 func NewUserService(
 	uowFactory ui.UnitOfWorkFactory,
 	h ui.PasswordHasher,
 	messagingService ui.MessagingService,
-	templateService ui.TemplateService) interfaces.UserService {
+	templateService ui.TemplateService,
+	tokenGenerator ui.TokenGenerator,
+	jwtService ui.JWTService,
+	passwordPolicy *PasswordPolicy,
+	audit interfaces.AuditService,
+	config AuthConfig) interfaces.UserService {
 	return &userService{
 		uowFactory:       uowFactory,
 		hasher:           h,
+		passwordPolicy:   passwordPolicy,
+		audit:            audit,
 		messagingService: messagingService,
 		templateService:  templateService,
+		tokenGenerator:   tokenGenerator,
+		jwtService:       jwtService,
+		config:           config,
 	}
 }
 
-func (s *userService) Create(ctx context.Context, u *domain.User) error {
+// Create registra al usuario sin contraseña y emite una invitación de un solo uso. El usuario define su
+// contraseña desde el enlace, lo que además valida su correo.
+func (s *userService) Create(ctx context.Context, u *domain.User) (*dto.UserInvitation, error) {
 	uow, err := s.uowFactory.New(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer uow.Rollback()
 
-	validatedRole, err := domain.ValidateRole(u.Role)
+	validatedRole, err := resolveRole(ctx, uow.RoleRepository(), u.Role)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	u.Role = validatedRole
 
+	if err := authorizeRoleAssignment(ctx, uow, "", u.Role); err != nil {
+		return nil, err
+	}
+
 	u.CreatedAt = time.Now()
 	u.Status = true
 	u.Name = strings.ToUpper(strings.TrimSpace(u.Name))
-	u.EmailValidated = true
+	u.Password = nil
+	u.EmailValidated = false
 
-	// Capturar la contraseña original antes del hash para enviarla por email
-	var originalPassword string
-	if u.Password != nil {
-		originalPassword = *u.Password
-		hashed, err := s.hasher.Hash(*u.Password)
-		if err != nil {
-			return err
-		}
-		u.Password = &hashed
+	if err := uow.UserRepository().Create(ctx, u); err != nil {
+		return nil, err
 	}
 
-	if err := uow.UserRepository().Create(ctx, u); err != nil {
-		return err
+	invitation, content, err := s.issueInvitation(ctx, uow.UserTokenRepository(), u)
+	if err != nil {
+		return nil, err
 	}
 
-	// Enviar email de bienvenida después de crear el usuario
-	if s.messagingService != nil && s.templateService != nil {
-		welcomeContent, err := s.templateService.RenderWelcomeEmail(u.Name, originalPassword)
-		if err != nil {
-			// Log del error pero no fallar la creación del usuario
-			return uow.Commit()
-		}
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
 
-		// Enviar email de forma asíncrona para no bloquear la respuesta
-		go func() {
-			if err := s.messagingService.SendEmail(context.Background(), u.Email, dto.WelcomeEmailSubject, welcomeContent); err != nil {
-				// Log del error pero no fallar la creación del usuario
-				// El logger debería estar disponible en el servicio de mensajería
-			}
-		}()
+	s.sendInvitation(ctx, u, content)
+
+	return invitation, nil
+}
+
+// ResendInvitation emite un nuevo enlace de invitación e invalida el anterior, mientras el usuario no haya
+// establecido su contraseña
+func (s *userService) ResendInvitation(ctx context.Context, id string) (*dto.UserInvitation, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer uow.Rollback()
 
-	return uow.Commit()
+	user, err := uow.UserRepository().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeUserWrite(ctx, uow, user); err != nil {
+		return nil, err
+	}
+
+	if user.Password != nil {
+		return nil, errors.New(dto.ErrInvitationAlreadyAccepted)
+	}
+
+	if !user.Status {
+		return nil, errors.New(dto.ErrAccountDisabled)
+	}
+
+	invitation, content, err := s.issueInvitation(ctx, uow.UserTokenRepository(), user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.sendInvitation(ctx, user, content)
+
+	return invitation, nil
 }
 
-func (s *userService) UpdateByID(ctx context.Context, input ui.UpdateUserInput) error {
+// issueInvitation persiste el token de invitación y renderiza el email de bienvenida. Sin servicio de
+// correo no hay email que renderizar y el enlace se retorna en la invitación.
+func (s *userService) issueInvitation(ctx context.Context, repo ui.UserTokenRepository, user *domain.User) (*dto.UserInvitation, string, error) {
+	plainToken, err := issueUserToken(ctx, repo, s.tokenGenerator, user.ID, domain.TokenPurposeInvitation, s.config.InvitationTTL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	link := s.config.buildFrontendLink(dto.InvitationPath, plainToken)
+	invitation := &dto.UserInvitation{ExpiresAt: time.Now().Add(s.config.InvitationTTL)}
+
+	if s.messagingService == nil || s.templateService == nil {
+		invitation.Link = link
+		return invitation, "", nil
+	}
+
+	content, err := s.templateService.RenderWelcomeEmail(user.Name, link, invitation.ExpiresAt.Format(emailDateLayout))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return invitation, content, nil
+}
+
+// sendInvitation envía el email después de confirmar la transacción; un error de envío no falla la operación
+func (s *userService) sendInvitation(ctx context.Context, user *domain.User, content string) {
+	if content == "" {
+		logger.Warn(ctx, dto.MsgInvitationLinkNotEmailed, logger.String("user_id", user.ID))
+		return
+	}
+
+	sendEmailAsync(s.messagingService, user.Email, dto.WelcomeEmailSubject, content, dto.MsgWelcomeEmailFailed)
+}
+
+// UpdateByID aplica los cambios del administrador, que debe tener todos los permisos del rol del usuario.
+// Una contraseña definida por el administrador se hashea y obliga al usuario a reemplazarla en su próximo
+// inicio de sesión. Un cambio de rol exige además los permisos del rol nuevo, y nadie puede cambiar el suyo.
+func (s *userService) UpdateByID(ctx context.Context, input *dto.UpdateUserInput) error {
 	uow, err := s.uowFactory.New(ctx)
 	if err != nil {
 		return err
 	}
 	defer uow.Rollback()
 
+	user, err := uow.UserRepository().GetByID(ctx, input.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeUserWrite(ctx, uow, user); err != nil {
+		return err
+	}
+
+	var events []*domain.AuditEvent
+
+	if input.Password != nil {
+		hashed, err := s.passwordPolicy.Apply(ctx, uow.PasswordHistoryRepository(), user, *input.Password)
+		if err != nil {
+			return err
+		}
+		mustChange := true
+		input.Password = &hashed
+		input.MustChangePassword = &mustChange
+
+		events = append(events, &domain.AuditEvent{Type: domain.AuditPasswordAdminSet})
+	}
+
+	if role, ok := input.FieldsToUpdate()["role"].(string); ok {
+		if err := ensureRoleExists(ctx, uow.RoleRepository(), role); err != nil {
+			return err
+		}
+
+		if role != user.Role {
+			// Un cambio de rol exige una sesión de usuario: una clave filtrada no debe poder promover cuentas
+			if domain.RequestMetadataFromContext(ctx).APIKeyID != "" {
+				return errors.New(dto.ErrAPIKeyRoleChange)
+			}
+
+			if err := authorizeRoleAssignment(ctx, uow, user.ID, user.Role, role); err != nil {
+				return err
+			}
+
+			events = append(events, &domain.AuditEvent{
+				Type: domain.AuditUserRoleChanged,
+				Details: map[string]any{
+					domain.AuditDetailPreviousRole: user.Role,
+					domain.AuditDetailNewRole:      role,
+				},
+			})
+		}
+	}
+
 	if err := uow.UserRepository().UpdateByID(ctx, input); err != nil {
 		return err
 	}
+
+	for _, event := range events {
+		event.TargetType = domain.AuditTargetUser
+		event.TargetID = &user.ID
+		if err := s.audit.Record(ctx, uow, event); err != nil {
+			return err
+		}
+	}
+
 	return uow.Commit()
 }
 
-func (s *userService) GetAll(ctx context.Context, pagination *domain.Pagination) (*domain.PaginatedResult[*domain.User], error) {
+func (s *userService) GetAll(ctx context.Context, filter domain.UserFilter, pagination *domain.Pagination) (*domain.PaginatedResult[*domain.User], error) {
 	uow, err := s.uowFactory.New(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer uow.Rollback()
 
-	users, total, err := uow.UserRepository().GetAll(ctx, pagination)
+	users, total, err := uow.UserRepository().GetAll(ctx, filter, pagination)
 	if err != nil {
 		return nil, err
 	}
@@ -119,10 +262,19 @@ func (s *userService) GetAll(ctx context.Context, pagination *domain.Pagination)
 		pagination = domain.NewPagination(1, int(total), "")
 	}
 
+	if pagination.CursorMode {
+		return domain.NewCursorPaginatedResult(users, pagination, total, userCursor), nil
+	}
+
 	result := domain.NewPaginatedResult(users, pagination, total)
 	return result, nil
 }
 
+// userCursor es la posición de un usuario en la paginación por cursor
+func userCursor(u *domain.User) domain.Cursor {
+	return domain.Cursor{CreatedAt: u.CreatedAt, ID: u.ID}
+}
+
 func (s *userService) GetByID(ctx context.Context, id string) (*domain.User, error) {
 	uow, err := s.uowFactory.New(ctx)
 	if err != nil {
@@ -154,12 +306,243 @@ func (s *userService) Delete(ctx context.Context, id string) error {
 	}
 	defer uow.Rollback()
 
+	user, err := uow.UserRepository().GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeUserWrite(ctx, uow, user); err != nil {
+		return err
+	}
+
 	if err := uow.UserRepository().Delete(ctx, id); err != nil {
 		return err
 	}
+
+	if err := s.audit.Record(ctx, uow, &domain.AuditEvent{
+		Type:       domain.AuditUserDeleted,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &id,
+	}); err != nil {
+		return err
+	}
+
 	return uow.Commit()
 }
 
+// Unlock levanta el bloqueo por intentos fallidos y reinicia el contador de backoff
+func (s *userService) Unlock(ctx context.Context, id string) error {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return err
+	}
+	defer uow.Rollback()
+
+	user, err := uow.UserRepository().GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeUserWrite(ctx, uow, user); err != nil {
+		return err
+	}
+
+	if err := uow.AccountLockoutRepository().Reset(ctx, id); err != nil {
+		return err
+	}
+	return uow.Commit()
+}
+
+// ForcePasswordReset invalida la contraseña actual a pedido de un administrador: la reemplaza, cierra todas
+// las sesiones, marca la cuenta con cambio obligatorio y entrega una contraseña temporal o un enlace de restablecimiento.
+// Como la contraseña temporal se entrega al actor, su rol debe cubrir el del usuario igual que al suplantarlo.
+func (s *userService) ForcePasswordReset(ctx context.Context, id string, input dto.AdminPasswordResetInput) (*dto.AdminPasswordResetResponse, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback()
+
+	user, err := uow.UserRepository().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeUserWrite(ctx, uow, user); err != nil {
+		return nil, err
+	}
+
+	if !user.Status {
+		return nil, errors.New(dto.ErrAccountDisabled)
+	}
+
+	response := &dto.AdminPasswordResetResponse{Method: input.Method}
+	if response.Method == "" {
+		response.Method = dto.AdminPasswordResetTemporary
+	}
+
+	// Ambos métodos reemplazan el hash para que la contraseña anterior deje de servir de inmediato. Con el
+	// enlace se guarda el de una contraseña aleatoria que nadie conoce hasta que el usuario elija la suya.
+	replacement, err := generateTemporaryPassword(s.passwordPolicy.TemporaryPasswordLength())
+	if err != nil {
+		return nil, err
+	}
+
+	hashed, err := s.hasher.Hash(replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	mustChange := true
+	update := &dto.UpdateUserInput{ID: user.ID, Password: &hashed, MustChangePassword: &mustChange}
+
+	// La contraseña temporal es una contraseña más del usuario; la aleatoria del enlace nadie la conoce y
+	// el historial recibe la que el usuario elija al restablecerla
+	if response.Method == dto.AdminPasswordResetTemporary {
+		if err := s.passwordPolicy.Record(ctx, uow.PasswordHistoryRepository(), user.ID, hashed); err != nil {
+			return nil, err
+		}
+		response.TemporaryPassword = replacement
+	}
+
+	if err := uow.UserRepository().UpdateByID(ctx, update); err != nil {
+		return nil, err
+	}
+
+	if err := uow.RefreshTokenRepository().RevokeByUser(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	if err := uow.SessionRepository().RevokeByUser(ctx, user.ID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := uow.AccountLockoutRepository().Reset(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.audit.Record(ctx, uow, &domain.AuditEvent{
+		Type:       domain.AuditPasswordAdminReset,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &user.ID,
+		Details:    map[string]any{domain.AuditDetailMethod: response.Method},
+	}); err != nil {
+		return nil, err
+	}
+
+	var resetContent string
+	if response.Method == dto.AdminPasswordResetLink {
+		plainToken, err := issueUserToken(ctx, uow.UserTokenRepository(), s.tokenGenerator, user.ID, domain.TokenPurposePasswordReset, domain.PasswordResetTokenTTL)
+		if err != nil {
+			return nil, err
+		}
+
+		link := s.config.buildFrontendLink(dto.PasswordResetPath, plainToken)
+		expiresAt := time.Now().Add(domain.PasswordResetTokenTTL)
+		response.ExpiresAt = &expiresAt
+
+		if s.messagingService == nil || s.templateService == nil {
+			response.ResetLink = link
+		} else {
+			resetContent, err = s.templateService.RenderPasswordResetEmail(user.Name, link)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+
+	if resetContent != "" {
+		sendEmailAsync(s.messagingService, user.Email, dto.PasswordResetEmailSubject, resetContent, dto.MsgPasswordResetEmailFailed)
+	}
+
+	return response, nil
+}
+
+// Impersonate emite un token de acceso de corta duración con el que actorID actúa como el usuario id.
+// Solo se puede suplantar a usuarios cuyo rol no tenga permisos que el del actor no tenga, y nunca a
+// quien también puede suplantar ni a cuentas deshabilitadas.
+func (s *userService) Impersonate(ctx context.Context, actorID, id string) (*dto.ImpersonationResponse, error) {
+	uow, err := s.uowFactory.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer uow.Rollback()
+
+	user, err := uow.UserRepository().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := uow.UserRepository().GetByID(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	covered, err := rolesCoveredBy(ctx, uow.RoleRepository(), actor.Role, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	if !covered {
+		return nil, errors.New(dto.ErrImpersonationRoleForbidden)
+	}
+
+	role, err := uow.RoleRepository().GetByName(ctx, user.Role)
+	if err != nil && err.Error() != dto.ErrNoRowsFound {
+		return nil, err
+	}
+	if role != nil && slices.Contains(role.Permissions, domain.PermissionUsersImpersonate) {
+		return nil, errors.New(dto.ErrImpersonationAdminForbidden)
+	}
+
+	if !user.Status {
+		return nil, errors.New(dto.ErrAccountDisabled)
+	}
+
+	// La suplantación es una sesión más del usuario: aparece en su inventario y puede terminarse desde él
+	sessionID := uuid.NewString()
+
+	token, err := s.jwtService.GenerateToken(*user, domain.TokenOptions{ActorID: actorID, TTL: s.config.ImpersonationTTL, SessionID: sessionID})
+	if err != nil {
+		return nil, errors.New(dto.ErrTokenGenerationFailed)
+	}
+
+	claims, err := s.jwtService.ValidateToken(token)
+	if err != nil {
+		return nil, errors.New(dto.ErrTokenGenerationFailed)
+	}
+
+	if err := createSession(ctx, uow.SessionRepository(), sessionID, user.ID, false, claims.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	if err := s.audit.Record(ctx, uow, &domain.AuditEvent{
+		Type:       domain.AuditImpersonationStarted,
+		ActorID:    &actorID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &user.ID,
+		Details:    map[string]any{domain.AuditDetailSession: sessionID},
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(); err != nil {
+		return nil, err
+	}
+
+	user.Password = nil
+
+	return &dto.ImpersonationResponse{
+		Token:     token,
+		ExpiresAt: claims.ExpiresAt,
+		ActorID:   actorID,
+		User:      *user,
+	}, nil
+}
+
 func (s *userService) CreateInitialAdmin(ctx context.Context) error {
 	adminEmail := os.Getenv("ADMIN_EMAIL")
 	adminName := os.Getenv("ADMIN_NAME")
@@ -178,20 +561,26 @@ func (s *userService) CreateInitialAdmin(ctx context.Context) error {
 		return errors.New("ADMIN_PASSWORD environment variable is required")
 	}
 
+	now := time.Now()
+	adminUser := &domain.User{
+		Name:              adminName,
+		Email:             adminEmail,
+		Role:              domain.AdminRole,
+		Status:            true,
+		EmailValidated:    true,
+		CreatedAt:         now,
+		PasswordChangedAt: &now,
+	}
+
+	if err := s.passwordPolicy.Validate(ctx, adminPassword, adminUser); err != nil {
+		return fmt.Errorf("ADMIN_PASSWORD no cumple la política de contraseñas: %w", err)
+	}
+
 	hashedPassword, err := s.hasher.Hash(adminPassword)
 	if err != nil {
 		return err
 	}
-
-	adminUser := &domain.User{
-		Name:           adminName,
-		Email:          adminEmail,
-		Password:       &hashedPassword,
-		Role:           domain.AdminRole,
-		Status:         true,
-		EmailValidated: true,
-		CreatedAt:      time.Now(),
-	}
+	adminUser.Password = &hashedPassword
 
 	uow, err := s.uowFactory.New(ctx)
 	if err != nil {
@@ -203,5 +592,9 @@ func (s *userService) CreateInitialAdmin(ctx context.Context) error {
 		return err
 	}
 
+	if err := s.passwordPolicy.Record(ctx, uow.PasswordHistoryRepository(), adminUser.ID, hashedPassword); err != nil {
+		return err
+	}
+
 	return uow.Commit()
 }
@@ -0,0 +1,331 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+const supportRole = "SUPPORT_ROLE"
+
+// newTestUserService crea el servicio con un administrador, un agente de soporte (users:write sin gestión
+// de roles) y un usuario básico
+func newTestUserService(t *testing.T) (*fakeUnitOfWork, *fakeAuditService, *userService) {
+	t.Helper()
+
+	uow := newFakeUnitOfWork()
+	uow.roles.permissions[supportRole] = []string{domain.PermissionUsersRead, domain.PermissionUsersWrite}
+	uow.users.users["admin-1"] = &domain.User{ID: "admin-1", Email: "admin@appfe.com", Role: domain.AdminRole, Status: true}
+	uow.users.users["support-1"] = &domain.User{ID: "support-1", Email: "soporte@appfe.com", Role: supportRole, Status: true}
+	uow.users.users["user-1"] = &domain.User{ID: "user-1", Email: "ana@appfe.com", Role: domain.UserRole, Status: true}
+
+	hasher := fakeHasher{}
+	audit := &fakeAuditService{}
+	config := AuthConfig{FrontendURL: "https://portal.appfe.test"}
+	service := NewUserService(fakeUnitOfWorkFactory{uow: uow}, hasher, nil, nil, newFakeTokenGenerator(), newFakeJWTService(), newTestPasswordPolicy(hasher), audit, config)
+
+	return uow, audit, service.(*userService)
+}
+
+func TestUserService_UpdateByIDRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		actorID  string
+		apiKeyID string
+		targetID string
+		role     string
+		wantErr  string
+		wantRole string
+	}{
+		{name: "admin grants a custom role", actorID: "admin-1", targetID: "user-1", role: supportRole, wantRole: supportRole},
+		{name: "role within the actor permissions", actorID: "support-1", targetID: "user-1", role: supportRole, wantRole: supportRole},
+		{name: "grant a role with more permissions", actorID: "support-1", targetID: "user-1", role: domain.AdminRole, wantErr: dto.ErrRoleAssignmentForbidden},
+		{name: "demote a more privileged user", actorID: "support-1", targetID: "admin-1", role: domain.UserRole, wantErr: dto.ErrUserWriteForbidden},
+		{name: "own role", actorID: "admin-1", targetID: "admin-1", role: domain.UserRole, wantErr: dto.ErrOwnRoleChangeForbidden},
+		{name: "no actor", targetID: "user-1", role: supportRole, wantErr: dto.ErrUserWriteForbidden},
+		{name: "through an API key", actorID: "admin-1", apiKeyID: "key-1", targetID: "user-1", role: supportRole, wantErr: dto.ErrAPIKeyRoleChange},
+		{name: "unknown role", actorID: "admin-1", targetID: "user-1", role: "DELETED_ROLE", wantErr: domain.ErrInvalidRole},
+		{name: "unchanged role needs no role check", actorID: "support-1", targetID: "user-1", role: domain.UserRole, wantRole: domain.UserRole},
+		{name: "unchanged role of a more privileged user", actorID: "support-1", targetID: "admin-1", role: domain.AdminRole, wantErr: dto.ErrUserWriteForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, audit, service := newTestUserService(t)
+			previousRole := uow.users.users[tt.targetID].Role
+
+			ctx := context.Background()
+			if tt.apiKeyID != "" {
+				ctx = domain.WithAPIKey(ctx, tt.actorID, tt.apiKeyID)
+			} else if tt.actorID != "" {
+				ctx = domain.WithActor(ctx, tt.actorID)
+			}
+
+			err := service.UpdateByID(ctx, &dto.UpdateUserInput{ID: tt.targetID, Role: stringPtr(tt.role)})
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("UpdateByID() error = %v, want %q", err, tt.wantErr)
+				}
+				if got := uow.users.users[tt.targetID].Role; got != previousRole {
+					t.Errorf("role = %s, want it unchanged (%s)", got, previousRole)
+				}
+				if uow.commits != 0 {
+					t.Error("UpdateByID() committed a rejected change")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("UpdateByID() error = %v", err)
+			}
+			if got := uow.users.users[tt.targetID].Role; got != tt.wantRole {
+				t.Errorf("role = %s, want %s", got, tt.wantRole)
+			}
+
+			var wantEvents []string
+			if tt.wantRole != previousRole {
+				wantEvents = []string{domain.AuditUserRoleChanged}
+			}
+			if got := audit.eventTypes(); !slices.Equal(got, wantEvents) {
+				t.Errorf("audit events = %v, want %v", got, wantEvents)
+			}
+		})
+	}
+}
+
+func TestUserService_WritesRequireRoleCoverage(t *testing.T) {
+	writes := map[string]func(ctx context.Context, service *userService, id string) error{
+		"update": func(ctx context.Context, service *userService, id string) error {
+			return service.UpdateByID(ctx, &dto.UpdateUserInput{ID: id, Password: stringPtr(testNewPassword)})
+		},
+		"delete": func(ctx context.Context, service *userService, id string) error {
+			return service.Delete(ctx, id)
+		},
+		"unlock": func(ctx context.Context, service *userService, id string) error {
+			return service.Unlock(ctx, id)
+		},
+		"resend invitation": func(ctx context.Context, service *userService, id string) error {
+			_, err := service.ResendInvitation(ctx, id)
+			return err
+		},
+	}
+
+	tests := []struct {
+		name     string
+		actorID  string
+		targetID string
+		wantErr  string
+	}{
+		{name: "target covered by the actor", actorID: "support-1", targetID: "user-1"},
+		{name: "admin on a custom role", actorID: "admin-1", targetID: "support-1"},
+		{name: "target with more permissions", actorID: "support-1", targetID: "admin-1", wantErr: dto.ErrUserWriteForbidden},
+		{name: "no actor", targetID: "user-1", wantErr: dto.ErrUserWriteForbidden},
+	}
+
+	for write, call := range writes {
+		for _, tt := range tests {
+			t.Run(write+"/"+tt.name, func(t *testing.T) {
+				uow, audit, service := newTestUserService(t)
+				before := *uow.users.users[tt.targetID]
+
+				ctx := context.Background()
+				if tt.actorID != "" {
+					ctx = domain.WithActor(ctx, tt.actorID)
+				}
+
+				err := call(ctx, service, tt.targetID)
+
+				if tt.wantErr == "" {
+					if err != nil {
+						t.Fatalf("%s error = %v", write, err)
+					}
+					return
+				}
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("%s error = %v, want %q", write, err, tt.wantErr)
+				}
+				if after, ok := uow.users.users[tt.targetID]; !ok || after.Password != before.Password || len(uow.lockouts.resets) != 0 {
+					t.Errorf("%s changed the target after being rejected", write)
+				}
+				if uow.commits != 0 || len(audit.events) != 0 || len(uow.userTokens.tokens) != 0 {
+					t.Errorf("%s committed %d times, recorded %v and issued %d tokens", write, uow.commits, audit.eventTypes(), len(uow.userTokens.tokens))
+				}
+			})
+		}
+	}
+}
+
+func TestUserService_CreateRole(t *testing.T) {
+	_, _, service := newTestUserService(t)
+
+	ctx := domain.WithActor(context.Background(), "support-1")
+	_, err := service.Create(ctx, &domain.User{Name: "Nuevo", Email: "nuevo@appfe.com", Role: domain.AdminRole})
+	if err == nil || err.Error() != dto.ErrRoleAssignmentForbidden {
+		t.Fatalf("Create() error = %v, want %q", err, dto.ErrRoleAssignmentForbidden)
+	}
+}
+
+func TestUserService_ForcePasswordReset(t *testing.T) {
+	tests := []struct {
+		name     string
+		actorID  string
+		targetID string
+		method   string
+		disabled bool
+		wantErr  string
+		wantLink bool
+	}{
+		{name: "default method is a temporary password", actorID: "admin-1", targetID: "user-1"},
+		{name: "temporary password", actorID: "admin-1", targetID: "user-1", method: dto.AdminPasswordResetTemporary},
+		{name: "reset link", actorID: "admin-1", targetID: "user-1", method: dto.AdminPasswordResetLink, wantLink: true},
+		{name: "actor covering the target role", actorID: "support-1", targetID: "user-1"},
+		{name: "target with more permissions", actorID: "support-1", targetID: "admin-1", wantErr: dto.ErrUserWriteForbidden},
+		{name: "target with more permissions through a link", actorID: "support-1", targetID: "admin-1", method: dto.AdminPasswordResetLink, wantErr: dto.ErrUserWriteForbidden},
+		{name: "disabled account", actorID: "admin-1", targetID: "user-1", method: dto.AdminPasswordResetLink, disabled: true, wantErr: dto.ErrAccountDisabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, audit, service := newTestUserService(t)
+			user := uow.users.users[tt.targetID]
+			user.Password = stringPtr(fakeHashPrefix + testCurrentPassword)
+			user.Status = !tt.disabled
+			ctx := domain.WithActor(context.Background(), tt.actorID)
+
+			response, err := service.ForcePasswordReset(ctx, tt.targetID, dto.AdminPasswordResetInput{Method: tt.method})
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ForcePasswordReset() error = %v, want %q", err, tt.wantErr)
+				}
+				if *user.Password != fakeHashPrefix+testCurrentPassword || uow.commits != 0 || response != nil {
+					t.Error("ForcePasswordReset() changed or exposed a rejected account")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ForcePasswordReset() error = %v", err)
+			}
+
+			if err := (fakeHasher{}).Verify(*user.Password, testCurrentPassword); err == nil {
+				t.Error("the previous password still works after the reset")
+			}
+			if !user.MustChangePassword {
+				t.Error("MustChangePassword not set")
+			}
+
+			resetTokens := uow.userTokens.active(domain.TokenPurposePasswordReset)
+			if tt.wantLink {
+				if response.TemporaryPassword != "" {
+					t.Error("the link method returned a temporary password")
+				}
+				if len(resetTokens) != 1 || !strings.Contains(response.ResetLink, "token=plain-token-1") {
+					t.Errorf("reset link = %q with %d active tokens, want one token in the link", response.ResetLink, len(resetTokens))
+				}
+				if response.ExpiresAt == nil {
+					t.Error("the link method returned no expiry")
+				}
+			} else {
+				if response.Method != dto.AdminPasswordResetTemporary {
+					t.Errorf("method = %q, want %q", response.Method, dto.AdminPasswordResetTemporary)
+				}
+				if *user.Password != fakeHashPrefix+response.TemporaryPassword {
+					t.Error("the stored hash does not match the returned temporary password")
+				}
+				if response.ResetLink != "" || len(resetTokens) != 0 {
+					t.Error("the temporary password method issued a reset link")
+				}
+			}
+
+			// Solo la contraseña temporal, que alguien conoce, entra al historial
+			var wantHistory []string
+			if !tt.wantLink {
+				wantHistory = []string{*user.Password}
+			}
+			if got := uow.passwordHistory.hashes[tt.targetID]; !slices.Equal(got, wantHistory) {
+				t.Errorf("password history = %v, want %v", got, wantHistory)
+			}
+
+			if !slices.Equal(uow.refreshTokens.revokedUsers, []string{"user-1"}) || !slices.Equal(uow.sessions.revokedUsers, []string{"user-1"}) {
+				t.Errorf("revoked refresh tokens for %v and sessions for %v, want [user-1]", uow.refreshTokens.revokedUsers, uow.sessions.revokedUsers)
+			}
+			if !slices.Equal(uow.lockouts.resets, []string{"user-1"}) {
+				t.Errorf("lockout resets = %v, want [user-1]", uow.lockouts.resets)
+			}
+			if got := audit.eventTypes(); !slices.Equal(got, []string{domain.AuditPasswordAdminReset}) {
+				t.Errorf("audit events = %v, want [%s]", got, domain.AuditPasswordAdminReset)
+			}
+		})
+	}
+}
+
+func TestUserService_Impersonate(t *testing.T) {
+	const impersonatorRole = "IMPERSONATOR_ROLE"
+
+	tests := []struct {
+		name     string
+		actorID  string
+		targetID string
+		wantErr  string
+	}{
+		{name: "admin impersonates a user", actorID: "admin-1", targetID: "user-1"},
+		{name: "admin impersonates a custom role", actorID: "admin-1", targetID: "support-1"},
+		{name: "role covering the target", actorID: "support-1", targetID: "user-1"},
+		{name: "target with more permissions", actorID: "support-1", targetID: "admin-1", wantErr: dto.ErrImpersonationRoleForbidden},
+		{name: "target that can impersonate", actorID: "admin-1", targetID: "impersonator-1", wantErr: dto.ErrImpersonationAdminForbidden},
+		{name: "disabled target", actorID: "admin-1", targetID: "disabled-1", wantErr: dto.ErrAccountDisabled},
+		{name: "unknown target", actorID: "admin-1", targetID: "missing", wantErr: dto.ErrNoRowsFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, audit, service := newTestUserService(t)
+			service.config.ImpersonationTTL = 15 * time.Minute
+			uow.roles.permissions[impersonatorRole] = []string{domain.PermissionUsersImpersonate}
+			uow.users.users["impersonator-1"] = &domain.User{ID: "impersonator-1", Role: impersonatorRole, Status: true}
+			uow.users.users["disabled-1"] = &domain.User{ID: "disabled-1", Role: domain.UserRole}
+
+			response, err := service.Impersonate(context.Background(), tt.actorID, tt.targetID)
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Impersonate() error = %v, want %q", err, tt.wantErr)
+				}
+				if len(audit.events) != 0 || uow.commits != 0 || len(uow.sessions.sessions) != 0 {
+					t.Errorf("rejected impersonation recorded %v, created %d sessions and committed %d times", audit.eventTypes(), len(uow.sessions.sessions), uow.commits)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Impersonate() error = %v", err)
+			}
+
+			claims, err := service.jwtService.ValidateToken(response.Token)
+			if err != nil {
+				t.Fatalf("ValidateToken() error = %v", err)
+			}
+			if claims.UserID != tt.targetID || claims.ActorID != tt.actorID {
+				t.Errorf("claims user = %q, actor = %q, want %q, %q", claims.UserID, claims.ActorID, tt.targetID, tt.actorID)
+			}
+			if !response.ExpiresAt.Equal(claims.ExpiresAt) {
+				t.Errorf("ExpiresAt = %v, want the token expiry %v", response.ExpiresAt, claims.ExpiresAt)
+			}
+			if session := uow.sessions.sessions[claims.SessionID]; session == nil || session.UserID != tt.targetID || !session.ExpiresAt.Equal(claims.ExpiresAt) {
+				t.Errorf("session %q = %+v, want a session of %q ending with the token", claims.SessionID, session, tt.targetID)
+			}
+			if response.User.Password != nil {
+				t.Error("response exposes the password hash")
+			}
+			if got := audit.eventTypes(); !slices.Equal(got, []string{domain.AuditImpersonationStarted}) {
+				t.Errorf("audit events = %v, want %v", got, []string{domain.AuditImpersonationStarted})
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain/interfaces"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+// errUserTokenInvalid indica que el token no existe, ya fue usado o expiró.
+// Cada flujo lo traduce a su propio mensaje para el cliente.
+var errUserTokenInvalid = errors.New("user token invalid")
+
+// issueUserToken invalida los tokens pendientes del mismo propósito y persiste uno nuevo.
+// Retorna el token en texto plano para incluirlo en el enlace enviado al usuario.
+func issueUserToken(ctx context.Context, repo interfaces.UserTokenRepository, generator interfaces.TokenGenerator, userID, purpose string, ttl time.Duration) (string, error) {
+	if err := repo.InvalidateByUser(ctx, userID, purpose); err != nil {
+		return "", err
+	}
+
+	plainToken, tokenHash, err := generator.Generate()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	token := &domain.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+
+	if err := repo.Create(ctx, token); err != nil {
+		return "", err
+	}
+
+	return plainToken, nil
+}
+
+// consumeUserToken valida un token recibido del cliente y lo marca como usado junto con
+// cualquier otro token pendiente del mismo propósito para ese usuario
+func consumeUserToken(ctx context.Context, repo interfaces.UserTokenRepository, generator interfaces.TokenGenerator, purpose, plainToken string) (*domain.UserToken, error) {
+	token, err := repo.FindByHash(ctx, purpose, generator.Hash(plainToken))
+	if err != nil {
+		if err.Error() == dto.ErrNoRowsFound {
+			return nil, errUserTokenInvalid
+		}
+		return nil, err
+	}
+
+	if token.IsUsed() || token.IsExpired(time.Now()) {
+		return nil, errUserTokenInvalid
+	}
+
+	if err := repo.MarkUsed(ctx, token.ID); err != nil {
+		return nil, err
+	}
+
+	if err := repo.InvalidateByUser(ctx, token.UserID, purpose); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// renderVerificationEmail emite un token de verificación de correo y renderiza el email que lo contiene
+func renderVerificationEmail(ctx context.Context, repo interfaces.UserTokenRepository, generator interfaces.TokenGenerator, templateService interfaces.TemplateService, config AuthConfig, user *domain.User) (string, error) {
+	plainToken, err := issueUserToken(ctx, repo, generator, user.ID, domain.TokenPurposeEmailVerification, domain.EmailVerificationTokenTTL)
+	if err != nil {
+		return "", err
+	}
+
+	return templateService.RenderEmailValidation(user.Name, config.buildFrontendLink(dto.EmailVerificationPath, plainToken))
+}
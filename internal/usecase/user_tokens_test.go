@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JacobD36/appfe_frontpage_api/internal/domain"
+	"github.com/JacobD36/appfe_frontpage_api/internal/usecase/dto"
+)
+
+// userTokenCase describe el token presentado a un flujo que consume tokens de un solo uso
+type userTokenCase struct {
+	name string
+	// purpose es el propósito con el que se emitió el token; vacío no emite ninguno
+	purpose   string
+	expiresIn time.Duration
+	// consumed usa el token en un intento exitoso antes del intento evaluado
+	consumed bool
+	// prepare ajusta la cuenta de user-1 antes del intento
+	prepare func(user *domain.User)
+	wantErr string
+}
+
+// userTokenCases son los casos comunes a todos los flujos que consumen un token de user-1
+func userTokenCases(purpose, invalidErr string) []userTokenCase {
+	return []userTokenCase{
+		{name: "valid token", purpose: purpose, expiresIn: time.Hour},
+		{name: "token already used", purpose: purpose, expiresIn: time.Hour, consumed: true, wantErr: invalidErr},
+		{name: "expired token", purpose: purpose, expiresIn: -time.Minute, wantErr: invalidErr},
+		{name: "token issued for another purpose", purpose: domain.TokenPurposeMagicLink, expiresIn: time.Hour, wantErr: invalidErr},
+		{name: "unknown token", wantErr: invalidErr},
+	}
+}
+
+// disableAccount deshabilita la cuenta después de emitido el token
+func disableAccount(user *domain.User) { user.Status = false }
+
+// presentUserToken emite el token del caso con el valor "presented" y ejecuta el flujo con él
+func presentUserToken(t *testing.T, uow *fakeUnitOfWork, tt userTokenCase, consume func(token string) error) error {
+	t.Helper()
+
+	if tt.prepare != nil {
+		tt.prepare(uow.users.users["user-1"])
+	}
+
+	if tt.purpose != "" {
+		now := time.Now()
+		uow.userTokens.tokens = append(uow.userTokens.tokens, &domain.UserToken{
+			ID:        "token-presented",
+			UserID:    "user-1",
+			Purpose:   tt.purpose,
+			TokenHash: "sha:presented",
+			ExpiresAt: now.Add(tt.expiresIn),
+			CreatedAt: now,
+		})
+	}
+
+	if tt.consumed {
+		if err := consume("presented"); err != nil {
+			t.Fatalf("first use error = %v", err)
+		}
+		uow.commits = 0
+	}
+
+	return consume("presented")
+}
+
+func TestAuthService_ResetPassword(t *testing.T) {
+	tests := append(userTokenCases(domain.TokenPurposePasswordReset, dto.ErrPasswordResetTokenInvalid),
+		userTokenCase{name: "disabled account", purpose: domain.TokenPurposePasswordReset, expiresIn: time.Hour, prepare: disableAccount, wantErr: dto.ErrAccountDisabled},
+	)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, _, service := newTestAuthService(t)
+			user := uow.users.users["user-1"]
+			user.Password = stringPtr(fakeHashPrefix + testCurrentPassword)
+			user.MustChangePassword = true
+			uow.sessions.sessions["session-1"] = &domain.Session{ID: "session-1", UserID: "user-1", ExpiresAt: time.Now().Add(time.Hour)}
+
+			err := presentUserToken(t, uow, tt, func(token string) error {
+				return service.ResetPassword(context.Background(), dto.AuthResetPasswordInput{Token: token, Password: testNewPassword})
+			})
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ResetPassword() error = %v, want %q", err, tt.wantErr)
+				}
+				if uow.commits != 0 {
+					t.Errorf("rejected reset committed %d times", uow.commits)
+				}
+				if !tt.consumed && *user.Password != fakeHashPrefix+testCurrentPassword {
+					t.Error("a rejected reset replaced the password")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResetPassword() error = %v", err)
+			}
+
+			if *user.Password != fakeHashPrefix+testNewPassword || user.MustChangePassword {
+				t.Errorf("password = %q, must change = %v, want the new password without a pending change", *user.Password, user.MustChangePassword)
+			}
+			if !slices.Equal(uow.refreshTokens.revokedUsers, []string{"user-1"}) || !uow.sessions.sessions["session-1"].IsRevoked() {
+				t.Errorf("refresh revocations = %v, session revoked = %v, want every session of user-1 ended", uow.refreshTokens.revokedUsers, uow.sessions.sessions["session-1"].IsRevoked())
+			}
+			if !slices.Equal(uow.lockouts.resets, []string{"user-1"}) {
+				t.Errorf("lockout resets = %v, want [user-1]", uow.lockouts.resets)
+			}
+			if got := service.audit.(*fakeAuditService).eventTypes(); !slices.Equal(got, []string{domain.AuditPasswordReset}) {
+				t.Errorf("audit events = %v, want [%s]", got, domain.AuditPasswordReset)
+			}
+		})
+	}
+}
+
+func TestAuthService_VerifyEmail(t *testing.T) {
+	for _, tt := range userTokenCases(domain.TokenPurposeEmailVerification, dto.ErrEmailVerificationTokenInvalid) {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, _, service := newTestAuthService(t)
+			user := uow.users.users["user-1"]
+			user.EmailValidated = false
+
+			err := presentUserToken(t, uow, tt, func(token string) error {
+				return service.VerifyEmail(context.Background(), dto.AuthVerifyEmailInput{Token: token})
+			})
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("VerifyEmail() error = %v, want %q", err, tt.wantErr)
+				}
+				if uow.commits != 0 || (!tt.consumed && user.EmailValidated) {
+					t.Errorf("rejected verification committed %d times, email validated = %v", uow.commits, user.EmailValidated)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyEmail() error = %v", err)
+			}
+			if !user.EmailValidated {
+				t.Error("email was not marked as validated")
+			}
+		})
+	}
+}
+
+func TestAuthService_ResendVerificationEmail(t *testing.T) {
+	tests := []struct {
+		name string
+		// previous son las antigüedades de los enlaces ya emitidos
+		previous  []time.Duration
+		email     string
+		validated bool
+		disabled  bool
+		wantNew   bool
+	}{
+		{name: "unverified account", previous: []time.Duration{2 * time.Minute}, email: "ana@appfe.com", wantNew: true},
+		{name: "within the resend interval", previous: []time.Duration{30 * time.Second}, email: "ana@appfe.com"},
+		{name: "hourly cap", previous: []time.Duration{10 * time.Minute, 20 * time.Minute, 30 * time.Minute, 40 * time.Minute, 50 * time.Minute}, email: "ana@appfe.com"},
+		{name: "links older than an hour do not count", previous: []time.Duration{61 * time.Minute, 62 * time.Minute, 63 * time.Minute, 64 * time.Minute, 65 * time.Minute}, email: "ana@appfe.com", wantNew: true},
+		{name: "already verified", email: "ana@appfe.com", validated: true},
+		{name: "disabled account", email: "ana@appfe.com", disabled: true},
+		{name: "unknown email", email: "nadie@appfe.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, templates, service := newTestAuthService(t)
+			user := uow.users.users["user-1"]
+			user.EmailValidated = tt.validated
+			user.Status = !tt.disabled
+
+			now := time.Now()
+			for i, age := range tt.previous {
+				uow.userTokens.tokens = append(uow.userTokens.tokens, &domain.UserToken{
+					ID:        fmt.Sprintf("previous-%d", i),
+					UserID:    "user-1",
+					Purpose:   domain.TokenPurposeEmailVerification,
+					TokenHash: "sha:previous",
+					ExpiresAt: now.Add(domain.EmailVerificationTokenTTL - age),
+					CreatedAt: now.Add(-age),
+				})
+			}
+			issuedBefore := len(uow.userTokens.tokens)
+
+			if err := service.ResendVerificationEmail(context.Background(), dto.AuthResendVerificationInput{Email: tt.email}); err != nil {
+				t.Fatalf("ResendVerificationEmail() error = %v", err)
+			}
+
+			active := uow.userTokens.active(domain.TokenPurposeEmailVerification)
+			if !tt.wantNew {
+				if len(uow.userTokens.tokens) != issuedBefore || len(templates.links) != 0 {
+					t.Errorf("issued %d links and rendered %v, want none", len(uow.userTokens.tokens)-issuedBefore, templates.links)
+				}
+				return
+			}
+
+			if len(active) != 1 || active[0].TokenHash != "sha:plain-token-1" {
+				t.Fatalf("active links = %d, want only the new one", len(active))
+			}
+			if len(templates.links) != 1 || !strings.HasSuffix(templates.links[0], "plain-token-1") {
+				t.Errorf("rendered links = %v, want one ending with the plain token", templates.links)
+			}
+
+			// El enlace nuevo es de un solo uso y sirve para verificar el correo
+			verify := func() error {
+				return service.VerifyEmail(context.Background(), dto.AuthVerifyEmailInput{Token: "plain-token-1"})
+			}
+			if err := verify(); err != nil || !user.EmailValidated {
+				t.Fatalf("VerifyEmail() error = %v, email validated = %v", err, user.EmailValidated)
+			}
+			if err := verify(); err == nil || err.Error() != dto.ErrEmailVerificationTokenInvalid {
+				t.Errorf("second VerifyEmail() error = %v, want %q", err, dto.ErrEmailVerificationTokenInvalid)
+			}
+		})
+	}
+}
+
+func TestAuthService_AcceptInvitation(t *testing.T) {
+	tests := append(userTokenCases(domain.TokenPurposeInvitation, dto.ErrInvitationTokenInvalid),
+		userTokenCase{name: "disabled account", purpose: domain.TokenPurposeInvitation, expiresIn: time.Hour, prepare: disableAccount, wantErr: dto.ErrAccountDisabled},
+		userTokenCase{
+			name: "password already set", purpose: domain.TokenPurposeInvitation, expiresIn: time.Hour,
+			prepare: func(user *domain.User) { user.Password = stringPtr(fakeHashPrefix + testCurrentPassword) },
+			wantErr: dto.ErrInvitationTokenInvalid,
+		},
+	)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow, _, service := newTestAuthService(t)
+			user := uow.users.users["user-1"]
+			user.EmailValidated = false
+
+			var before *string
+			err := presentUserToken(t, uow, tt, func(token string) error {
+				before = user.Password
+				return service.AcceptInvitation(context.Background(), dto.AuthAcceptInvitationInput{Token: token, Password: testNewPassword})
+			})
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("AcceptInvitation() error = %v, want %q", err, tt.wantErr)
+				}
+				if uow.commits != 0 {
+					t.Errorf("rejected invitation committed %d times", uow.commits)
+				}
+				if !tt.consumed && (user.Password != before || user.EmailValidated) {
+					t.Error("a rejected invitation changed the account")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AcceptInvitation() error = %v", err)
+			}
+
+			if user.Password == nil || *user.Password != fakeHashPrefix+testNewPassword || !user.EmailValidated {
+				t.Errorf("password set = %v, email validated = %v, want the chosen password and a validated email", user.Password != nil, user.EmailValidated)
+			}
+			if got := service.audit.(*fakeAuditService).eventTypes(); !slices.Equal(got, []string{domain.AuditInvitationAccepted}) {
+				t.Errorf("audit events = %v, want [%s]", got, domain.AuditInvitationAccepted)
+			}
+		})
+	}
+}
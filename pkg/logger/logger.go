@@ -145,6 +145,10 @@ func (l *appLogger) logWithContext(ctx context.Context, level slog.Level, msg st
 		if userID := getUserID(ctx); userID != "" {
 			attrs = append(attrs, slog.String("user_id", userID))
 		}
+		// Durante una suplantación se registran ambos: user_id es el usuario suplantado y actor_id el administrador
+		if actorID := identityFromContext(ctx).actorID; actorID != "" {
+			attrs = append(attrs, slog.String("actor_id", actorID))
+		}
 	}
 
 	// Agregar campos adicionales
@@ -262,7 +266,26 @@ func getUserID(ctx context.Context) string {
 		return userID
 	}
 
-	return ""
+	// Usuario autenticado que agrega JWTMiddleware
+	return identityFromContext(ctx).userID
+}
+
+type identityKey struct{}
+
+type identity struct {
+	userID  string
+	actorID string
+}
+
+// WithIdentity agrega al contexto el usuario autenticado que aparece en los logs; actorID es el
+// administrador que lo suplanta y va vacío fuera de una suplantación
+func WithIdentity(ctx context.Context, userID, actorID string) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity{userID: userID, actorID: actorID})
+}
+
+func identityFromContext(ctx context.Context) identity {
+	id, _ := ctx.Value(identityKey{}).(identity)
+	return id
 }
 
 // Instancia global del logger